@@ -0,0 +1,260 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+)
+
+// methods are the HTTP methods offered for completion; others are accepted too
+var methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// NewCommand returns the api command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api <method> <path>",
+		Short: "Make an authenticated request to any Spot API endpoint",
+		Long: `Make an authenticated request to any Rackspace Spot API endpoint, including
+ones spotctl has no command for yet, and print the response.
+
+The path is relative to the API version, ngpc.rxt.io/v1 unless the global
+--api-version is given, or a full path starting with /apis/<group>/<version>/. The refresh
+token, User-Agent, retries and error handling are the same as for every other
+command, and an API error exits with a non-zero status.
+
+The request body is given with --data, either inline, from a file with @file
+or from standard input with @-. JSON responses are pretty-printed; use -o yaml
+or -o jsonpath=... to reformat them.
+
+Examples:
+  # List the regions
+  spotctl api GET /regions
+
+  # Every cloudspace in a namespace, following continue tokens
+  spotctl api GET /namespaces/org-abc123/cloudspaces --paginate
+
+  # Names of the organizations
+  spotctl api GET /organizations --api-version auth.ngpc.rxt.io/v1 -o jsonpath='{.organizations[*].name}'
+
+  # Create a spot node pool from a file
+  spotctl api POST /namespaces/org-abc123/spotnodepools -d @pool.json
+
+  # Patch a node pool with a JSON patch from standard input
+  echo '[{"op":"replace","path":"/spec/desired","value":3}]' |
+    spotctl api PATCH /namespaces/org-abc123/spotnodepools/my-pool -d @- -H 'Content-Type: application/json-patch+json'`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeMethod,
+		RunE:              runAPI,
+	}
+
+	cmd.Flags().StringP("data", "d", "", "JSON request body, @file to read it from a file or @- for standard input")
+	cmd.Flags().StringArrayP("header", "H", nil, "Extra request header as 'Key: Value'; repeat for more")
+	cmd.Flags().Bool("paginate", false, "Follow continue tokens of a GET list response and print every item as one list")
+	cmd.Flags().StringP("output", "o", "json", "Output format (json, yaml, jsonpath=...)")
+
+	return cmd
+}
+
+func runAPI(cmd *cobra.Command, args []string) error {
+	method := strings.ToUpper(args[0])
+	path, pathVersion := splitAPIPath(args[1])
+	paginate, _ := cmd.Flags().GetBool("paginate")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if paginate && method != http.MethodGet {
+		return fmt.Errorf("--paginate can only be used with GET")
+	}
+
+	data, _ := cmd.Flags().GetString("data")
+	body, err := readBody(data, cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+
+	var opts []client.Option
+	headers, _ := cmd.Flags().GetStringArray("header")
+	for _, header := range headers {
+		key, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid header %q: expected 'Key: Value'", header)
+		}
+		opts = append(opts, client.WithHeader(strings.TrimSpace(key), strings.TrimSpace(value)))
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	version := client.APIVersionDefault
+	if cfg.APIVersion != "" {
+		if version, err = client.ParseAPIVersion(cfg.APIVersion); err != nil {
+			return err
+		}
+	}
+	if pathVersion != "" {
+		// A full path is sent as given, whatever --api-version says
+		version = pathVersion
+		opts = append(opts, client.WithAPIVersion(""))
+	}
+	apiClient := client.NewClient(cfg, opts...)
+
+	var response []byte
+	if paginate {
+		response, err = getAllPages(cmd, apiClient, path, version)
+	} else {
+		response, err = request(cmd, apiClient, method, path, body, version)
+	}
+	if err != nil {
+		return err
+	}
+	return printResponse(cmd.OutOrStdout(), response, outputFormat)
+}
+
+// splitAPIPath returns the path relative to its API version and, for a full
+// /apis/<group>/<version>/ path, that version
+func splitAPIPath(path string) (string, client.APIVersion) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if rest, ok := strings.CutPrefix(path, "/apis/"); ok {
+		if parts := strings.SplitN(rest, "/", 3); len(parts) == 3 {
+			return "/" + parts[2], client.APIVersion(parts[0] + "/" + parts[1])
+		}
+	}
+	return path, ""
+}
+
+// readBody returns the request body given with --data, or nil without one
+func readBody(data string, stdin io.Reader) (any, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	raw := []byte(data)
+	if name, ok := strings.CutPrefix(data, "@"); ok {
+		var err error
+		if name == "-" {
+			raw, err = io.ReadAll(stdin)
+		} else {
+			raw, err = os.ReadFile(name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+	if !json.Valid(raw) {
+		return nil, fmt.Errorf("request body is not valid JSON")
+	}
+	return json.RawMessage(raw), nil
+}
+
+// request sends one request and returns the response body
+func request(cmd *cobra.Command, apiClient *client.Client, method, path string, body any, version client.APIVersion) ([]byte, error) {
+	resp, err := apiClient.MakeRequest(cmd.Context(), method, path, body, version)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	response, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return response, nil
+}
+
+// getAllPages gets a Kubernetes style list and the pages after it, returning the
+// first page with the items of every page
+func getAllPages(cmd *cobra.Command, apiClient *client.Client, path string, version client.APIVersion) ([]byte, error) {
+	var list map[string]any
+	var items []any
+	pagePath := path
+	for {
+		response, err := request(cmd, apiClient, http.MethodGet, pagePath, nil, version)
+		if err != nil {
+			return nil, err
+		}
+		var page map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(response))
+		decoder.UseNumber()
+		if err := decoder.Decode(&page); err != nil {
+			return nil, fmt.Errorf("--paginate needs a JSON object response: %w", err)
+		}
+		pageItems, ok := page["items"].([]any)
+		if !ok {
+			return nil, fmt.Errorf("--paginate needs a list response with items")
+		}
+		if list == nil {
+			list = page
+		}
+		items = append(items, pageItems...)
+
+		metadata, _ := page["metadata"].(map[string]any)
+		next, _ := metadata["continue"].(string)
+		if next == "" {
+			break
+		}
+		nextPath, err := withContinue(path, next)
+		if err != nil {
+			return nil, err
+		}
+		if nextPath == pagePath {
+			return nil, fmt.Errorf("pagination did not advance past continue token %q", next)
+		}
+		pagePath = nextPath
+	}
+
+	list["items"] = items
+	if metadata, ok := list["metadata"].(map[string]any); ok {
+		delete(metadata, "continue")
+	}
+	return json.Marshal(list)
+}
+
+// withContinue sets the continue query parameter of path
+func withContinue(path, token string) (string, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	query := u.Query()
+	query.Set("continue", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// printResponse pretty-prints a JSON response in the output format and writes
+// anything else as it is
+func printResponse(w io.Writer, response []byte, format string) error {
+	if len(bytes.TrimSpace(response)) == 0 {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(response))
+	decoder.UseNumber()
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		_, err := w.Write(response)
+		return err
+	}
+
+	formatter := output.NewFormatter(output.OutputOptions{Format: output.OutputFormat(format)})
+	return formatter.OutputToWriter(w, data, nil)
+}
+
+// completeMethod completes the HTTP method
+func completeMethod(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return methods, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+	"github.com/spf13/viper"
+)
+
+func TestSplitAPIPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantPath string
+		wantVers client.APIVersion
+	}{
+		{path: "/regions", wantPath: "/regions"},
+		{path: "regions", wantPath: "/regions"},
+		{path: "/apis/auth.ngpc.rxt.io/v1/organizations", wantPath: "/organizations", wantVers: client.APIVersionAuth},
+	}
+	for _, tt := range tests {
+		path, apiVersion := splitAPIPath(tt.path)
+		if path != tt.wantPath || apiVersion != tt.wantVers {
+			t.Errorf("splitAPIPath(%q) = %q, %q, want %q, %q", tt.path, path, apiVersion, tt.wantPath, tt.wantVers)
+		}
+	}
+}
+
+func TestAPI(t *testing.T) {
+	server := spottest.NewTestServer(t)
+	server.Configure(t)
+
+	run := func(stdin string, args ...string) (string, error) {
+		cmd := NewCommand()
+		var out bytes.Buffer
+		cmd.SetArgs(args)
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("", "get", "/regions/uk-lon-1")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if !strings.Contains(out, "\n  \"metadata\": {") || !strings.Contains(out, `"name": "uk-lon-1"`) {
+		t.Errorf("Expected pretty-printed JSON, got:\n%s", out)
+	}
+
+	out, err = run("", "GET", "/regions?limit=1", "--paginate", "-o", "jsonpath={.items[*].metadata.name}")
+	if err != nil {
+		t.Fatalf("GET --paginate failed: %v", err)
+	}
+	if got := strings.Fields(out); len(got) != 3 {
+		t.Errorf("Expected the regions of every page, got %q", out)
+	}
+
+	out, err = run("", "GET", "/apis/auth.ngpc.rxt.io/v1/organizations", "-o", "jsonpath={.organizations[0].name}")
+	if err != nil || strings.TrimSpace(out) != "spottest" {
+		t.Errorf("Expected the organization from the auth API, got %q (%v)", out, err)
+	}
+
+	// Paths are relative to --api-version
+	viper.Set("api-version", client.APIVersionAuth.String())
+	out, err = run("", "GET", "/organizations", "-o", "jsonpath={.organizations[0].name}")
+	viper.Set("api-version", "")
+	if err != nil || strings.TrimSpace(out) != "spottest" {
+		t.Errorf("Expected the organization from --api-version, got %q (%v)", out, err)
+	}
+
+	body := filepath.Join(t.TempDir(), "cloudspace.json")
+	os.WriteFile(body, []byte(`{"apiVersion":"ngpc.rxt.io/v1","kind":"CloudSpace","metadata":{"name":"from-api"},"spec":{"region":"uk-lon-1"}}`), 0o600)
+	if _, err := run("", "POST", "/namespaces/"+spottest.DefaultNamespace+"/cloudspaces", "-d", "@"+body); err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	patch := `[{"op":"add","path":"/metadata/labels","value":{"team":"web"}}]`
+	out, err = run(patch, "PATCH", "/namespaces/"+spottest.DefaultNamespace+"/cloudspaces/from-api", "-d", "@-",
+		"-H", "Content-Type: application/json-patch+json", "-o", "yaml")
+	if err != nil {
+		t.Fatalf("PATCH failed: %v", err)
+	}
+	if !strings.Contains(out, "team: web") {
+		t.Errorf("Expected the patched cloudspace as YAML, got:\n%s", out)
+	}
+
+	if _, err := run("", "GET", "/regions/does-not-exist"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected a 404 API error, got %v", err)
+	}
+	if _, err := run("", "POST", "/regions", "-d", "{not json"); err == nil {
+		t.Error("Expected an invalid body to be rejected")
+	}
+	if _, err := run("", "POST", "/regions", "--paginate"); err == nil {
+		t.Error("Expected --paginate to require GET")
+	}
+	if _, err := run("", "GET", "/regions", "-H", "no-colon"); err == nil {
+		t.Error("Expected an invalid header to be rejected")
+	}
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// NewCommand returns the main auth command with all subcommands
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage Rackspace Spot credentials",
+		Long: `Log in and out of Rackspace Spot and inspect the current credentials.
+
+spotctl authenticates with a refresh token from the Rackspace Spot console
+(API Access > Terraform > Get New Token), which it exchanges for short-lived
+ID tokens used as bearer tokens for API requests.`,
+	}
+
+	// Add all subcommands
+	cmd.AddCommand(NewLoginCommand())
+	cmd.AddCommand(NewWhoamiCommand())
+	cmd.AddCommand(NewLogoutCommand())
+	cmd.AddCommand(NewPrintTokenCommand())
+
+	return cmd
+}
+
+// readRefreshToken reads a refresh token from in, without echoing it when in is a terminal
+func readRefreshToken(in io.Reader, prompt io.Writer) (string, error) {
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprint(prompt, "Enter your Rackspace Spot refresh token: ")
+		token, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(prompt)
+		if err != nil {
+			return "", fmt.Errorf("failed to read refresh token: %w", err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read refresh token: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
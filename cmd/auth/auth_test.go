@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+	"github.com/spf13/viper"
+)
+
+func TestReadRefreshToken(t *testing.T) {
+	token, err := readRefreshToken(strings.NewReader("  my-token \nignored\n"), &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("readRefreshToken failed: %v", err)
+	}
+	if token != "my-token" {
+		t.Errorf("Expected my-token, got %q", token)
+	}
+
+	if token, _ := readRefreshToken(strings.NewReader(""), &bytes.Buffer{}); token != "" {
+		t.Errorf("Expected no token from empty input, got %q", token)
+	}
+}
+
+func TestDescribeIdentity(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	id := &identity{
+		Subject:       "auth0|123",
+		Email:         "dev@example.com",
+		ExpiresAt:     now.Add(30 * time.Minute),
+		Organizations: []string{"acme", "globex"},
+	}
+
+	var buf bytes.Buffer
+	if err := describeIdentity(&buf, id, now); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Subject:", "auth0|123", "dev@example.com", "(in 30m0s)", "acme", "globex", "Issuer:         <none>"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	id.ExpiresAt = now.Add(-time.Minute)
+	describeIdentity(&buf, id, now)
+	if !strings.Contains(buf.String(), "(expired)") {
+		t.Errorf("Expected an expired token to be marked, got:\n%s", buf.String())
+	}
+}
+
+// TestLoginWhoamiLogout logs in against the fake API, checks the saved
+// credentials and the reported identity, then logs out again
+func TestLoginWhoamiLogout(t *testing.T) {
+	server := spottest.NewTestServer(t)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("SPOTCTL_CONFIG", configPath)
+	t.Setenv("SPOTCTL_REFRESH_TOKEN", "")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := server.Config()
+	viper.Set("base-url", cfg.BaseURL)
+	viper.Set("oauth-url", cfg.OAuthURL)
+	t.Cleanup(viper.Reset)
+
+	run := func(stdin string, args ...string) (string, error) {
+		cmd := NewCommand()
+		var out bytes.Buffer
+		cmd.SetArgs(args)
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run("wrong-token\n", "login"); err == nil {
+		t.Error("Expected login with an unknown refresh token to fail")
+	}
+	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+		t.Error("Expected a rejected token not to be saved")
+	}
+
+	out, err := run(spottest.DefaultRefreshToken+"\n", "login")
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if !strings.Contains(out, "Logged in as "+spottest.DefaultEmail) || !strings.Contains(out, "Organizations: spottest") {
+		t.Errorf("Unexpected login output:\n%s", out)
+	}
+	saved, err := os.ReadFile(configPath)
+	if err != nil || !strings.Contains(string(saved), spottest.DefaultRefreshToken) {
+		t.Fatalf("Expected the refresh token to be saved, got %q (%v)", saved, err)
+	}
+
+	out, err = run("", "whoami", "-o", "json")
+	if err != nil {
+		t.Fatalf("whoami failed: %v", err)
+	}
+	if !strings.Contains(out, `"subject": "`+spottest.DefaultSubject+`"`) || !strings.Contains(out, `"spottest"`) {
+		t.Errorf("Unexpected whoami output:\n%s", out)
+	}
+
+	out, err = run("", "print-token")
+	if err != nil {
+		t.Fatalf("print-token failed: %v", err)
+	}
+	if strings.Count(strings.TrimSpace(out), ".") != 2 {
+		t.Errorf("Expected a JWT, got %q", out)
+	}
+
+	out, err = run("", "print-token", "--exec-credential")
+	if err != nil {
+		t.Fatalf("print-token --exec-credential failed: %v", err)
+	}
+	var credential execCredential
+	if err := json.Unmarshal([]byte(out), &credential); err != nil || credential.Kind != "ExecCredential" || credential.Status.Token == "" {
+		t.Errorf("Expected an ExecCredential with a token, got %q (%v)", out, err)
+	}
+	if _, err := time.Parse(time.RFC3339, credential.Status.ExpirationTimestamp); err != nil {
+		t.Errorf("Expected the token's expiry, got %q", credential.Status.ExpirationTimestamp)
+	}
+
+	if _, err := run("", "logout"); err != nil {
+		t.Fatalf("logout failed: %v", err)
+	}
+	saved, _ = os.ReadFile(configPath)
+	if strings.Contains(string(saved), spottest.DefaultRefreshToken) {
+		t.Errorf("Expected the refresh token to be removed, got:\n%s", saved)
+	}
+	if _, err := run("", "whoami"); err == nil {
+		t.Error("Expected whoami to fail after logout")
+	}
+}
+
+// TestLoginRotatedRefreshToken checks that login saves the refresh token issued
+// by a server with rotation, not the one it was given, which no longer works
+func TestLoginRotatedRefreshToken(t *testing.T) {
+	server := spottest.NewTestServer(t, spottest.WithRefreshTokenRotation())
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("SPOTCTL_CONFIG", configPath)
+	t.Setenv("SPOTCTL_REFRESH_TOKEN", "")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := server.Config()
+	viper.Set("base-url", cfg.BaseURL)
+	viper.Set("oauth-url", cfg.OAuthURL)
+	t.Cleanup(viper.Reset)
+
+	cmd := NewCommand()
+	var out bytes.Buffer
+	cmd.SetArgs([]string{"login"})
+	cmd.SetIn(strings.NewReader(spottest.DefaultRefreshToken + "\n"))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("login failed: %v\n%s", err, out.String())
+	}
+	if strings.Contains(out.String(), "not read from the config file") {
+		t.Errorf("Expected no warning about the rotated token, got:\n%s", out.String())
+	}
+
+	saved, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current := server.RefreshToken(); current == spottest.DefaultRefreshToken || !strings.Contains(string(saved), "refresh-token: "+current+"\n") {
+		t.Errorf("Expected the rotated refresh token %q to be saved, got:\n%s", current, saved)
+	}
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewLoginCommand creates the auth login command
+func NewLoginCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in with a refresh token",
+		Long: `Log in with a Rackspace Spot refresh token and save it to the config file.
+
+The token is read from --refresh-token or prompted for, then checked by
+exchanging it for an ID token and listing your organizations before it is saved.
+
+With --device no token needs to be copied: spotctl shows a URL and a code to
+enter in a browser on any device, waits for the login to be approved and saves
+the refresh token it is issued (OAuth device authorization grant). The
+authorization server is the one serving oauth-url, or device-auth-url if set.
+
+Examples:
+  # Prompt for the token
+  spotctl auth login
+
+  # Read the token from a file or secret manager
+  spotctl auth login < token.txt
+
+  # Log in through a browser, e.g. from a headless machine
+  spotctl auth login --device`,
+		Args: cobra.NoArgs,
+		RunE: runLogin,
+	}
+
+	cmd.Flags().Bool("device", false, "Log in through a browser on any device instead of pasting a refresh token")
+
+	return cmd
+}
+
+func runLogin(cmd *cobra.Command, args []string) error {
+	device, _ := cmd.Flags().GetBool("device")
+	refreshToken, _ := cmd.Flags().GetString("refresh-token")
+	var err error
+	switch {
+	case device:
+		if refreshToken, err = deviceLogin(cmd); err != nil {
+			return err
+		}
+	case refreshToken == "":
+		if refreshToken, err = readRefreshToken(cmd.InOrStdin(), cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+	}
+	if refreshToken == "" {
+		return fmt.Errorf("refresh token is required")
+	}
+
+	viper.Set("refresh-token", refreshToken)
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	// With refresh token rotation the exchange below invalidates the token given,
+	// so the one saved is whichever the token manager holds afterwards
+	cfg.StoreRefreshToken = func(refreshToken string) error {
+		cfg.RefreshToken = refreshToken
+		return nil
+	}
+
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+	token, err := apiClient.AccessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("refresh token was rejected: %w", err)
+	}
+	orgList, err := apiClient.ListOrganizations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list organizations with the new token: %w", err)
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return err
+	}
+	path, err := config.FilePath()
+	if err != nil {
+		return err
+	}
+
+	if claims, err := client.ParseIDToken(token); err == nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "Logged in as %s\n", displayName(claims))
+	} else {
+		fmt.Fprintln(cmd.OutOrStdout(), "Logged in")
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Refresh token saved to %s\n", path)
+	if names := organizationNames(orgList.Organizations); len(names) > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Organizations: %s\n", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// deviceLogin runs the device authorization flow and returns the issued refresh token
+func deviceLogin(cmd *cobra.Command) (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	flow := client.NewClient(cfg).DeviceFlow()
+	ctx := cmd.Context()
+	auth, err := flow.Start(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "To log in, open %s and enter the code %s\n", auth.VerificationURI, auth.UserCode)
+	if auth.VerificationURIComplete != "" {
+		fmt.Fprintf(out, "or open %s\n", auth.VerificationURIComplete)
+	}
+	fmt.Fprintln(out, "Waiting for the login to be approved...")
+
+	token, err := flow.Poll(ctx, auth)
+	if err != nil {
+		return "", err
+	}
+	return token.RefreshToken, nil
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/georgetaylor/spotctl/pkg/cache"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewLogoutCommand creates the auth logout command
+func NewLogoutCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Remove stored credentials",
+		Long: `Remove the refresh token from the config file and clear spotctl's caches,
+which hold data fetched with it such as organization namespaces and completion
+results. Other settings in the config file are kept.
+
+The refresh token itself stays valid until it is revoked in the Rackspace Spot
+console.`,
+		Args: cobra.NoArgs,
+		RunE: runLogout,
+	}
+
+	return cmd
+}
+
+func runLogout(cmd *cobra.Command, args []string) error {
+	if err := config.ClearRefreshToken(); err != nil {
+		return fmt.Errorf("failed to remove refresh token: %w", err)
+	}
+	if err := cache.ClearAll(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "Logged out: refresh token removed and cached data cleared")
+	if os.Getenv("SPOTCTL_REFRESH_TOKEN") != "" {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: SPOTCTL_REFRESH_TOKEN is still set in your environment")
+	}
+	return nil
+}
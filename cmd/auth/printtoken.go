@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/kubeconfig"
+	"github.com/spf13/cobra"
+)
+
+// NewPrintTokenCommand creates the auth print-token command
+func NewPrintTokenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "print-token",
+		Short: "Print a bearer token for the API",
+		Long: `Print a currently valid ID token for use as a bearer token with other tools.
+
+The token is short-lived and grants the same access as your refresh token, so
+avoid saving it or pasting it into logs.
+
+With --exec-credential the token is printed as a Kubernetes ExecCredential, for
+kubectl to run whenever it needs a token. Kubeconfigs written by
+'spotctl cloudspaces kubeconfig' do this.
+
+Examples:
+  curl -H "Authorization: Bearer $(spotctl auth print-token)" \
+    https://spot.rackspace.com/apis/ngpc.rxt.io/v1/regions`,
+		Args: cobra.NoArgs,
+		RunE: runPrintToken,
+	}
+
+	cmd.Flags().Bool("exec-credential", false, "Print the token as a Kubernetes ExecCredential, as kubectl expects of exec users")
+
+	return cmd
+}
+
+// execCredential is the ExecCredential kubectl reads from exec users
+type execCredential struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Status     execCredentialStatus `json:"status"`
+}
+
+type execCredentialStatus struct {
+	Token string `json:"token"`
+	// ExpirationTimestamp lets kubectl reuse the token until it expires
+	ExpirationTimestamp string `json:"expirationTimestamp,omitempty"`
+}
+
+func runPrintToken(cmd *cobra.Command, args []string) error {
+	asExecCredential, _ := cmd.Flags().GetBool("exec-credential")
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	token, err := client.NewClient(cfg).AccessToken(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get an ID token: %w", err)
+	}
+
+	if !asExecCredential {
+		fmt.Fprintln(cmd.OutOrStdout(), token)
+		return nil
+	}
+
+	credential := execCredential{
+		APIVersion: kubeconfig.ExecAPIVersion,
+		Kind:       "ExecCredential",
+		Status:     execCredentialStatus{Token: token},
+	}
+	if claims, err := client.ParseIDToken(token); err == nil && !claims.ExpiresAt().IsZero() {
+		credential.Status.ExpirationTimestamp = claims.ExpiresAt().UTC().Format(time.RFC3339)
+	}
+	return json.NewEncoder(cmd.OutOrStdout()).Encode(credential)
+}
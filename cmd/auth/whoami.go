@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+// NewWhoamiCommand creates the auth whoami command
+func NewWhoamiCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show who the current credentials belong to",
+		Long: `Show the identity behind the configured refresh token.
+
+The refresh token is exchanged for an ID token whose claims are shown: the
+subject, email, expiry and organizations. Organizations not named in the token
+are looked up through the API.
+
+Examples:
+  spotctl auth whoami
+  spotctl auth whoami -o json`,
+		Args: cobra.NoArgs,
+		RunE: runWhoami,
+	}
+
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// identity is the output of whoami
+type identity struct {
+	Subject       string    `json:"subject" yaml:"subject"`
+	Email         string    `json:"email,omitempty" yaml:"email,omitempty"`
+	Name          string    `json:"name,omitempty" yaml:"name,omitempty"`
+	Issuer        string    `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	ExpiresAt     time.Time `json:"expiresAt" yaml:"expiresAt"`
+	Organizations []string  `json:"organizations" yaml:"organizations"`
+}
+
+func runWhoami(cmd *cobra.Command, args []string) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+	token, err := apiClient.AccessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get an ID token: %w", err)
+	}
+	claims, err := client.ParseIDToken(token)
+	if err != nil {
+		return err
+	}
+
+	id := &identity{
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		Name:      claims.Name,
+		Issuer:    claims.Issuer,
+		ExpiresAt: claims.ExpiresAt(),
+	}
+	switch {
+	case claims.OrgName != "":
+		id.Organizations = []string{claims.OrgName}
+	case claims.OrgID != "":
+		id.Organizations = []string{claims.OrgID}
+	default:
+		orgList, err := apiClient.ListOrganizations(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list organizations: %w", err)
+		}
+		id.Organizations = organizationNames(orgList.Organizations)
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat == string(output.TableFormat) {
+		return describeIdentity(cmd.OutOrStdout(), id, time.Now())
+	}
+	formatter := output.NewFormatter(output.OutputOptions{Format: output.OutputFormat(outputFormat)})
+	return formatter.OutputToWriter(cmd.OutOrStdout(), id, nil)
+}
+
+// describeIdentity renders id for people
+func describeIdentity(w io.Writer, id *identity, now time.Time) error {
+	d := output.NewDescribeWriter(w)
+	d.Field(0, "Subject", id.Subject)
+	d.Field(0, "Email", id.Email)
+	if id.Name != "" {
+		d.Field(0, "Name", id.Name)
+	}
+	d.Field(0, "Issuer", id.Issuer)
+	expires := ""
+	if !id.ExpiresAt.IsZero() {
+		expires = fmt.Sprintf("%s (in %s)", id.ExpiresAt.Local().Format(time.RFC3339), id.ExpiresAt.Sub(now).Round(time.Second))
+		if !id.ExpiresAt.After(now) {
+			expires = fmt.Sprintf("%s (expired)", id.ExpiresAt.Local().Format(time.RFC3339))
+		}
+	}
+	d.Field(0, "Token Expires", expires)
+	d.List(0, "Organizations", id.Organizations)
+	return d.Flush()
+}
+
+// displayName names the user behind claims as specifically as the claims allow
+func displayName(claims *client.Claims) string {
+	switch {
+	case claims.Email != "":
+		return claims.Email
+	case claims.Name != "":
+		return claims.Name
+	default:
+		return claims.Subject
+	}
+}
+
+// organizationNames returns the names of organizations, falling back to their IDs
+func organizationNames(organizations []client.Organization) []string {
+	names := make([]string, 0, len(organizations))
+	for _, org := range organizations {
+		if org.Name != "" {
+			names = append(names, org.Name)
+		} else {
+			names = append(names, org.ID)
+		}
+	}
+	return names
+}
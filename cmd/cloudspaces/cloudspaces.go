@@ -1,6 +1,8 @@
 package cloudspaces
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/spf13/cobra"
+)
 
 // NewCommand returns the main cloudspaces command with all subcommands
 func NewCommand() *cobra.Command {
@@ -16,9 +18,12 @@ Cloudspaces represent Kubernetes clusters deployed through Rackspace Spot.`,
 	// Add all subcommands
 	cmd.AddCommand(NewListCommand())
 	cmd.AddCommand(NewGetCommand())
+	cmd.AddCommand(NewDescribeCommand())
+	cmd.AddCommand(NewTreeCommand())
 	cmd.AddCommand(NewCreateCommand())
 	cmd.AddCommand(NewEditCommand())
 	cmd.AddCommand(NewDeleteCommand())
+	cmd.AddCommand(NewKubeconfigCommand())
 
 	return cmd
 }
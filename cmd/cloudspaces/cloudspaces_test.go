@@ -3,15 +3,23 @@ package cloudspaces
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
-	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // MockCloudSpaceClient implements a simple mock for testing cloudspaces command business logic
@@ -214,7 +222,7 @@ func TestCloudspacesListCommand(t *testing.T) {
 }
 
 func TestGetCloudSpacesTableConfig(t *testing.T) {
-	config := getCloudSpacesTableConfig()
+	config := registry.CloudSpaces.TableConfig()
 
 	if config == nil {
 		t.Fatal("Expected table config but got nil")
@@ -595,7 +603,7 @@ func TestCloudspacesEditCommand(t *testing.T) {
 			args: []string{"test-cloudspace"},
 			flags: map[string]string{
 				"namespace": "test-namespace",
-				"file":      validPatchFile,
+				"patch":     validPatchFile,
 				"confirm":   "true",
 				"output":    "table",
 			},
@@ -608,7 +616,7 @@ func TestCloudspacesEditCommand(t *testing.T) {
 			args: []string{"test-cloudspace"},
 			flags: map[string]string{
 				"namespace": "test-namespace",
-				"file":      validPatchFile,
+				"patch":     validPatchFile,
 				"confirm":   "true",
 				"output":    "json",
 			},
@@ -621,7 +629,7 @@ func TestCloudspacesEditCommand(t *testing.T) {
 			args: []string{"test-cloudspace"},
 			flags: map[string]string{
 				"namespace": "test-namespace",
-				"file":      emptyPatchFile,
+				"patch":     emptyPatchFile,
 				"confirm":   "true",
 			},
 			mockError:   nil,
@@ -632,14 +640,14 @@ func TestCloudspacesEditCommand(t *testing.T) {
 			name: "missing namespace flag",
 			args: []string{"test-cloudspace"},
 			flags: map[string]string{
-				"file":    validPatchFile,
+				"patch":   validPatchFile,
 				"confirm": "true",
 			},
 			expectError: false, // namespace can come from config
 			expectOut:   []string{"Applying 1 patch operation(s)"},
 		},
 		{
-			name: "missing file flag",
+			name: "missing patch flag",
 			args: []string{"test-cloudspace"},
 			flags: map[string]string{
 				"namespace": "test-namespace",
@@ -651,7 +659,7 @@ func TestCloudspacesEditCommand(t *testing.T) {
 			args: []string{},
 			flags: map[string]string{
 				"namespace": "test-namespace",
-				"file":      validPatchFile,
+				"patch":     validPatchFile,
 			},
 			expectError: true,
 		},
@@ -660,7 +668,7 @@ func TestCloudspacesEditCommand(t *testing.T) {
 			args: []string{"test-cloudspace"},
 			flags: map[string]string{
 				"namespace": "test-namespace",
-				"file":      "/nonexistent/file.json",
+				"patch":     "/nonexistent/file.json",
 				"confirm":   "true",
 			},
 			expectError: true,
@@ -670,7 +678,7 @@ func TestCloudspacesEditCommand(t *testing.T) {
 			args: []string{"test-cloudspace"},
 			flags: map[string]string{
 				"namespace": "test-namespace",
-				"file":      invalidPatchFile,
+				"patch":     invalidPatchFile,
 				"confirm":   "true",
 			},
 			expectError: true,
@@ -680,7 +688,7 @@ func TestCloudspacesEditCommand(t *testing.T) {
 			args: []string{"test-cloudspace"},
 			flags: map[string]string{
 				"namespace": "test-namespace",
-				"file":      validPatchFile,
+				"patch":     validPatchFile,
 				"confirm":   "true",
 			},
 			mockError:   errors.New("API error: cloudspace not found"),
@@ -712,28 +720,17 @@ func TestCloudspacesEditCommand(t *testing.T) {
 				if namespace == "" {
 					namespace = "default-test-namespace"
 				}
-				file, _ := cmd.Flags().GetString("file")
+				if len(args) == 0 {
+					return errors.New("no cloudspaces given")
+				}
 				outputFormat, _ := cmd.Flags().GetString("output")
 
-				// Load the JSON patch operations from the file
-				patchOps, err := client.LoadPatchOperations(file)
+				// Load the JSON patch operations from --patch
+				patchOps, err := cmdutil.LoadPatch(cmd)
 				if err != nil {
 					return err
 				}
-
-				// Capture displayPatchOperations output
-				old := os.Stdout
-				r, w, _ := os.Pipe()
-				os.Stdout = w
-
-				// Display the patch operations that will be applied
-				client.DisplayPatchOperations(patchOps)
-
-				// Restore stdout and capture the output
-				w.Close()
-				os.Stdout = old
-				output, _ := io.ReadAll(r)
-				stdoutBuf.Write(output)
+				client.WritePatchOperations(&stdoutBuf, patchOps)
 
 				// Skip confirmation since we set confirm=true in flags or this is a test
 				skipConfirmation, _ := cmd.Flags().GetBool("confirm")
@@ -790,3 +787,434 @@ func TestCloudspacesEditCommand(t *testing.T) {
 		})
 	}
 }
+
+// newStandInServer starts a local stand-in for the OAuth and Spot APIs
+func newStandInServer(t *testing.T, cloudspaceBody string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			if err := r.ParseForm(); err != nil || r.Form.Get("refresh_token") != "test-refresh-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"id_token":"stand-in-id-token","expires_in":86400,"token_type":"Bearer"}`))
+		case "/apis/ngpc.rxt.io/v1/namespaces/org-abc123/cloudspaces/my-cloudspace":
+			if r.Header.Get("Authorization") != "Bearer stand-in-id-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"message":"unauthorized"}`))
+				return
+			}
+			w.Write([]byte(cloudspaceBody))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBuildKubeconfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		cloudspaceBody string
+		cloudspaceName string
+		expectError    bool
+	}{
+		{
+			name:           "ready cloudspace",
+			cloudspaceBody: `{"metadata":{"name":"my-cloudspace","namespace":"org-abc123"},"status":{"APIServerEndpoint":"hcp-123.spot.rackspace.com","phase":"Ready"}}`,
+			cloudspaceName: "my-cloudspace",
+		},
+		{
+			name:           "cloudspace still provisioning",
+			cloudspaceBody: `{"metadata":{"name":"my-cloudspace","namespace":"org-abc123"},"status":{"phase":"Provisioning"}}`,
+			cloudspaceName: "my-cloudspace",
+			expectError:    true,
+		},
+		{
+			name:           "cloudspace not found",
+			cloudspaceName: "missing",
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStandInServer(t, tt.cloudspaceBody)
+			apiClient := client.NewClient(&config.Config{
+				RefreshToken: "test-refresh-token",
+				BaseURL:      server.URL + "/apis",
+				OAuthURL:     server.URL + "/oauth/token",
+				Timeout:      5,
+			})
+
+			cmd := NewKubeconfigCommand()
+			cmd.SetContext(context.Background())
+			user, err := kubeconfigUser(cmd, apiClient, true)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			cfg, err := buildKubeconfig(context.Background(), apiClient, "org-abc123", tt.cloudspaceName, "spot-test", user, false)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if cfg.CurrentContext != "spot-test" {
+				t.Errorf("Expected current context spot-test, got %q", cfg.CurrentContext)
+			}
+			if cfg.Users[0].User["token"] != "stand-in-id-token" {
+				t.Errorf("Expected stand-in token, got %v", cfg.Users[0].User["token"])
+			}
+			if cfg.Clusters[0].Cluster["server"] != "https://hcp-123.spot.rackspace.com" {
+				t.Errorf("Expected server https://hcp-123.spot.rackspace.com, got %v", cfg.Clusters[0].Cluster["server"])
+			}
+		})
+	}
+}
+
+func TestKubeconfigUser(t *testing.T) {
+	cmd := NewKubeconfigCommand()
+	cmd.Flags().String("config", "", "")
+	cmd.Flags().Set("config", "spot.yaml")
+
+	user, err := kubeconfigUser(cmd, nil, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	exec, _ := user["exec"].(map[string]interface{})
+	args, _ := exec["args"].([]string)
+	if exec["command"] != "spotctl" || len(args) != 5 || args[1] != "print-token" || !filepath.IsAbs(args[4]) {
+		t.Errorf("Expected spotctl auth print-token with an absolute --config, got %+v", user)
+	}
+
+	var buf bytes.Buffer
+	now := time.Unix(1700000000, 0)
+	token := "e30." + base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1700003600}`)) + ".sig"
+	warnTokenExpiry(&buf, token, now)
+	if !strings.Contains(buf.String(), "(in 1h0m0s)") {
+		t.Errorf("Expected the time left on the token, got %q", buf.String())
+	}
+}
+
+func TestDescribeCloudSpace(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-72 * time.Hour)
+	transition := now.Add(-10 * time.Minute)
+	degraded := now.Add(-2 * time.Minute)
+	desired, won := 3, 2
+
+	description := &cloudSpaceDescription{
+		CloudSpace: &client.CloudSpace{
+			Metadata: client.ObjectMeta{Name: "my-cloudspace", Namespace: "org-abc123", CreationTimestamp: &created},
+			Spec:     client.CloudSpaceSpec{Region: "uk-lon-1", KubernetesVersion: "1.31.1", CNI: "cilium"},
+			Status: client.CloudSpaceStatus{
+				Phase:        "Ready",
+				Health:       "Healthy",
+				UpgradePhase: "Idle",
+				Conditions: []client.CloudSpaceCondition{
+					{Type: "Ready", Status: "True", Reason: "Provisioned", LastTransitionTime: &transition},
+					{Type: "NodesHealthy", Status: "False", Severity: "Warning", Reason: "NodeNotReady", Message: "1 of 3 nodes not ready", LastTransitionTime: &degraded},
+				},
+				Bids: map[string]interface{}{
+					"bid-1": map[string]interface{}{"serverClass": "gp.vs1.large-lon", "won": float64(2)},
+				},
+			},
+		},
+		SpotNodePools: []client.SpotNodePool{
+			{
+				Metadata: client.ObjectMeta{Name: "pool-a"},
+				Spec:     client.SpotNodePoolSpec{CloudSpace: "my-cloudspace", ServerClass: "gp.vs1.large-lon", Desired: &desired, BidPrice: "0.05"},
+				Status:   client.SpotNodePoolStatus{BidStatus: "Won", WonCount: &won},
+			},
+		},
+		OnDemandNodePoolsErr: errors.New("forbidden"),
+	}
+
+	var buf bytes.Buffer
+	if err := describeCloudSpace(&buf, description, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"my-cloudspace",
+		"(3d ago)",
+		"Upgrade Phase:",
+		"Idle",
+		"Conditions:",
+		"Provisioned",
+		"10m",
+		"Bids:",
+		"bid-1:",
+		"serverClass:",
+		"Assigned Servers:",
+		"Spot Node Pools:",
+		"pool-a",
+		"gp.vs1.large-lon",
+		"On-Demand Node Pools:",
+		"<error: forbidden>",
+		"Events:",
+		"Cloudspace created",
+		"NodesHealthy is False: 1 of 3 nodes not ready",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+
+	// Events are told oldest first, and unhealthy conditions are warnings
+	events := out[strings.Index(out, "Events:"):]
+	if strings.Index(events, "Created") > strings.Index(events, "Provisioned") || strings.Index(events, "Provisioned") > strings.Index(events, "NodeNotReady") {
+		t.Errorf("Expected events in time order, got:\n%s", events)
+	}
+	if !strings.Contains(events, "Warning") {
+		t.Errorf("Expected the unhealthy condition to be a warning, got:\n%s", events)
+	}
+}
+
+func TestBuildNamespaceTree(t *testing.T) {
+	desired, won, reserved, orphanDesired := 3, 2, 1, 1
+
+	data := &topologyData{
+		CloudSpaces: &client.CloudSpaceList{Items: []client.CloudSpace{
+			{Metadata: client.ObjectMeta{Name: "zeta"}, Spec: client.CloudSpaceSpec{Region: "uk-lon-1"}},
+			{Metadata: client.ObjectMeta{Name: "alpha"}, Spec: client.CloudSpaceSpec{Region: "uk-lon-1"}, Status: client.CloudSpaceStatus{Phase: "Ready"}},
+		}},
+		SpotNodePools: &client.SpotNodePoolList{Items: []client.SpotNodePool{
+			{
+				Metadata: client.ObjectMeta{Name: "spot-a"},
+				Spec:     client.SpotNodePoolSpec{CloudSpace: "alpha", ServerClass: "gp.vs1.large-lon", Desired: &desired},
+				Status:   client.SpotNodePoolStatus{BidStatus: "Won", WonCount: &won},
+			},
+			{
+				Metadata: client.ObjectMeta{Name: "orphan"},
+				Spec:     client.SpotNodePoolSpec{CloudSpace: "deleted", ServerClass: "unknown-class", Desired: &orphanDesired},
+			},
+		}},
+		OnDemandNodePools: &client.OnDemandNodePoolList{Items: []client.OnDemandNodePool{
+			{
+				Metadata: client.ObjectMeta{Name: "od-a"},
+				Spec:     client.OnDemandNodePoolSpec{CloudSpace: "alpha", ServerClass: "mh.vs1.xlarge-lon", Desired: &reserved},
+				Status:   client.OnDemandNodePoolStatus{ReservedCount: &reserved},
+			},
+		}},
+		ServerClasses: &client.ServerClassList{Items: []client.ServerClass{
+			{
+				Metadata: client.ObjectMeta{Name: "gp.vs1.large-lon"},
+				Status:   client.ServerClassStatus{SpotPricing: client.ServerClassSpotPricing{MarketPricePerHour: "0.035"}},
+			},
+			{
+				Metadata: client.ObjectMeta{Name: "mh.vs1.xlarge-lon"},
+				Spec:     client.ServerClassSpec{OnDemandPricing: client.ServerClassPricing{Cost: "146", Interval: "month"}},
+			},
+		}},
+	}
+
+	tree := buildNamespaceTree("org-abc123", data)
+
+	if len(tree.CloudSpaces) != 2 || tree.CloudSpaces[0].Name != "alpha" {
+		t.Fatalf("Expected cloudspaces sorted by name, got %+v", tree.CloudSpaces)
+	}
+	alpha := tree.CloudSpaces[0]
+	if alpha.Desired != 4 || alpha.Won != 2 || alpha.Reserved != 1 {
+		t.Errorf("Unexpected counts: desired=%d won=%d reserved=%d", alpha.Desired, alpha.Won, alpha.Reserved)
+	}
+	// 2 x 0.035 spot + 1 x 146/730 on-demand
+	if got := alpha.HourlyCost.String(); got != "0.2700" {
+		t.Errorf("Expected alpha hourly cost 0.2700, got %s", got)
+	}
+	if len(tree.UnattachedNodePools) != 1 || tree.UnattachedNodePools[0].Name != "orphan" {
+		t.Errorf("Expected orphan pool to be unattached, got %+v", tree.UnattachedNodePools)
+	}
+	if !tree.CostIncomplete {
+		t.Errorf("Expected total cost to be incomplete because of the unknown server class")
+	}
+
+	var buf bytes.Buffer
+	if err := renderTree(&buf, tree); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		"Namespace org-abc123: $0.2700/h ($197.10/month) (incomplete)",
+		"alpha (uk-lon-1, Ready)  desired=4 won=2 reserved=1  $0.2700/h",
+		"├── spot/spot-a  gp.vs1.large-lon  desired=3 won=2  Won  $0.0700/h",
+		"└── ondemand/od-a  mh.vs1.xlarge-lon  desired=1 reserved=1  $0.2000/h",
+		"zeta (uk-lon-1, <none>)",
+		"└── <no node pools>",
+		"Node pools referencing missing cloudspaces:",
+		"└── spot/orphan",
+		"cost unknown",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestFetchTopologyCancelsOnFirstError(t *testing.T) {
+	server := spottest.NewTestServer(t)
+	server.InjectFault(spottest.Fault{Path: "/ngpc.rxt.io/v1/namespaces/" + spottest.DefaultNamespace + "/spotnodepools", Status: http.StatusForbidden})
+	server.InjectFault(spottest.Fault{Path: "/ngpc.rxt.io/v1/namespaces/" + spottest.DefaultNamespace + "/cloudspaces", Latency: time.Minute})
+	apiClient := client.NewClient(server.Config())
+
+	start := time.Now()
+	_, err := fetchTopology(context.Background(), apiClient, spottest.DefaultNamespace)
+	if err == nil || !strings.Contains(err.Error(), "failed to list spot node pools") {
+		t.Fatalf("Expected the spot node pool failure, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected the slow cloudspace list to be cancelled, took %s", elapsed)
+	}
+}
+
+func TestCloudspacesDeleteCascade(t *testing.T) {
+	originalInterval := cmdutil.WaitPollInterval
+	cmdutil.WaitPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { cmdutil.WaitPollInterval = originalInterval })
+
+	setup := func(t *testing.T) *client.Client {
+		t.Helper()
+		server := spottest.NewTestServer(t, spottest.WithDeletionDelay(20*time.Millisecond))
+		server.Configure(t)
+		viper.Set("namespace", spottest.DefaultNamespace)
+
+		meta := func(name string) client.ObjectMeta {
+			return client.ObjectMeta{Name: name, Namespace: spottest.DefaultNamespace}
+		}
+		if err := server.Seed(
+			client.CloudSpace{Metadata: meta("web")},
+			client.CloudSpace{Metadata: meta("api")},
+			client.SpotNodePool{Metadata: meta("web-spot"), Spec: client.SpotNodePoolSpec{CloudSpace: "web"}},
+			client.SpotNodePool{Metadata: meta("api-spot"), Spec: client.SpotNodePoolSpec{CloudSpace: "api"}},
+			client.OnDemandNodePool{Metadata: meta("web-ondemand"), Spec: client.OnDemandNodePoolSpec{CloudSpace: "web"}},
+		); err != nil {
+			t.Fatal(err)
+		}
+		return client.NewClient(server.Config())
+	}
+	run := func(args ...string) (string, string, error) {
+		var out, errOut bytes.Buffer
+		cmd := NewDeleteCommand()
+		cmd.SetArgs(args)
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		err := cmd.Execute()
+		return out.String(), errOut.String(), err
+	}
+	exists := func(t *testing.T, apiClient *client.Client, kind, name string) bool {
+		t.Helper()
+		var err error
+		switch kind {
+		case "cloudspace":
+			_, err = apiClient.GetCloudSpace(context.Background(), spottest.DefaultNamespace, name)
+		case "spotnodepool":
+			_, err = apiClient.GetSpotNodePool(context.Background(), spottest.DefaultNamespace, name)
+		case "ondemandnodepool":
+			_, err = apiClient.GetOnDemandNodePool(context.Background(), spottest.DefaultNamespace, name)
+		}
+		if err != nil && !client.IsNotFound(err) {
+			t.Fatalf("Failed to get %s %s: %v", kind, name, err)
+		}
+		return err == nil
+	}
+
+	t.Run("foreground", func(t *testing.T) {
+		apiClient := setup(t)
+		out, stderr, err := run("web", "--cascade=foreground", "--confirm")
+		if err != nil {
+			t.Fatalf("Delete failed: %v\n%s", err, stderr)
+		}
+		for _, expected := range []string{
+			"SpotNodePool 'web-spot' deleted successfully",
+			"OnDemandNodePool 'web-ondemand' deleted successfully",
+			"Waiting for spotnodepool/web-spot, ondemandnodepool/web-ondemand to be removed...",
+			"CloudSpace 'web' deleted successfully",
+			"Waiting for cloudspace/web to be removed...\nCloudSpace 'web' removed\n",
+		} {
+			if !strings.Contains(out, expected) {
+				t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+			}
+		}
+		if strings.Index(out, "OnDemandNodePool 'web-ondemand' removed") > strings.Index(out, "CloudSpace 'web' deleted") {
+			t.Errorf("Expected the node pools to be removed before the cloudspace was deleted, got:\n%s", out)
+		}
+		if exists(t, apiClient, "cloudspace", "web") || exists(t, apiClient, "spotnodepool", "web-spot") || exists(t, apiClient, "ondemandnodepool", "web-ondemand") {
+			t.Error("Expected the cloudspace and its node pools to be gone")
+		}
+		if !exists(t, apiClient, "cloudspace", "api") || !exists(t, apiClient, "spotnodepool", "api-spot") {
+			t.Error("Expected the other cloudspace and its node pool to be kept")
+		}
+	})
+
+	t.Run("orphan", func(t *testing.T) {
+		apiClient := setup(t)
+		out, stderr, err := run("web", "--cascade=orphan", "--confirm")
+		if err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if !strings.Contains(stderr, "Warning: 2 node pools will be left without their cloudspace and keep running: spotnodepool/web-spot, ondemandnodepool/web-ondemand") {
+			t.Errorf("Expected a warning about the orphaned node pools, got %q", stderr)
+		}
+		if strings.Contains(out, "NodePool") {
+			t.Errorf("Expected no node pools to be deleted, got:\n%s", out)
+		}
+		if !exists(t, apiClient, "spotnodepool", "web-spot") {
+			t.Error("Expected the node pool to be kept")
+		}
+	})
+
+	t.Run("filename", func(t *testing.T) {
+		apiClient := setup(t)
+		runStdin := func(stdin string, args ...string) (string, string, error) {
+			var out, errOut bytes.Buffer
+			cmd := NewDeleteCommand()
+			cmd.SetArgs(args)
+			cmd.SetIn(strings.NewReader(stdin))
+			cmd.SetOut(&out)
+			cmd.SetErr(&errOut)
+			err := cmd.Execute()
+			return out.String(), errOut.String(), err
+		}
+
+		if _, _, err := runStdin("cloudspace/api\n", "-f", "-", "--cascade=orphan"); err == nil || !strings.Contains(err.Error(), "--confirm is required") {
+			t.Errorf("Expected --confirm to be required reading stdin, got %v", err)
+		}
+		if _, _, err := runStdin("spotnodepool/api-spot\n", "-f", "-", "--confirm"); err == nil || !strings.Contains(err.Error(), "is not a cloudspace") {
+			t.Errorf("Expected other kinds to be rejected, got %v", err)
+		}
+		if _, stderr, err := runStdin("cloudspace/api\n", "-f", "-", "--cascade=foreground", "--confirm"); err != nil {
+			t.Fatalf("Delete failed: %v\n%s", err, stderr)
+		}
+		if exists(t, apiClient, "cloudspace", "api") || !exists(t, apiClient, "cloudspace", "web") {
+			t.Error("Expected only the cloudspace read from stdin to be deleted")
+		}
+
+		// The prompt goes to stderr and is answered on stdin
+		out, stderr, err := runStdin("n\n", "web", "--cascade=orphan")
+		if err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if out != "" || !strings.Contains(stderr, "Are you sure you want to delete cloudspace 'web'") || !strings.Contains(stderr, "Delete cancelled") {
+			t.Errorf("Expected the prompt and cancellation on stderr, got stdout %q, stderr %q", out, stderr)
+		}
+		if !exists(t, apiClient, "cloudspace", "web") {
+			t.Error("Expected the cloudspace to be kept")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		setup(t)
+		if _, _, err := run("web", "--cascade=always", "--confirm"); err == nil || !strings.Contains(err.Error(), "invalid --cascade value") {
+			t.Errorf("Expected an invalid --cascade error, got %v", err)
+		}
+	})
+}
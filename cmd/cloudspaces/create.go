@@ -1,13 +1,14 @@
 package cloudspaces
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -20,8 +21,10 @@ func NewCreateCommand() *cobra.Command {
 
 The namespace can be specified via:
 - The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
 - The 'namespace' field in your config file
 - The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
 
 Examples:
   # Create a cloudspace using namespace from config
@@ -43,7 +46,10 @@ Examples:
   spotctl cloudspaces create my-cloudspace --file spec.json
 
   # Create a cloudspace from a file but override the cloud provider
-  spotctl cloudspaces create my-cloudspace --file spec.json --cloud custom`,
+  spotctl cloudspaces create my-cloudspace --file spec.json --cloud custom
+
+  # Validate a cloudspace spec with the API without creating it
+  spotctl cloudspaces create my-cloudspace --file spec.json --dry-run=server`,
 		Args: cobra.ExactArgs(1),
 		RunE: runCreate,
 	}
@@ -58,6 +64,7 @@ Examples:
 	cmd.Flags().Bool("ha-control-plane", false, "Enable high availability control plane")
 	cmd.Flags().String("cni", "cilium", "Container Network Interface (CNI) to use")
 	cmd.Flags().String("cloud", "default", "Cloud provider")
+	cmdutil.AddDryRunFlag(cmd)
 
 	return cmd
 }
@@ -65,7 +72,12 @@ Examples:
 func runCreate(cmd *cobra.Command, args []string) error {
 	cloudspaceName := args[0] // Get name from positional argument
 
-	namespace, err := getNamespace(cmd)
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	dryRun, err := cmdutil.DryRun(cmd)
 	if err != nil {
 		return err
 	}
@@ -91,6 +103,9 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	apiClient := client.NewClient(cfg)
+	if dryRun {
+		apiClient = apiClient.WithDryRun()
+	}
 
 	var cloudSpace *client.CloudSpace
 
@@ -140,14 +155,18 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	createdCloudSpace, err := apiClient.CreateCloudSpace(ctx, namespace, cloudSpace)
 	if err != nil {
 		return fmt.Errorf("failed to create cloudspace: %w", err)
 	}
 
+	if dryRun {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Server dry run: cloudspace '%s' was validated but not created\n", cloudspaceName)
+	}
+
 	// Output the created cloudspace
-	return outputCreatedCloudSpace(createdCloudSpace, outputFormat)
+	return registry.Output(registry.CloudSpaces, createdCloudSpace, outputFormat)
 }
 
 // loadCloudSpaceSpecFromFile loads a CloudSpaceSpec from a JSON file
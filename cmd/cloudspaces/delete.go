@@ -1,25 +1,47 @@
 package cloudspaces
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
 // NewDeleteCommand returns the cloudspaces delete command
 func NewDeleteCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete [NAME]",
-		Short: "Delete a cloudspace",
-		Long: `Delete a cloudspace by name in the specified namespace.
+		Use:   "delete (NAME... | -f FILE)",
+		Short: "Delete cloudspaces",
+		Long: `Delete one or more cloudspaces by name in the specified namespace. Several
+cloudspaces are deleted concurrently, followed by a summary; the command fails if
+any of them could not be deleted.
+
+Spot and on-demand node pools whose spec.cloudSpace names a deleted cloudspace
+are handled according to --cascade, which must be given if there are any:
+- background: delete the node pools, then the cloudspaces, without waiting
+- foreground: delete the node pools and wait until they are removed, then delete
+  the cloudspaces and wait until they are removed too, reporting progress
+- orphan: delete only the cloudspaces, warning about the node pools left running
+
+Without --cascade, a cloudspace that has node pools is not deleted; earlier
+versions deleted it and left its node pools running, as --cascade=orphan does.
+The confirmation prompt lists every object that will be deleted. If any node pool
+cannot be deleted, the cloudspaces are kept. Waiting stops at --timeout.
+
+The cloudspaces can also be named with --filename/-f by a manifest or a list of
+references one per line, as printed by -o name; use -f - to read them from stdin,
+together with --confirm.
 
 The namespace can be specified via:
 - The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
 - The 'namespace' field in your config file
 - The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
 
 Examples:
   # Delete a cloudspace using namespace from config
@@ -29,37 +51,54 @@ Examples:
   spotctl cloudspaces delete my-cloudspace --namespace org-abc123
 
   # Delete with confirmation
-  spotctl cloudspaces delete my-cloudspace --confirm`,
-		Args: cobra.ExactArgs(1),
-		RunE: runDelete,
+  spotctl cloudspaces delete my-cloudspace --confirm
+
+  # Check that the cloudspace can be deleted without deleting it
+  spotctl cloudspaces delete my-cloudspace --dry-run=server
+
+  # Delete several cloudspaces
+  spotctl cloudspaces delete web api --confirm
+
+  # Delete a cloudspace and its node pools without waiting
+  spotctl cloudspaces delete my-cloudspace --cascade=background --confirm
+
+  # Delete a cloudspace and its node pools, waiting up to 20 minutes until all are gone
+  spotctl cloudspaces delete my-cloudspace --cascade=foreground --timeout 20m
+
+  # Delete only the cloudspace, leaving its node pools
+  spotctl cloudspaces delete my-cloudspace --cascade=orphan`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completion.Each(completion.CloudSpaceNames),
+		RunE:              runDelete,
 	}
 
 	// Add flags for cloudspaces delete command
-	cmd.Flags().StringP("namespace", "n", "", "Namespace of the cloudspace (overrides config)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of the cloudspaces (overrides config)")
 	cmd.Flags().Bool("confirm", false, "Skip confirmation prompt")
+	cmdutil.AddFilenameFlag(cmd)
+	cmdutil.AddCascadeFlag(cmd)
+	cmdutil.AddDryRunFlag(cmd)
 
 	return cmd
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
-	cloudspaceName := args[0] // Get name from positional argument
-
-	namespace, err := getNamespace(cmd)
+	dryRun, err := cmdutil.DryRun(cmd)
+	if err != nil {
+		return err
+	}
+	cascadeMode, err := cmdutil.CascadeMode(cmd)
 	if err != nil {
 		return err
 	}
 
 	confirm, _ := cmd.Flags().GetBool("confirm")
-
-	// Ask for confirmation unless --confirm flag is used
-	if !confirm {
-		fmt.Printf("Are you sure you want to delete cloudspace '%s' in namespace '%s'? (y/N): ", cloudspaceName, namespace)
-		var response string
-		fmt.Scanln(&response)
-		if response != "y" && response != "Y" && response != "yes" && response != "Yes" {
-			fmt.Println("Delete cancelled")
-			return nil
-		}
+	if err := cmdutil.RequireConfirmForStdin(cmd, confirm, dryRun); err != nil {
+		return err
+	}
+	targets, err := cmdutil.KindTargets(cmd, registry.CloudSpaces, args)
+	if err != nil {
+		return err
 	}
 
 	cfg, err := config.GetConfig()
@@ -68,21 +107,25 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	apiClient := client.NewClient(cfg)
+	if dryRun {
+		apiClient = apiClient.WithDryRun()
+	}
 
-	ctx := context.Background()
-	deleteResponse, err := apiClient.DeleteCloudSpace(ctx, namespace, cloudspaceName)
+	// Find the node pools that would be stranded by deleting the cloudspaces
+	ctx := cmd.Context()
+	cascade, err := cmdutil.PlanCascade(ctx, apiClient, targets, cascadeMode, cmd.ErrOrStderr())
 	if err != nil {
-		return fmt.Errorf("failed to delete cloudspace: %w", err)
+		return err
 	}
 
-	// Check if the deletion was successful
-	if deleteResponse.Status == "Success" || deleteResponse.Status == "" {
-		fmt.Printf("Cloudspace '%s' deleted successfully from namespace '%s'\n", cloudspaceName, namespace)
-	} else {
-		fmt.Printf("Delete operation completed with status: %s\n", deleteResponse.Status)
-		if deleteResponse.Message != "" {
-			fmt.Printf("Message: %s\n", deleteResponse.Message)
+	// Ask for confirmation unless --confirm flag is used; a dry run deletes nothing
+	if !confirm && !dryRun {
+		if !cmdutil.Confirm(cmd, cmdutil.ConfirmDeleteMessage(cascade.All())) {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Delete cancelled")
+			return nil
 		}
 	}
-	return nil
+
+	// Node pools go first, then the cloudspaces concurrently, with a summary at the end
+	return cascade.Delete(ctx, apiClient, cmd.OutOrStdout(), cmd.ErrOrStderr(), dryRun)
 }
@@ -0,0 +1,273 @@
+package cloudspaces
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/pager"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewDescribeCommand returns the cloudspaces describe command
+func NewDescribeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe <cloudspace-name>",
+		Short: "Show a detailed description of a cloudspace",
+		Long: `Show a detailed, human-readable description of a cloudspace.
+
+The description includes the cloudspace metadata and spec, its conditions,
+assigned servers, bids and pending allocations, the spot and on-demand node
+pools that reference it, and its events. The API keeps no event log, so the
+events are pieced together from the creation, first ready and deletion times
+and the last transition of each condition; earlier transitions are not shown.
+
+The namespace can be specified via:
+- The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
+- The 'namespace' field in your config file
+- The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
+
+Examples:
+  # Describe a cloudspace using namespace from config
+  spotctl cloudspaces describe my-cloudspace
+
+  # Describe a cloudspace in a specific namespace
+  spotctl cloudspaces describe my-cloudspace --namespace org-abc123`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.CloudSpaceNames,
+		RunE:              runDescribe,
+	}
+
+	// Add flags for cloudspaces describe command
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of the cloudspace (overrides config)")
+
+	return cmd
+}
+
+// cloudSpaceDescription holds everything shown by describe
+type cloudSpaceDescription struct {
+	CloudSpace        *client.CloudSpace
+	SpotNodePools     []client.SpotNodePool
+	OnDemandNodePools []client.OnDemandNodePool
+	// Errors encountered while looking up related node pools; shown inline rather than failing
+	SpotNodePoolsErr     error
+	OnDemandNodePoolsErr error
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	cloudspaceName := args[0] // Get name from positional argument
+
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+	cloudSpace, err := apiClient.GetCloudSpace(ctx, namespace, cloudspaceName)
+	if err != nil {
+		return fmt.Errorf("failed to get cloudspace: %w", err)
+	}
+
+	description := &cloudSpaceDescription{CloudSpace: cloudSpace}
+
+	// The node pools are listed concurrently, as neither depends on the other
+	var spotNodePools *client.SpotNodePoolList
+	var onDemandNodePools *client.OnDemandNodePoolList
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		spotNodePools, description.SpotNodePoolsErr = apiClient.ListSpotNodePools(ctx, namespace)
+	}()
+	go func() {
+		defer wg.Done()
+		onDemandNodePools, description.OnDemandNodePoolsErr = apiClient.ListOnDemandNodePools(ctx, namespace)
+	}()
+	wg.Wait()
+
+	if description.SpotNodePoolsErr == nil {
+		for _, pool := range spotNodePools.Items {
+			if pool.Spec.CloudSpace == cloudspaceName {
+				description.SpotNodePools = append(description.SpotNodePools, pool)
+			}
+		}
+	}
+	if description.OnDemandNodePoolsErr == nil {
+		for _, pool := range onDemandNodePools.Items {
+			if pool.Spec.CloudSpace == cloudspaceName {
+				description.OnDemandNodePools = append(description.OnDemandNodePools, pool)
+			}
+		}
+	}
+
+	p := pager.NewPager()
+	p.Disable = viper.GetBool("no-pager")
+	return p.WriteToWriter(func(w io.Writer) error {
+		return describeCloudSpace(w, description, time.Now())
+	})
+}
+
+// describeCloudSpace renders the cloudspace description to w
+func describeCloudSpace(w io.Writer, description *cloudSpaceDescription, now time.Time) error {
+	cs := description.CloudSpace
+	d := output.NewDescribeWriter(w)
+
+	d.Field(0, "Name", cs.Metadata.Name)
+	d.Field(0, "Namespace", cs.Metadata.Namespace)
+	d.Field(0, "Created", output.Timestamp(cs.Metadata.CreationTimestamp, now))
+	if cs.Metadata.DeletionTimestamp != nil {
+		d.Field(0, "Deleting Since", output.Timestamp(cs.Metadata.DeletionTimestamp, now))
+	}
+	d.Map(0, "Labels", cs.Metadata.Labels)
+	d.Map(0, "Annotations", cs.Metadata.Annotations)
+
+	d.Section(0, "Spec")
+	d.Field(1, "Region", cs.Spec.Region)
+	d.Field(1, "Kubernetes Version", cs.Spec.KubernetesVersion)
+	d.Field(1, "HA Control Plane", strconv.FormatBool(cs.Spec.HAControlPlane))
+	d.Field(1, "CNI", cs.Spec.CNI)
+	d.Field(1, "Cloud", cs.Spec.Cloud)
+	d.Field(1, "Deployment Type", cs.Spec.DeploymentType)
+	d.Field(1, "Type", cs.Spec.Type)
+	d.Field(1, "Webhook", cs.Spec.Webhook)
+	networks := make([]string, 0, len(cs.Spec.Networks))
+	for _, network := range cs.Spec.Networks {
+		networks = append(networks, fmt.Sprintf("%s (%s)", network.Name, network.Subnet))
+	}
+	d.List(1, "Networks", networks)
+	servers := make([]string, 0, len(cs.Spec.Servers))
+	for _, server := range cs.Spec.Servers {
+		servers = append(servers, fmt.Sprintf("%s x%d", server.Class, server.Count))
+	}
+	d.List(1, "Servers", servers)
+
+	d.Section(0, "Status")
+	d.Field(1, "Phase", cs.Status.Phase)
+	d.Field(1, "Health", cs.Status.Health)
+	d.Field(1, "Reason", cs.Status.Reason)
+	d.Field(1, "Upgrade Phase", cs.Status.UpgradePhase)
+	d.Field(1, "Current Kubernetes Version", cs.Status.CurrentKubernetesVersion)
+	d.Field(1, "API Server Endpoint", cs.Status.APIServerEndpoint)
+	d.Field(1, "First Ready", output.Timestamp(cs.Status.FirstReadyTimestamp, now))
+
+	conditions := make([][]string, 0, len(cs.Status.Conditions))
+	for _, condition := range cs.Status.Conditions {
+		conditions = append(conditions, []string{
+			condition.Type,
+			condition.Status,
+			valueOrNone(condition.Severity),
+			valueOrNone(condition.Reason),
+			output.Age(condition.LastTransitionTime, now),
+			valueOrNone(condition.Message),
+		})
+	}
+	d.Table(0, "Conditions", []string{"TYPE", "STATUS", "SEVERITY", "REASON", "AGE", "MESSAGE"}, conditions)
+
+	d.Object(0, "Assigned Servers", toObject(cs.Status.AssignedServers))
+	d.Object(0, "Bids", toObject(cs.Status.Bids))
+	d.Object(0, "Pending Allocations", toObject(cs.Status.PendingAllocations))
+
+	if description.SpotNodePoolsErr != nil {
+		d.Field(0, "Spot Node Pools", fmt.Sprintf("<error: %v>", description.SpotNodePoolsErr))
+	} else {
+		rows := make([][]string, 0, len(description.SpotNodePools))
+		for _, pool := range description.SpotNodePools {
+			rows = append(rows, []string{
+				pool.Metadata.Name,
+				valueOrNone(pool.Spec.ServerClass),
+				intOrNone(pool.Spec.Desired),
+				intOrNone(pool.Status.WonCount),
+				valueOrNone(pool.Status.BidStatus),
+				valueOrNone(pool.Spec.BidPrice),
+			})
+		}
+		d.Table(0, "Spot Node Pools", []string{"NAME", "SERVER CLASS", "DESIRED", "WON", "BID STATUS", "BID PRICE"}, rows)
+	}
+
+	if description.OnDemandNodePoolsErr != nil {
+		d.Field(0, "On-Demand Node Pools", fmt.Sprintf("<error: %v>", description.OnDemandNodePoolsErr))
+	} else {
+		rows := make([][]string, 0, len(description.OnDemandNodePools))
+		for _, pool := range description.OnDemandNodePools {
+			rows = append(rows, []string{
+				pool.Metadata.Name,
+				valueOrNone(pool.Spec.ServerClass),
+				intOrNone(pool.Spec.Desired),
+				intOrNone(pool.Status.ReservedCount),
+				valueOrNone(pool.Status.ReservedStatus),
+			})
+		}
+		d.Table(0, "On-Demand Node Pools", []string{"NAME", "SERVER CLASS", "DESIRED", "RESERVED", "STATUS"}, rows)
+	}
+
+	d.Events(0, cloudSpaceEvents(cs), now)
+
+	return d.Flush()
+}
+
+// cloudSpaceEvents narrates the cloudspace from its timestamps and the last
+// transition of each condition. Conditions that are not True are warnings unless
+// their severity is Info.
+func cloudSpaceEvents(cs *client.CloudSpace) []output.Event {
+	events := []output.Event{
+		{Time: cs.Metadata.CreationTimestamp, Type: output.EventNormal, Reason: "Created", Message: "Cloudspace created"},
+		{Time: cs.Status.FirstReadyTimestamp, Type: output.EventNormal, Reason: "FirstReady", Message: "Cloudspace became ready for the first time"},
+	}
+	for _, condition := range cs.Status.Conditions {
+		eventType := output.EventNormal
+		if condition.Status != "True" && condition.Severity != "Info" {
+			eventType = output.EventWarning
+		}
+		reason := condition.Reason
+		if reason == "" {
+			reason = condition.Type
+		}
+		message := fmt.Sprintf("%s is %s", condition.Type, condition.Status)
+		if condition.Message != "" {
+			message += ": " + condition.Message
+		}
+		events = append(events, output.Event{Time: condition.LastTransitionTime, Type: eventType, Reason: reason, Message: message})
+	}
+	events = append(events, output.Event{Time: cs.Metadata.DeletionTimestamp, Type: output.EventNormal, Reason: "Deleting", Message: "Cloudspace deletion requested"})
+	return events
+}
+
+// toObject converts a decoded status map into a generic value for rendering
+func toObject(values map[string]interface{}) interface{} {
+	if values == nil {
+		return map[string]interface{}{}
+	}
+	return values
+}
+
+func valueOrNone(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "<none>"
+	}
+	return value
+}
+
+func intOrNone(value *int) string {
+	if value == nil {
+		return "<none>"
+	}
+	return strconv.Itoa(*value)
+}
@@ -5,21 +5,33 @@ import (
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
 // NewEditCommand returns the cloudspaces edit command
 func NewEditCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "edit <cloudspace-name>",
-		Short: "Edit a cloudspace",
-		Long: `Edit a cloudspace in the specified namespace using JSON patch operations.
+		Use:   "edit (NAME... | -f FILE) --patch FILE",
+		Short: "Edit cloudspaces",
+		Long: `Edit one or more cloudspaces in the specified namespace using JSON patch operations.
+The same patch is applied to each cloudspace named in turn; any that fail are
+reported and the rest are still edited.
+
+The cloudspaces can also be named with --filename/-f by a manifest or a list of
+references one per line, as printed by -o name; use -f - to read them from stdin.
+The patch is read from --patch/-p. Earlier versions read it from --file/-f, which
+is deprecated; -f now names the cloudspaces to edit, as for 'spotctl edit'.
 
 The namespace can be specified via:
 - The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
 - The 'namespace' field in your config file
 - The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
 
 The patch operations should be provided in a JSON file with the following format:
 [
@@ -39,52 +51,69 @@ Supported operations are: add, remove, replace, move, copy, test.
 
 Examples:
   # Edit a cloudspace using namespace from config
-  spotctl cloudspaces edit my-cloudspace --file patch.json
+  spotctl cloudspaces edit my-cloudspace --patch patch.json
 
   # Edit with specific namespace (overrides config)
-  spotctl cloudspaces edit my-cloudspace --namespace org-abc123 --file patch.json
+  spotctl cloudspaces edit my-cloudspace --namespace org-abc123 --patch patch.json
+
+  # Apply the same patch to several cloudspaces
+  spotctl cloudspaces edit cs-a cs-b --patch patch.json
 
   # Edit with detailed information output
-  spotctl cloudspaces edit my-cloudspace --file patch.json -o wide
+  spotctl cloudspaces edit my-cloudspace --patch patch.json -o wide
 
   # Edit and output the result as JSON
-  spotctl cloudspaces edit my-cloudspace --file patch.json --output json
+  spotctl cloudspaces edit my-cloudspace --patch patch.json --output json
 
   # Edit and output the result as YAML (skip confirmation)
-  spotctl cloudspaces edit my-cloudspace --file patch.json --output yaml --confirm`,
-		Args: cobra.ExactArgs(1),
-		RunE: runEdit,
+  spotctl cloudspaces edit my-cloudspace --patch patch.json --output yaml --confirm
+
+  # Edit the cloudspaces named in a manifest
+  spotctl cloudspaces edit -f cloudspaces.yaml --patch patch.json
+
+  # Validate the patch with the API without applying it
+  spotctl cloudspaces edit my-cloudspace --patch patch.json --dry-run=server`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completion.Each(completion.CloudSpaceNames),
+		RunE:              runEdit,
 	}
 
 	// Add flags for cloudspaces edit command
-	cmd.Flags().StringP("namespace", "n", "", "Namespace of the cloudspace (overrides config)")
-	cmd.Flags().StringP("file", "f", "", "Path to the JSON file containing patch operations (required)")
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of the cloudspaces (overrides config)")
+	cmdutil.AddPatchFlags(cmd)
+	cmdutil.AddFilenameFlag(cmd)
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
 	cmd.Flags().Bool("confirm", false, "Skip confirmation prompt")
-
-	// Mark only file as required (namespace comes from config/flag/env)
-	cmd.MarkFlagRequired("file")
+	cmdutil.AddDryRunFlag(cmd)
 
 	return cmd
 }
 
 func runEdit(cmd *cobra.Command, args []string) error {
-	namespace, err := getNamespace(cmd)
+	dryRun, err := cmdutil.DryRun(cmd)
 	if err != nil {
 		return err
 	}
+	skipConfirmation, _ := cmd.Flags().GetBool("confirm")
+	if err := cmdutil.RequireConfirmForStdin(cmd, skipConfirmation, dryRun); err != nil {
+		return err
+	}
 
-	file, _ := cmd.Flags().GetString("file")
+	targets, err := cmdutil.KindTargets(cmd, registry.CloudSpaces, args)
+	if err != nil {
+		return err
+	}
 	outputFormat, _ := cmd.Flags().GetString("output")
 
 	// Load the JSON patch operations from the file
-	patchOps, err := client.LoadPatchOperations(file)
+	patchOps, err := cmdutil.LoadPatch(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to load patch operations: %w", err)
+		return err
 	}
 
-	// Display the patch operations that will be applied
-	client.DisplayPatchOperations(patchOps)
+	// The patch and prompt go to stderr so that -o json and -o yaml stay parseable
+	errOut := cmd.ErrOrStderr()
+	client.WritePatchOperations(errOut, patchOps)
 
 	// Create a new client
 	cfg, err := config.GetConfig()
@@ -92,26 +121,49 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 	apiClient := client.NewClient(cfg)
+	if dryRun {
+		apiClient = apiClient.WithDryRun()
+	}
 
-	// Prompt for confirmation if the --confirm flag is not set
-	skipConfirmation, _ := cmd.Flags().GetBool("confirm")
-	if !skipConfirmation {
-		confirmed, err := client.PromptForConfirmation(fmt.Sprintf("cloudspace '%s'", args[0]))
+	// Prompt for confirmation if the --confirm flag is not set; a dry run changes nothing
+	if !skipConfirmation && !dryRun {
+		confirmed, err := client.PromptForConfirmationTo(errOut, cmdutil.DescribeTargets(targets))
 		if err != nil {
 			return err
 		}
 		if !confirmed {
-			fmt.Println("Patch operation cancelled.")
+			fmt.Fprintln(errOut, "Patch operation cancelled.")
 			return nil
 		}
 	}
 
-	// Apply the patch operations
-	updatedCloudSpace, err := apiClient.EditCloudSpace(context.Background(), namespace, args[0], patchOps)
-	if err != nil {
-		return fmt.Errorf("failed to edit cloudspace: %w", err)
+	// A single cloudspace fails like any other command
+	if len(targets) == 1 {
+		t := targets[0]
+		updatedCloudSpace, err := apiClient.EditCloudSpace(cmd.Context(), t.Namespace, t.Name, patchOps)
+		if err != nil {
+			return fmt.Errorf("failed to edit cloudspace: %w", err)
+		}
+		if dryRun {
+			fmt.Fprintf(errOut, "Server dry run: cloudspace '%s' was validated but not changed\n", t.Name)
+		}
+
+		// Output the updated cloudspace using the same formatting as the get command
+		return registry.Output(registry.CloudSpaces, updatedCloudSpace, outputFormat)
 	}
 
-	// Output the updated cloudspace using the same formatting as the get command
-	return outputCloudSpace(updatedCloudSpace, outputFormat)
+	// Several cloudspaces are patched in turn and shown as a list, after reporting any that failed
+	items, editErr := cmdutil.EachTarget(cmd.Context(), targets, errOut, "edit", func(ctx context.Context, t cmdutil.Target) (*client.CloudSpace, error) {
+		updatedCloudSpace, err := apiClient.EditCloudSpace(ctx, t.Namespace, t.Name, patchOps)
+		if err == nil && dryRun {
+			fmt.Fprintf(errOut, "Server dry run: cloudspace '%s' was validated but not changed\n", t.Name)
+		}
+		return updatedCloudSpace, err
+	})
+	if len(items) > 0 {
+		if err := registry.OutputList(registry.CloudSpaces, items, outputFormat, ""); err != nil {
+			return err
+		}
+	}
+	return editErr
 }
@@ -5,21 +5,26 @@ import (
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
 // NewGetCommand returns the cloudspaces get command
 func NewGetCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "get [NAME]",
-		Short: "Get a specific cloudspace",
-		Long: `Get detailed information about a specific cloudspace by name in the specified namespace.
+		Use:   "get NAME...",
+		Short: "Get specific cloudspaces",
+		Long: `Get detailed information about one or more cloudspaces by name in the specified namespace.
 
 The namespace can be specified via:
 - The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
 - The 'namespace' field in your config file
 - The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
 
 Examples:
   # Get a cloudspace using namespace from config
@@ -35,13 +40,17 @@ Examples:
   spotctl cloudspaces get my-cloudspace --output json
 
   # Get cloudspace with YAML output
-  spotctl cloudspaces get my-cloudspace --output yaml`,
-		Args: cobra.ExactArgs(1),
-		RunE: runGet,
+  spotctl cloudspaces get my-cloudspace --output yaml
+
+  # Get several cloudspaces
+  spotctl cloudspaces get web api`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completion.Each(completion.CloudSpaceNames),
+		RunE:              runGet,
 	}
 
 	// Add flags for cloudspaces get command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
 	cmd.Flags().StringP("namespace", "n", "", "Namespace of the cloudspace (overrides config)")
 
 	return cmd
@@ -50,7 +59,7 @@ Examples:
 func runGet(cmd *cobra.Command, args []string) error {
 	cloudspaceName := args[0] // Get name from positional argument
 
-	namespace, err := getNamespace(cmd)
+	namespace, err := cmdutil.ResolveNamespace(cmd)
 	if err != nil {
 		return err
 	}
@@ -62,13 +71,27 @@ func runGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	client := client.NewClient(cfg)
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+
+	// Several cloudspaces are shown as a list, after reporting any that could not be found
+	if len(args) > 1 {
+		items, getErr := cmdutil.EachName(ctx, args, cmd.ErrOrStderr(), "get", "cloudspace", func(ctx context.Context, name string) (*client.CloudSpace, error) {
+			return apiClient.GetCloudSpace(ctx, namespace, name)
+		})
+		if len(items) > 0 {
+			if err := registry.OutputList(registry.CloudSpaces, items, outputFormat, namespace); err != nil {
+				return err
+			}
+		}
+		return getErr
+	}
 
-	ctx := context.Background()
-	cloudSpace, err := client.GetCloudSpace(ctx, namespace, cloudspaceName)
+	cloudSpace, err := apiClient.GetCloudSpace(ctx, namespace, cloudspaceName)
 	if err != nil {
 		return fmt.Errorf("failed to get cloudspace: %w", err)
 	}
 
-	return outputCloudSpace(cloudSpace, outputFormat)
+	return registry.Output(registry.CloudSpaces, cloudSpace, outputFormat)
 }
@@ -0,0 +1,227 @@
+package cloudspaces
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/kubeconfig"
+	"github.com/spf13/cobra"
+)
+
+// NewKubeconfigCommand returns the cloudspaces kubeconfig command
+func NewKubeconfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kubeconfig <cloudspace-name>",
+		Short: "Generate a kubeconfig for a cloudspace",
+		Long: `Generate a kubeconfig for a cloudspace using its API server endpoint.
+
+The generated kubeconfig has kubectl run 'spotctl auth print-token' whenever it
+needs a token, so it keeps working for as long as your refresh token does. This
+needs spotctl on the PATH of whatever runs kubectl. Where that is not possible,
+--embed-token writes the current token into the kubeconfig instead; it is
+short-lived, so the command must be run again once it expires.
+
+By default the kubeconfig is printed to stdout. Use --output to write it to a
+file, or --merge to add it to your existing kubeconfig (the first file in
+$KUBECONFIG, or ~/.kube/config). Merging only adds or updates the entries for
+this cloudspace; all other clusters, contexts and users are left untouched.
+
+The namespace can be specified via:
+- The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
+- The 'namespace' field in your config file
+- The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
+
+Examples:
+  # Print a kubeconfig for a cloudspace
+  spotctl cloudspaces kubeconfig my-cloudspace
+
+  # Write the kubeconfig to a file
+  spotctl cloudspaces kubeconfig my-cloudspace -o my-cloudspace.yaml
+
+  # Write a kubeconfig for a CI job without spotctl, valid until the token expires
+  spotctl cloudspaces kubeconfig my-cloudspace --embed-token -o ci-kubeconfig.yaml
+
+  # Merge into ~/.kube/config with a custom context name
+  spotctl cloudspaces kubeconfig my-cloudspace --merge --context-name spot-prod
+
+  # Remove the merged entries once the cloudspace has been deleted
+  spotctl cloudspaces kubeconfig my-cloudspace --remove --context-name spot-prod`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.CloudSpaceNames,
+		RunE:              runKubeconfig,
+	}
+
+	// Add flags for cloudspaces kubeconfig command
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of the cloudspace (overrides config)")
+	cmd.Flags().StringP("output", "o", "", "Write the kubeconfig to this file instead of stdout")
+	cmd.Flags().Bool("merge", false, "Merge the kubeconfig into your existing kubeconfig")
+	cmd.Flags().Bool("remove", false, "Remove the cloudspace's entries from your existing kubeconfig")
+	cmd.Flags().String("kubeconfig", "", "Kubeconfig file used by --merge and --remove (default is $KUBECONFIG or ~/.kube/config)")
+	cmd.Flags().String("context-name", "", "Name of the context, cluster and user entries (default is the cloudspace name)")
+	cmd.Flags().Bool("set-current", false, "Make the merged context the current context")
+	cmd.Flags().Bool("force", false, "Overwrite existing cluster, context and user entries of the same name that belong to something else")
+	cmd.Flags().Bool("insecure-skip-tls-verify", false, "Skip TLS verification of the cloudspace API server")
+	cmd.Flags().Bool("embed-token", false, "Embed the current short-lived token instead of having kubectl run spotctl for one")
+
+	cmd.MarkFlagsMutuallyExclusive("merge", "remove", "output")
+
+	return cmd
+}
+
+func runKubeconfig(cmd *cobra.Command, args []string) error {
+	cloudspaceName := args[0] // Get name from positional argument
+
+	outputFile, _ := cmd.Flags().GetString("output")
+	merge, _ := cmd.Flags().GetBool("merge")
+	remove, _ := cmd.Flags().GetBool("remove")
+	kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+	contextName, _ := cmd.Flags().GetString("context-name")
+	setCurrent, _ := cmd.Flags().GetBool("set-current")
+	force, _ := cmd.Flags().GetBool("force")
+	insecure, _ := cmd.Flags().GetBool("insecure-skip-tls-verify")
+	embedToken, _ := cmd.Flags().GetBool("embed-token")
+
+	if contextName == "" {
+		contextName = cloudspaceName
+	}
+
+	if (merge || remove) && kubeconfigPath == "" {
+		path, err := kubeconfig.DefaultPath()
+		if err != nil {
+			return err
+		}
+		kubeconfigPath = path
+	}
+
+	// Removing entries is purely local, so it works after the cloudspace is gone
+	if remove {
+		existing, err := kubeconfig.Load(kubeconfigPath)
+		if err != nil {
+			return err
+		}
+		if !existing.RemoveContext(contextName) {
+			fmt.Printf("Context '%s' not found in %s\n", contextName, kubeconfigPath)
+			return nil
+		}
+		if err := kubeconfig.Write(kubeconfigPath, existing); err != nil {
+			return err
+		}
+		fmt.Printf("Removed context '%s' from %s\n", contextName, kubeconfigPath)
+		return nil
+	}
+
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+	user, err := kubeconfigUser(cmd, apiClient, embedToken)
+	if err != nil {
+		return err
+	}
+	generated, err := buildKubeconfig(ctx, apiClient, namespace, cloudspaceName, contextName, user, insecure)
+	if err != nil {
+		return err
+	}
+	if embedToken {
+		warnTokenExpiry(cmd.ErrOrStderr(), user["token"].(string), time.Now())
+	}
+
+	switch {
+	case merge:
+		existing, err := kubeconfig.Load(kubeconfigPath)
+		if err != nil {
+			return err
+		}
+		if err := existing.Merge(generated, force); err != nil {
+			return err
+		}
+		if setCurrent {
+			existing.CurrentContext = contextName
+		}
+		if err := kubeconfig.Write(kubeconfigPath, existing); err != nil {
+			return err
+		}
+		fmt.Printf("Merged context '%s' into %s\n", contextName, kubeconfigPath)
+		if existing.CurrentContext != contextName {
+			fmt.Printf("Switch to it with: kubectl config use-context %s\n", contextName)
+		}
+		return nil
+	case outputFile != "":
+		if err := kubeconfig.Write(outputFile, generated); err != nil {
+			return err
+		}
+		fmt.Printf("Kubeconfig for cloudspace '%s' written to %s\n", cloudspaceName, outputFile)
+		return nil
+	default:
+		data, err := generated.Marshal()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+}
+
+// kubeconfigUser returns the user of the generated kubeconfig: by default one for
+// which kubectl runs 'spotctl auth print-token', with the same --config as this
+// command, or with embedToken one holding the current token
+func kubeconfigUser(cmd *cobra.Command, apiClient *client.Client, embedToken bool) (map[string]interface{}, error) {
+	if embedToken {
+		token, err := apiClient.AccessToken(cmd.Context())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get access token: %w", err)
+		}
+		return kubeconfig.TokenUser(token), nil
+	}
+
+	args := []string{"auth", "print-token", "--exec-credential"}
+	if flag := cmd.Flags().Lookup("config"); flag != nil && flag.Value.String() != "" {
+		path, err := filepath.Abs(flag.Value.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve config path: %w", err)
+		}
+		args = append(args, "--config", path)
+	}
+	return kubeconfig.ExecUser("spotctl", args...), nil
+}
+
+// warnTokenExpiry says when an embedded token stops working, so it is not a surprise
+func warnTokenExpiry(w io.Writer, token string, now time.Time) {
+	claims, err := client.ParseIDToken(token)
+	if err != nil || claims.ExpiresAt().IsZero() {
+		fmt.Fprintln(w, "Warning: the kubeconfig embeds a short-lived token; run this command again when it expires, or omit --embed-token to have kubectl fetch tokens with spotctl")
+		return
+	}
+	expiresAt := claims.ExpiresAt()
+	fmt.Fprintf(w, "Warning: the kubeconfig embeds a token that expires at %s (in %s); run this command again then, or omit --embed-token to have kubectl fetch tokens with spotctl\n",
+		expiresAt.Local().Format(time.RFC3339), expiresAt.Sub(now).Round(time.Minute))
+}
+
+// buildKubeconfig fetches the cloudspace and assembles a kubeconfig for it that authenticates as user
+func buildKubeconfig(ctx context.Context, apiClient *client.Client, namespace, name, contextName string, user map[string]interface{}, insecure bool) (*kubeconfig.Config, error) {
+	cloudSpace, err := apiClient.GetCloudSpace(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cloudspace: %w", err)
+	}
+
+	return kubeconfig.ForCloudSpace(cloudSpace, user, contextName, insecure)
+}
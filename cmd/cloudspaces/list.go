@@ -1,11 +1,12 @@
 package cloudspaces
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -18,8 +19,10 @@ func NewListCommand() *cobra.Command {
 
 The namespace can be specified via:
 - The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
 - The 'namespace' field in your config file
 - The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
 
 Examples:
   # List cloudspaces using namespace from config
@@ -32,20 +35,41 @@ Examples:
   spotctl cloudspaces list --namespace my-namespace -o wide
 
   # List cloudspaces with JSON output
-  spotctl cloudspaces list --namespace my-namespace --output json`,
+  spotctl cloudspaces list --namespace my-namespace --output json
+
+  # List cloudspaces in the namespaces of all your organizations
+  spotctl cloudspaces list -A`,
 		Args: cobra.NoArgs,
 		RunE: runList,
 	}
 
 	// Add flags for cloudspaces list command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
 	cmd.Flags().StringP("namespace", "n", "", "Namespace to list cloudspaces from (overrides config)")
+	cmdutil.AddListFlags(cmd)
+	cmdutil.AddAllNamespacesFlag(cmd)
 
 	return cmd
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	namespace, err := getNamespace(cmd)
+	allNamespaces, err := cmdutil.AllNamespaces(cmd)
+	if err != nil {
+		return err
+	}
+
+	listOpts, limit, err := cmdutil.ListFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	if allNamespaces {
+		return cmdutil.ListKindAllNamespaces(cmd, registry.CloudSpaces, listOpts, limit, outputFormat)
+	}
+
+	namespace, err := cmdutil.ResolveNamespace(cmd)
 	if err != nil {
 		return err
 	}
@@ -55,15 +79,13 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	client := client.NewClient(cfg)
+	apiClient := client.NewClient(cfg)
 
-	ctx := context.Background()
-	cloudSpaceList, err := client.ListCloudSpaces(ctx, namespace)
+	ctx := cmd.Context()
+	items, err := client.Collect(apiClient.AllCloudSpaces(ctx, namespace, listOpts), limit)
 	if err != nil {
 		return fmt.Errorf("failed to list cloudspaces: %w", err)
 	}
 
-	outputFormat, _ := cmd.Flags().GetString("output")
-
-	return outputCloudSpaces(cloudSpaceList, outputFormat, namespace)
+	return registry.OutputList(registry.CloudSpaces, items, outputFormat, namespace)
 }
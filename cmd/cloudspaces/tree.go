@@ -0,0 +1,401 @@
+package cloudspaces
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/pager"
+	"github.com/georgetaylor/spotctl/pkg/pricing"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewTreeCommand returns the cloudspaces tree command
+func NewTreeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Show cloudspaces with their node pools",
+		Long: `Show every cloudspace in a namespace with its spot and on-demand node pools
+nested underneath.
+
+For each node pool the desired, won (spot) or reserved (on-demand) server counts
+are shown together with an hourly cost estimate based on the current spot market
+price or on-demand price of its server class. Node pools that reference a
+cloudspace that no longer exists are listed separately.
+
+The namespace can be specified via:
+- The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
+- The 'namespace' field in your config file
+- The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
+
+Examples:
+  # Show the cloudspace topology using namespace from config
+  spotctl cloudspaces tree
+
+  # Show the topology of a specific namespace as JSON
+  spotctl cloudspaces tree --namespace org-abc123 --output json`,
+		Args: cobra.NoArgs,
+		RunE: runTree,
+	}
+
+	// Add flags for cloudspaces tree command
+	cmd.Flags().StringP("output", "o", "tree", "Output format (tree, json, yaml)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace to show cloudspaces from (overrides config)")
+
+	return cmd
+}
+
+// namespaceTree is the topology of every cloudspace in a namespace
+type namespaceTree struct {
+	Namespace           string           `json:"namespace" yaml:"namespace"`
+	HourlyCost          pricing.Amount   `json:"hourlyCost" yaml:"hourlyCost"`
+	MonthlyCost         pricing.Amount   `json:"monthlyCost" yaml:"monthlyCost"`
+	CostIncomplete      bool             `json:"costIncomplete,omitempty" yaml:"costIncomplete,omitempty"`
+	CloudSpaces         []cloudSpaceTree `json:"cloudSpaces" yaml:"cloudSpaces"`
+	UnattachedNodePools []nodePoolTree   `json:"unattachedNodePools,omitempty" yaml:"unattachedNodePools,omitempty"`
+}
+
+// cloudSpaceTree is a cloudspace together with the node pools that reference it
+type cloudSpaceTree struct {
+	Name              string         `json:"name" yaml:"name"`
+	Region            string         `json:"region,omitempty" yaml:"region,omitempty"`
+	Phase             string         `json:"phase,omitempty" yaml:"phase,omitempty"`
+	Desired           int            `json:"desired" yaml:"desired"`
+	Won               int            `json:"won" yaml:"won"`
+	Reserved          int            `json:"reserved" yaml:"reserved"`
+	HourlyCost        pricing.Amount `json:"hourlyCost" yaml:"hourlyCost"`
+	CostIncomplete    bool           `json:"costIncomplete,omitempty" yaml:"costIncomplete,omitempty"`
+	SpotNodePools     []nodePoolTree `json:"spotNodePools" yaml:"spotNodePools"`
+	OnDemandNodePools []nodePoolTree `json:"onDemandNodePools" yaml:"onDemandNodePools"`
+}
+
+// nodePoolTree summarises a spot or on-demand node pool
+type nodePoolTree struct {
+	Kind        string          `json:"kind" yaml:"kind"`
+	Name        string          `json:"name" yaml:"name"`
+	CloudSpace  string          `json:"cloudSpace,omitempty" yaml:"cloudSpace,omitempty"`
+	ServerClass string          `json:"serverClass" yaml:"serverClass"`
+	Desired     *int            `json:"desired,omitempty" yaml:"desired,omitempty"`
+	Won         *int            `json:"won,omitempty" yaml:"won,omitempty"`
+	Reserved    *int            `json:"reserved,omitempty" yaml:"reserved,omitempty"`
+	Status      string          `json:"status,omitempty" yaml:"status,omitempty"`
+	HourlyCost  *pricing.Amount `json:"hourlyCost,omitempty" yaml:"hourlyCost,omitempty"`
+	CostError   string          `json:"costError,omitempty" yaml:"costError,omitempty"`
+}
+
+// topologyData holds the raw resources fetched for a namespace
+type topologyData struct {
+	CloudSpaces       *client.CloudSpaceList
+	SpotNodePools     *client.SpotNodePoolList
+	OnDemandNodePools *client.OnDemandNodePoolList
+	ServerClasses     *client.ServerClassList
+	// ServerClassesErr is kept separately: without prices the tree is still useful
+	ServerClassesErr error
+}
+
+func runTree(cmd *cobra.Command, args []string) error {
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat != "tree" && outputFormat != "json" && outputFormat != "yaml" {
+		return fmt.Errorf("unsupported output format: %s (use tree, json or yaml)", outputFormat)
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+	data, err := fetchTopology(ctx, apiClient, namespace)
+	if err != nil {
+		return err
+	}
+
+	tree := buildNamespaceTree(namespace, data)
+
+	p := pager.NewPager()
+	p.Disable = viper.GetBool("no-pager")
+	return p.WriteToWriter(func(w io.Writer) error {
+		if outputFormat == "tree" {
+			if data.ServerClassesErr != nil {
+				fmt.Fprintf(w, "Warning: costs unavailable: %v\n\n", data.ServerClassesErr)
+			}
+			return renderTree(w, tree)
+		}
+		formatter := output.NewFormatter(output.OutputOptions{Format: output.OutputFormat(outputFormat)})
+		return formatter.OutputToWriter(w, tree, nil)
+	})
+}
+
+// fetchTopology fetches cloudspaces, node pools and server classes concurrently.
+// The first cloudspace or node pool list to fail cancels the others, whose errors
+// would only be cancellations; server classes are optional and never cancel.
+func fetchTopology(ctx context.Context, apiClient *client.Client, namespace string) (*topologyData, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	data := &topologyData{}
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		list, err := apiClient.ListCloudSpaces(ctx, namespace)
+		if err != nil {
+			fail(fmt.Errorf("failed to list cloudspaces: %w", err))
+		}
+		data.CloudSpaces = list
+	}()
+	go func() {
+		defer wg.Done()
+		list, err := apiClient.ListSpotNodePools(ctx, namespace)
+		if err != nil {
+			fail(fmt.Errorf("failed to list spot node pools: %w", err))
+		}
+		data.SpotNodePools = list
+	}()
+	go func() {
+		defer wg.Done()
+		list, err := apiClient.ListOnDemandNodePools(ctx, namespace)
+		if err != nil {
+			fail(fmt.Errorf("failed to list on demand node pools: %w", err))
+		}
+		data.OnDemandNodePools = list
+	}()
+	go func() {
+		defer wg.Done()
+		data.ServerClasses, data.ServerClassesErr = apiClient.ListServerClasses(ctx)
+	}()
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return data, nil
+}
+
+// buildNamespaceTree joins node pools to their cloudspaces via spec.cloudSpace and totals counts and costs
+func buildNamespaceTree(namespace string, data *topologyData) *namespaceTree {
+	var spotPools []client.SpotNodePool
+	if data.SpotNodePools != nil {
+		spotPools = data.SpotNodePools.Items
+	}
+	var onDemandPools []client.OnDemandNodePool
+	if data.OnDemandNodePools != nil {
+		onDemandPools = data.OnDemandNodePools.Items
+	}
+	estimate := pricing.NewCatalog(data.ServerClasses).Estimate(namespace, spotPools, onDemandPools)
+	costs := make(map[string]pricing.NodePoolCost, len(estimate.NodePools))
+	for _, cost := range estimate.NodePools {
+		costs[cost.Type+"/"+cost.Name] = cost
+	}
+
+	tree := &namespaceTree{Namespace: namespace, CloudSpaces: []cloudSpaceTree{}}
+
+	index := make(map[string]int)
+	if data.CloudSpaces != nil {
+		for _, cs := range data.CloudSpaces.Items {
+			index[cs.Metadata.Name] = len(tree.CloudSpaces)
+			tree.CloudSpaces = append(tree.CloudSpaces, cloudSpaceTree{
+				Name:              cs.Metadata.Name,
+				Region:            cs.Spec.Region,
+				Phase:             cs.Status.Phase,
+				SpotNodePools:     []nodePoolTree{},
+				OnDemandNodePools: []nodePoolTree{},
+			})
+		}
+	}
+
+	for _, pool := range spotPools {
+		node := nodePoolTree{
+			Kind:        "spot",
+			Name:        pool.Metadata.Name,
+			CloudSpace:  pool.Spec.CloudSpace,
+			ServerClass: pool.Spec.ServerClass,
+			Desired:     pool.Spec.Desired,
+			Won:         pool.Status.WonCount,
+			Status:      pool.Status.BidStatus,
+		}
+		setNodeCost(&node, costs[pricing.SpotNodePoolType+"/"+pool.Metadata.Name], data.ServerClassesErr)
+
+		i, ok := index[pool.Spec.CloudSpace]
+		if !ok {
+			tree.UnattachedNodePools = append(tree.UnattachedNodePools, node)
+			continue
+		}
+		cs := &tree.CloudSpaces[i]
+		cs.SpotNodePools = append(cs.SpotNodePools, node)
+		cs.Desired += derefInt(pool.Spec.Desired)
+		cs.Won += derefInt(pool.Status.WonCount)
+	}
+
+	for _, pool := range onDemandPools {
+		node := nodePoolTree{
+			Kind:        "ondemand",
+			Name:        pool.Metadata.Name,
+			CloudSpace:  pool.Spec.CloudSpace,
+			ServerClass: pool.Spec.ServerClass,
+			Desired:     pool.Spec.Desired,
+			Reserved:    pool.Status.ReservedCount,
+			Status:      pool.Status.ReservedStatus,
+		}
+		setNodeCost(&node, costs[pricing.OnDemandNodePoolType+"/"+pool.Metadata.Name], data.ServerClassesErr)
+
+		i, ok := index[pool.Spec.CloudSpace]
+		if !ok {
+			tree.UnattachedNodePools = append(tree.UnattachedNodePools, node)
+			continue
+		}
+		cs := &tree.CloudSpaces[i]
+		cs.OnDemandNodePools = append(cs.OnDemandNodePools, node)
+		cs.Desired += derefInt(pool.Spec.Desired)
+		cs.Reserved += derefInt(pool.Status.ReservedCount)
+	}
+
+	// The estimate totals every pool by spec.cloudSpace, unattached ones included
+	for _, cost := range estimate.CloudSpaces {
+		if i, ok := index[cost.Name]; ok {
+			tree.CloudSpaces[i].HourlyCost = cost.Hourly
+			tree.CloudSpaces[i].CostIncomplete = cost.Incomplete
+		}
+	}
+	for i := range tree.CloudSpaces {
+		sortNodePools(tree.CloudSpaces[i].SpotNodePools)
+		sortNodePools(tree.CloudSpaces[i].OnDemandNodePools)
+	}
+	sort.Slice(tree.CloudSpaces, func(i, j int) bool { return tree.CloudSpaces[i].Name < tree.CloudSpaces[j].Name })
+	sortNodePools(tree.UnattachedNodePools)
+
+	tree.HourlyCost = estimate.Summary.Hourly
+	tree.MonthlyCost = estimate.Summary.Monthly
+	tree.CostIncomplete = estimate.Summary.Incomplete
+
+	return tree
+}
+
+// setNodeCost records a node pool's estimated cost or the reason it could not be computed
+func setNodeCost(node *nodePoolTree, cost pricing.NodePoolCost, catalogErr error) {
+	switch {
+	case catalogErr != nil:
+		node.CostError = "server class prices unavailable"
+	case cost.Error != "":
+		node.CostError = cost.Error
+	default:
+		node.HourlyCost = &cost.Hourly
+	}
+}
+
+// renderTree writes the namespace topology as an indented tree
+func renderTree(w io.Writer, tree *namespaceTree) error {
+	fmt.Fprintf(w, "Namespace %s: %s\n", tree.Namespace, formatCost(tree.HourlyCost, tree.CostIncomplete, true))
+
+	if len(tree.CloudSpaces) == 0 && len(tree.UnattachedNodePools) == 0 {
+		fmt.Fprintf(w, "No cloudspaces found in namespace %s\n", tree.Namespace)
+		return nil
+	}
+
+	for _, cs := range tree.CloudSpaces {
+		fmt.Fprintln(w)
+		details := []string{valueOrNone(cs.Region), valueOrNone(cs.Phase)}
+		fmt.Fprintf(w, "%s (%s)  desired=%d won=%d reserved=%d  %s\n",
+			cs.Name, strings.Join(details, ", "), cs.Desired, cs.Won, cs.Reserved,
+			formatCost(cs.HourlyCost, cs.CostIncomplete, false))
+
+		pools := append(append([]nodePoolTree{}, cs.SpotNodePools...), cs.OnDemandNodePools...)
+		if len(pools) == 0 {
+			fmt.Fprintln(w, "└── <no node pools>")
+			continue
+		}
+		writeNodePools(w, pools)
+	}
+
+	if len(tree.UnattachedNodePools) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Node pools referencing missing cloudspaces:")
+		writeNodePools(w, tree.UnattachedNodePools)
+	}
+
+	return nil
+}
+
+// writeNodePools writes node pools as tree branches
+func writeNodePools(w io.Writer, pools []nodePoolTree) {
+	for i, pool := range pools {
+		branch := "├──"
+		if i == len(pools)-1 {
+			branch = "└──"
+		}
+
+		counts := fmt.Sprintf("desired=%s", intOrNone(pool.Desired))
+		if pool.Kind == "spot" {
+			counts += fmt.Sprintf(" won=%s", intOrNone(pool.Won))
+		} else {
+			counts += fmt.Sprintf(" reserved=%s", intOrNone(pool.Reserved))
+		}
+
+		cost := "cost unknown"
+		if pool.HourlyCost != nil {
+			cost = formatCost(*pool.HourlyCost, false, false)
+		}
+
+		line := fmt.Sprintf("%s %s/%s  %s  %s", branch, pool.Kind, pool.Name, valueOrNone(pool.ServerClass), counts)
+		if pool.Status != "" {
+			line += "  " + pool.Status
+		}
+		if pool.HourlyCost == nil && pool.CostError != "" {
+			cost += " (" + pool.CostError + ")"
+		}
+		fmt.Fprintf(w, "%s  %s\n", line, cost)
+	}
+}
+
+// formatCost renders an hourly cost, optionally with its monthly projection
+func formatCost(hourly pricing.Amount, incomplete, monthly bool) string {
+	result := fmt.Sprintf("$%s/h", hourly)
+	if monthly {
+		result += fmt.Sprintf(" (%s/month)", hourly.Monthly().Dollars())
+	}
+	if incomplete {
+		result += " (incomplete)"
+	}
+	return result
+}
+
+func sortNodePools(pools []nodePoolTree) {
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+}
+
+func derefInt(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -3,8 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
 
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
 	"github.com/georgetaylor/spotctl/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -20,15 +21,17 @@ func GetOutputFormat(cmd *cobra.Command) string {
 	return output
 }
 
-// ConfirmAction prompts the user for confirmation
-func ConfirmAction(message string) bool {
-	fmt.Printf("%s (y/N): ", message)
-	var response string
-	fmt.Scanln(&response)
-	return strings.ToLower(response) == "y" || strings.ToLower(response) == "yes"
+// newAPIClient creates the client used by the generic get, edit and delete verbs,
+// making server-side dry runs if dryRun is set. Tests replace it with a fake.
+var newAPIClient = func(cfg *config.Config, dryRun bool) client.Interface {
+	apiClient := client.NewClient(cfg)
+	if dryRun {
+		return apiClient.WithDryRun()
+	}
+	return apiClient
 }
 
-// CheckError checks for an error and exits if one exists
+// CheckError checks for an error and exits if one exists, with ExitCode(err)
 func CheckError(err error) {
 	if err != nil {
 		if appErr, ok := err.(*errors.Error); ok {
@@ -36,6 +39,6 @@ func CheckError(err error) {
 		} else {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		}
-		os.Exit(1)
+		os.Exit(ExitCode(err))
 	}
 }
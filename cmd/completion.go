@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a shell completion script for spotctl.
+
+Besides commands and flags, completion suggests resource names fetched live from
+the API, such as cloudspaces and node pools in the current namespace, regions,
+server classes and namespaces for --namespace. Results are cached for a few
+seconds so repeated <TAB> presses stay fast.
+
+Bash:
+  # Requires the bash-completion package
+  source <(spotctl completion bash)
+
+  # Load completions for every new session (Linux)
+  spotctl completion bash > /etc/bash_completion.d/spotctl
+
+Zsh:
+  # Enable completion once if it is not already enabled
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+  # Load completions for every new session
+  spotctl completion zsh > "${fpath[1]}/_spotctl"
+
+Fish:
+  spotctl completion fish > ~/.config/fish/completions/spotctl.fish
+
+PowerShell:
+  spotctl completion powershell | Out-String | Invoke-Expression`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := cmd.Root()
+		switch args[0] {
+		case "bash":
+			return root.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return root.GenZshCompletion(os.Stdout)
+		case "fish":
+			return root.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			return root.GenPowerShellCompletionWithDesc(os.Stdout)
+		default:
+			return fmt.Errorf("unsupported shell: %s", args[0])
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
@@ -3,6 +3,9 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -39,9 +42,16 @@ var configShowCmd = &cobra.Command{
 		}
 
 		fmt.Printf("  namespace: %s\n", viper.GetString("namespace"))
+		fmt.Printf("  org: %s\n", viper.GetString("org"))
 		fmt.Printf("  base-url: %s\n", viper.GetString("base-url"))
+		fmt.Printf("  oauth-url: %s\n", viper.GetString("oauth-url"))
 		fmt.Printf("  debug: %t\n", viper.GetBool("debug"))
 		fmt.Printf("  timeout: %d\n", viper.GetInt("timeout"))
+		fmt.Printf("  ca-file: %s\n", viper.GetString("ca-file"))
+		fmt.Printf("  insecure-skip-tls-verify: %t\n", viper.GetBool("insecure-skip-tls-verify"))
+		fmt.Printf("  client-cert: %s\n", viper.GetString("client-cert"))
+		fmt.Printf("  client-key: %s\n", viper.GetString("client-key"))
+		fmt.Printf("  proxy-url: %s\n", viper.GetString("proxy-url"))
 
 		if viper.ConfigFileUsed() != "" {
 			fmt.Printf("\nConfig file: %s\n", viper.ConfigFileUsed())
@@ -62,7 +72,8 @@ var configSetCmd = &cobra.Command{
 		value := args[1]
 
 		// Validate the key
-		validKeys := []string{"refresh-token", "namespace", "base-url", "debug", "timeout"}
+		validKeys := []string{"refresh-token", "namespace", "org", "base-url", "oauth-url", "debug", "timeout",
+			"ca-file", "insecure-skip-tls-verify", "client-cert", "client-key", "proxy-url"}
 		if !contains(validKeys, key) {
 			CheckError(fmt.Errorf("invalid configuration key '%s'. Valid keys are: %v", key, validKeys))
 		}
@@ -74,10 +85,18 @@ var configSetCmd = &cobra.Command{
 			existingCfg = &config.Config{
 				RefreshToken: viper.GetString("refresh-token"),
 				Namespace:    viper.GetString("namespace"),
+				Org:          viper.GetString("org"),
 				BaseURL:      viper.GetString("base-url"),
+				OAuthURL:     viper.GetString("oauth-url"),
 				Debug:        viper.GetBool("debug"),
 				Timeout:      viper.GetInt("timeout"),
 				OutputFormat: viper.GetString("output-format"),
+
+				CAFile:                viper.GetString("ca-file"),
+				InsecureSkipTLSVerify: viper.GetBool("insecure-skip-tls-verify"),
+				ClientCert:            viper.GetString("client-cert"),
+				ClientKey:             viper.GetString("client-key"),
+				ProxyURL:              viper.GetString("proxy-url"),
 			}
 		}
 
@@ -88,10 +107,18 @@ var configSetCmd = &cobra.Command{
 		cfg := &config.Config{
 			RefreshToken: existingCfg.RefreshToken,
 			Namespace:    existingCfg.Namespace,
+			Org:          existingCfg.Org,
 			BaseURL:      existingCfg.BaseURL,
+			OAuthURL:     existingCfg.OAuthURL,
 			Debug:        existingCfg.Debug,
 			Timeout:      existingCfg.Timeout,
 			OutputFormat: existingCfg.OutputFormat,
+
+			CAFile:                existingCfg.CAFile,
+			InsecureSkipTLSVerify: existingCfg.InsecureSkipTLSVerify,
+			ClientCert:            existingCfg.ClientCert,
+			ClientKey:             existingCfg.ClientKey,
+			ProxyURL:              existingCfg.ProxyURL,
 		}
 
 		// Update the specific field that was changed
@@ -100,12 +127,26 @@ var configSetCmd = &cobra.Command{
 			cfg.RefreshToken = value
 		case "namespace":
 			cfg.Namespace = value
+		case "org":
+			cfg.Org = value
 		case "base-url":
 			cfg.BaseURL = value
+		case "oauth-url":
+			cfg.OAuthURL = value
 		case "debug":
 			cfg.Debug = viper.GetBool("debug")
 		case "timeout":
 			cfg.Timeout = viper.GetInt("timeout")
+		case "ca-file":
+			cfg.CAFile = value
+		case "insecure-skip-tls-verify":
+			cfg.InsecureSkipTLSVerify = viper.GetBool("insecure-skip-tls-verify")
+		case "client-cert":
+			cfg.ClientCert = value
+		case "client-key":
+			cfg.ClientKey = value
+		case "proxy-url":
+			cfg.ProxyURL = value
 		}
 
 		// Save the configuration
@@ -116,6 +157,47 @@ var configSetCmd = &cobra.Command{
 	},
 }
 
+// configSetOrgCmd persists the organization whose namespace commands operate on
+var configSetOrgCmd = &cobra.Command{
+	Use:   "set-org <name|display-name|id>",
+	Short: "Set the default organization",
+	Long: `Set the default organization and save it to the config file.
+
+The organization is looked up by name, display name or ID and its canonical name
+is saved as 'org'. Commands then operate on the namespace of that organization
+unless --namespace or --org is given. Any saved 'namespace' is cleared, since it
+would otherwise take precedence over the organization.
+
+Examples:
+  spotctl config set-org acme
+  spotctl config set-org "Acme Ltd"`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.OrganizationNames,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.GetConfig()
+		CheckError(err)
+
+		orgList, err := client.NewClient(cfg).ListOrganizations(cmd.Context())
+		if err != nil {
+			CheckError(fmt.Errorf("failed to list organizations: %w", err))
+		}
+
+		org, err := cmdutil.FindOrganization(orgList.Organizations, args[0])
+		CheckError(err)
+		if org.Metadata.Namespace == "" {
+			CheckError(fmt.Errorf("organization '%s' has no namespace", org.Name))
+		}
+
+		cfg.Org = org.Name
+		cfg.Namespace = ""
+
+		err = config.SaveConfig(cfg)
+		CheckError(err)
+
+		fmt.Printf("Configuration saved: org = %s (namespace %s)\n", org.Name, org.Metadata.Namespace)
+	},
+}
+
 // configInitCmd initializes the configuration with prompts
 var configInitCmd = &cobra.Command{
 	Use:   "init",
@@ -138,6 +220,7 @@ var configInitCmd = &cobra.Command{
 		viper.Set("refresh-token", refreshToken)
 		viper.Set("namespace", "") // Default to empty namespace
 		viper.Set("base-url", config.Defaults.BaseURL)
+		viper.Set("oauth-url", config.Defaults.OAuthURL)
 		viper.Set("debug", config.Defaults.Debug)
 		viper.Set("timeout", config.Defaults.Timeout)
 
@@ -146,6 +229,7 @@ var configInitCmd = &cobra.Command{
 			RefreshToken: refreshToken,
 			Namespace:    "", // Default to empty namespace
 			BaseURL:      config.Defaults.BaseURL,
+			OAuthURL:     config.Defaults.OAuthURL,
 			Debug:        config.Defaults.Debug,
 			Timeout:      config.Defaults.Timeout,
 			OutputFormat: config.Defaults.OutputFormat,
@@ -165,6 +249,7 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configSetOrgCmd)
 	configCmd.AddCommand(configInitCmd) // Add configInitCmd to config command
 
 	// Add output flag to show command
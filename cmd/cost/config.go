@@ -0,0 +1,141 @@
+package cost
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/pager"
+	"github.com/georgetaylor/spotctl/pkg/pricing"
+	"github.com/spf13/viper"
+)
+
+// getNodePoolCostTableConfig returns the table configuration for per node pool costs
+func getNodePoolCostTableConfig() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "CLOUDSPACE", Field: "cloudSpace", Default: "<none>"},
+			{Header: "NODEPOOL", Field: "name"},
+			{Header: "TYPE", Field: "type"},
+			{Header: "SERVER CLASS", Field: "serverClass", Default: "<none>"},
+			{Header: "SERVERS", Field: "servers"},
+			{Header: "HOURLY", Field: "hourly"},
+			{Header: "MONTHLY", Field: "monthly"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "UNIT HOURLY", Field: "unitHourly"},
+			{Header: "ERROR", Field: "error", Default: "<none>"},
+		},
+	}
+}
+
+// getCloudSpaceCostTableConfig returns the table configuration for per cloudspace costs
+func getCloudSpaceCostTableConfig() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "CLOUDSPACE", Field: "name", Default: "<none>"},
+			{Header: "NODEPOOLS", Field: "nodePools"},
+			{Header: "SERVERS", Field: "servers"},
+			{Header: "HOURLY", Field: "hourly"},
+			{Header: "MONTHLY", Field: "monthly"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "NAMESPACE", Field: "namespace"},
+			{Header: "INCOMPLETE", Field: "incomplete"},
+		},
+	}
+}
+
+// getNamespaceCostTableConfig returns the table configuration for the namespace total
+func getNamespaceCostTableConfig() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "NAMESPACE", Field: "namespace"},
+			{Header: "CLOUDSPACES", Field: "cloudSpaces"},
+			{Header: "NODEPOOLS", Field: "nodePools"},
+			{Header: "SERVERS", Field: "servers"},
+			{Header: "HOURLY", Field: "hourly"},
+			{Header: "MONTHLY", Field: "monthly"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "INCOMPLETE", Field: "incomplete"},
+		},
+	}
+}
+
+// withNamespaceColumn returns a copy of a table configuration with a NAMESPACE
+// column first, for estimates combined across namespaces
+func withNamespaceColumn(config *output.TableConfig) *output.TableConfig {
+	namespaced := &output.TableConfig{
+		Columns: append([]output.TableColumn{{Header: "NAMESPACE", Field: "namespace"}}, config.Columns...),
+	}
+	for _, col := range config.DetailCols {
+		if col.Header != "NAMESPACE" {
+			namespaced.DetailCols = append(namespaced.DetailCols, col)
+		}
+	}
+	return namespaced
+}
+
+// outputEstimate handles formatting and output of a cost estimate.
+// JSON and YAML always contain the full breakdown; table and CSV show the rows for the chosen grouping.
+func outputEstimate(estimate *pricing.Estimate, groupBy, format string) error {
+	p := pager.NewPager()
+	p.Disable = viper.GetBool("no-pager")
+	return p.WriteToWriter(func(w io.Writer) error {
+		return writeEstimate(w, estimate, groupBy, format)
+	})
+}
+
+// writeEstimate writes a cost estimate to w
+func writeEstimate(w io.Writer, estimate *pricing.Estimate, groupBy, format string) error {
+	formatter := output.NewFormatter(output.OutputOptions{Format: output.OutputFormat(format)})
+
+	if format == string(output.JSONFormat) || format == string(output.YAMLFormat) {
+		return formatter.OutputToWriter(w, estimate, nil)
+	}
+
+	var (
+		data        interface{}
+		tableConfig *output.TableConfig
+	)
+	// A combined estimate has a row per namespace and its other rows need telling apart
+	allNamespaces := estimate.Namespaces != nil
+	switch {
+	case groupBy == byNamespace && allNamespaces:
+		data, tableConfig = estimate.Namespaces, getNamespaceCostTableConfig()
+	case groupBy == byNamespace:
+		data, tableConfig = &estimate.Summary, getNamespaceCostTableConfig()
+	case groupBy == byCloudSpace:
+		data, tableConfig = estimate.CloudSpaces, getCloudSpaceCostTableConfig()
+	default:
+		data, tableConfig = estimate.NodePools, getNodePoolCostTableConfig()
+	}
+	if allNamespaces && groupBy != byNamespace {
+		tableConfig = withNamespaceColumn(tableConfig)
+	}
+
+	isTable := format == string(output.TableFormat) || format == string(output.WideFormat)
+	if isTable && estimate.Summary.NodePools == 0 {
+		if allNamespaces {
+			fmt.Fprintln(w, "No node pools found in any namespace")
+		} else {
+			fmt.Fprintf(w, "No node pools found in namespace %s\n", estimate.Summary.Namespace)
+		}
+		return nil
+	}
+
+	if err := formatter.OutputToWriter(w, data, tableConfig); err != nil {
+		return err
+	}
+
+	if isTable && (groupBy != byNamespace || allNamespaces) {
+		summary := estimate.Summary
+		fmt.Fprintf(w, "\nTotal: $%s/hour, %s/month projected\n", summary.Hourly, summary.Monthly.Dollars())
+	}
+	if isTable && estimate.Summary.Incomplete {
+		fmt.Fprintln(w, "Warning: some node pools could not be priced and are excluded from the totals (use -o wide for details)")
+	}
+
+	return nil
+}
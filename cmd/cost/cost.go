@@ -0,0 +1,209 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/pricing"
+	"github.com/spf13/cobra"
+)
+
+// Groupings supported by the --by flag
+const (
+	byNodePool   = "nodepool"
+	byCloudSpace = "cloudspace"
+	byNamespace  = "namespace"
+)
+
+// NewCommand returns the cost command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Estimate what your node pools cost",
+		Long: `Estimate the hourly and projected monthly cost of the node pools in a namespace.
+
+Each node pool is priced using its server class: spot node pools at the current
+spot market price multiplied by the number of servers won, and on-demand node
+pools at the on-demand price multiplied by the number of servers reserved. Pools
+that have not reported a won or reserved count are priced at their desired size.
+Monthly projections assume 730 hours per month.
+
+Costs can be broken down per node pool, per cloudspace or for the whole namespace
+with the --by flag. Node pools whose server class cannot be priced are reported
+with an error and the affected totals are marked as incomplete.
+
+With --all-namespaces/-A the node pools in the namespaces of all your
+organizations are priced and totalled together; --by namespace then shows a row
+per namespace. Namespaces whose node pools cannot be listed are reported after
+the rest and left out of the totals.
+
+The namespace can be specified via:
+- The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
+- The 'namespace' field in your config file
+- The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
+
+Examples:
+  # Show the cost of each node pool using namespace from config
+  spotctl cost
+
+  # Show the cost per cloudspace
+  spotctl cost --by cloudspace
+
+  # Show the cost of each of your organizations' namespaces and their total
+  spotctl cost -A --by namespace
+
+  # Export node pool costs as CSV
+  spotctl cost --namespace org-abc123 --output csv > costs.csv
+
+  # Full breakdown as JSON
+  spotctl cost --output json`,
+		Args: cobra.NoArgs,
+		RunE: runCost,
+	}
+
+	// Add flags for cost command
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, wide, json, yaml, csv)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace to estimate costs for (overrides config)")
+	cmd.Flags().String("by", byNodePool, "Group costs by nodepool, cloudspace or namespace")
+	cmd.Flags().BoolP("all-namespaces", "A", false, "Estimate across the namespaces of all your organizations")
+
+	return cmd
+}
+
+func runCost(cmd *cobra.Command, args []string) error {
+	allNamespaces, err := cmdutil.AllNamespaces(cmd)
+	if err != nil {
+		return err
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	groupBy, _ := cmd.Flags().GetString("by")
+	if groupBy != byNodePool && groupBy != byCloudSpace && groupBy != byNamespace {
+		return fmt.Errorf("invalid --by value: %s (use nodepool, cloudspace or namespace)", groupBy)
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	apiClient := client.NewClient(cfg)
+	ctx := cmd.Context()
+
+	if allNamespaces {
+		return estimateAllNamespaces(cmd, cfg, apiClient, groupBy, outputFormat)
+	}
+
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	estimate, err := estimateNamespace(ctx, apiClient, namespace)
+	if err != nil {
+		return err
+	}
+
+	return outputEstimate(estimate, groupBy, outputFormat)
+}
+
+// estimateAllNamespaces prices the node pools of every organization's namespace
+// against one server class list and writes the combined estimate, then reports
+// the namespaces that could not be listed
+func estimateAllNamespaces(cmd *cobra.Command, cfg *config.Config, apiClient *client.Client, groupBy, outputFormat string) error {
+	ctx := cmd.Context()
+	serverClasses, err := apiClient.ListServerClasses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list server classes: %w", err)
+	}
+	catalog := pricing.NewCatalog(serverClasses)
+
+	results, err := cmdutil.ListAllNamespaces(ctx, cfg, func(ctx context.Context, namespace string) ([]*pricing.Estimate, error) {
+		spotPools, onDemandPools, err := listNodePools(ctx, apiClient, namespace)
+		if err != nil {
+			return nil, err
+		}
+		return []*pricing.Estimate{catalog.Estimate(namespace, spotPools.Items, onDemandPools.Items)}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var estimates []*pricing.Estimate
+	for _, r := range results {
+		estimates = append(estimates, r.Items...)
+	}
+	if err := outputEstimate(pricing.Combine(estimates), groupBy, outputFormat); err != nil {
+		return err
+	}
+	return cmdutil.ReportNamespaceErrors(cmd.ErrOrStderr(), results, "node pools")
+}
+
+// estimateNamespace fetches node pools and server class prices concurrently and prices every pool
+func estimateNamespace(ctx context.Context, apiClient *client.Client, namespace string) (*pricing.Estimate, error) {
+	var (
+		spotPools      *client.SpotNodePoolList
+		onDemandPools  *client.OnDemandNodePoolList
+		serverClasses  *client.ServerClassList
+		poolsErr       error
+		serverClassErr error
+		wg             sync.WaitGroup
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		spotPools, onDemandPools, poolsErr = listNodePools(ctx, apiClient, namespace)
+	}()
+	go func() {
+		defer wg.Done()
+		serverClasses, serverClassErr = apiClient.ListServerClasses(ctx)
+	}()
+	wg.Wait()
+
+	if poolsErr != nil {
+		return nil, poolsErr
+	}
+	if serverClassErr != nil {
+		return nil, fmt.Errorf("failed to list server classes: %w", serverClassErr)
+	}
+
+	catalog := pricing.NewCatalog(serverClasses)
+	return catalog.Estimate(namespace, spotPools.Items, onDemandPools.Items), nil
+}
+
+// listNodePools lists the spot and on-demand node pools of a namespace concurrently
+func listNodePools(ctx context.Context, apiClient *client.Client, namespace string) (*client.SpotNodePoolList, *client.OnDemandNodePoolList, error) {
+	var (
+		spotPools     *client.SpotNodePoolList
+		onDemandPools *client.OnDemandNodePoolList
+		spotErr       error
+		onDemandErr   error
+		wg            sync.WaitGroup
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		spotPools, spotErr = apiClient.ListSpotNodePools(ctx, namespace)
+	}()
+	go func() {
+		defer wg.Done()
+		onDemandPools, onDemandErr = apiClient.ListOnDemandNodePools(ctx, namespace)
+	}()
+	wg.Wait()
+
+	if spotErr != nil {
+		return nil, nil, fmt.Errorf("failed to list spot node pools: %w", spotErr)
+	}
+	if onDemandErr != nil {
+		return nil, nil, fmt.Errorf("failed to list on demand node pools: %w", onDemandErr)
+	}
+	return spotPools, onDemandPools, nil
+}
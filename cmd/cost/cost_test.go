@@ -0,0 +1,191 @@
+package cost
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/pricing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func testEstimate() *pricing.Estimate {
+	catalog := pricing.NewCatalog(&client.ServerClassList{Items: []client.ServerClass{
+		{
+			Metadata: client.ObjectMeta{Name: "gp.vs1.large-lon"},
+			Spec:     client.ServerClassSpec{OnDemandPricing: client.ServerClassPricing{Cost: "0.20", Interval: "hour"}},
+			Status:   client.ServerClassStatus{SpotPricing: client.ServerClassSpotPricing{MarketPricePerHour: "0.035"}},
+		},
+	}})
+
+	return catalog.Estimate("org-abc123",
+		[]client.SpotNodePool{
+			{
+				Metadata: client.ObjectMeta{Name: "spot-a"},
+				Spec:     client.SpotNodePoolSpec{CloudSpace: "alpha", ServerClass: "gp.vs1.large-lon", Desired: intPtr(4)},
+				Status:   client.SpotNodePoolStatus{WonCount: intPtr(3)},
+			},
+		},
+		[]client.OnDemandNodePool{
+			{
+				Metadata: client.ObjectMeta{Name: "od-a"},
+				Spec:     client.OnDemandNodePoolSpec{CloudSpace: "beta", ServerClass: "gp.vs1.large-lon", Desired: intPtr(1)},
+			},
+		},
+	)
+}
+
+func TestCostCommandFlags(t *testing.T) {
+	cmd := NewCommand()
+
+	if cmd.Use != "cost" {
+		t.Errorf("Expected Use to be 'cost', got %s", cmd.Use)
+	}
+	for _, name := range []string{"output", "namespace", "by"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected flag %q to be defined", name)
+		}
+	}
+	if by := cmd.Flags().Lookup("by").DefValue; by != byNodePool {
+		t.Errorf("Expected --by to default to %s, got %s", byNodePool, by)
+	}
+}
+
+func TestWriteEstimate(t *testing.T) {
+	tests := []struct {
+		name        string
+		groupBy     string
+		format      string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "node pool table",
+			groupBy:  byNodePool,
+			format:   "table",
+			contains: []string{"CLOUDSPACE", "spot-a", "od-a", "0.1050", "76.6500", "Total: $0.3050/hour, $222.65/month projected"},
+		},
+		{
+			name:        "cloudspace table",
+			groupBy:     byCloudSpace,
+			format:      "table",
+			contains:    []string{"NODEPOOLS", "alpha", "beta", "0.2000"},
+			notContains: []string{"spot-a"},
+		},
+		{
+			name:        "namespace table",
+			groupBy:     byNamespace,
+			format:      "table",
+			contains:    []string{"org-abc123", "0.3050", "222.6500"},
+			notContains: []string{"Total:"},
+		},
+		{
+			name:    "node pool csv",
+			groupBy: byNodePool,
+			format:  "csv",
+			contains: []string{
+				"CLOUDSPACE,NODEPOOL,TYPE,SERVER CLASS,SERVERS,HOURLY,MONTHLY,UNIT HOURLY,ERROR\n",
+				"alpha,spot-a,spot,gp.vs1.large-lon,3,0.1050,76.6500,0.0350,\n",
+			},
+			notContains: []string{"Total:"},
+		},
+		{
+			name:     "json contains full breakdown",
+			groupBy:  byNamespace,
+			format:   "json",
+			contains: []string{`"summary"`, `"cloudSpaces"`, `"nodePools"`, `"hourly": "0.305000"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeEstimate(&buf, testEstimate(), tt.groupBy, tt.format); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			out := buf.String()
+			for _, expected := range tt.contains {
+				if !strings.Contains(out, expected) {
+					t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+				}
+			}
+			for _, unexpected := range tt.notContains {
+				if strings.Contains(out, unexpected) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unexpected, out)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteEstimateNoNodePools(t *testing.T) {
+	estimate := pricing.NewCatalog(nil).Estimate("org-empty", nil, nil)
+
+	var buf bytes.Buffer
+	if err := writeEstimate(&buf, estimate, byNodePool, "table"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No node pools found in namespace org-empty") {
+		t.Errorf("Unexpected output: %s", buf.String())
+	}
+}
+
+func TestWriteCombinedEstimate(t *testing.T) {
+	estimate := pricing.Combine([]*pricing.Estimate{testEstimate(), pricing.NewCatalog(nil).Estimate("org-empty", nil, nil)})
+
+	var buf bytes.Buffer
+	if err := writeEstimate(&buf, estimate, byNamespace, "table"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, expected := range []string{"org-abc123", "org-empty", "Total: $0.3050/hour, $222.65/month projected"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+
+	buf.Reset()
+	if err := writeEstimate(&buf, estimate, byNodePool, "csv"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "org-abc123,alpha,spot-a,spot,") {
+		t.Errorf("Expected node pools to be namespaced, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeEstimate(&buf, pricing.Combine(nil), byNodePool, "table"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No node pools found in any namespace") {
+		t.Errorf("Unexpected output: %s", buf.String())
+	}
+}
+
+func TestWriteEstimateSubCentTotal(t *testing.T) {
+	catalog := pricing.NewCatalog(&client.ServerClassList{Items: []client.ServerClass{
+		{
+			Metadata: client.ObjectMeta{Name: "gp.vs1.small-lon"},
+			Status:   client.ServerClassStatus{SpotPricing: client.ServerClassSpotPricing{MarketPricePerHour: "0.0095"}},
+		},
+	}})
+	estimate := catalog.Estimate("org-abc123", []client.SpotNodePool{
+		{
+			Metadata: client.ObjectMeta{Name: "spot-a"},
+			Spec:     client.SpotNodePoolSpec{CloudSpace: "alpha", ServerClass: "gp.vs1.small-lon", Desired: intPtr(2)},
+		},
+	}, nil)
+
+	var buf bytes.Buffer
+	if err := writeEstimate(&buf, estimate, byNodePool, "table"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The hourly total keeps the precision of the rows rather than rounding to cents
+	if !strings.Contains(buf.String(), "Total: $0.0190/hour, $13.87/month projected") {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+}
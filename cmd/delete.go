@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// newDeleteCommand creates the generic delete command
+func newDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete (<type> <name>... | <type>/<name>... | -f <file>)",
+		Short: "Delete resources of any type",
+		Long: `Delete resources of any type that supports deletion.
+
+Objects are named by a type followed by one or more names, by type/name
+references, or with --filename by a manifest or a list of type/name references
+one per line, as printed by -o name. Use --filename - to read them from stdin.
+Types can be given by plural, singular or short name, e.g. cloudspaces,
+cloudspace or cs. Namespaced types use the namespace of their manifest, or else
+the namespace from --namespace, --org or your config.
+
+Deleting a cloudspace that has spot or on-demand node pools requires --cascade,
+as 'spotctl cloudspaces delete' does: --cascade=background deletes the node pools
+first, --cascade=foreground also waits for each to be removed, and --cascade=orphan
+deletes only the cloudspace.
+
+Several objects are deleted concurrently. Each is reported as it is deleted or
+fails, followed by a summary; the command fails if any object could not be
+deleted.
+
+Examples:
+  # Delete a spot node pool
+  spotctl delete spotnodepool my-nodepool
+
+  # Delete two spot node pools and a cloudspace without confirmation
+  spotctl delete snp/pool-a snp/pool-b cs/my-cloudspace --confirm
+
+  # Delete the spot node pools labelled env=dev
+  spotctl spotnodepool list -l env=dev -o name | spotctl delete -f - --confirm
+
+  # Delete the objects in a manifest
+  spotctl delete -f nodepools.yaml
+
+  # Delete a cloudspace and its node pools, waiting until all are gone
+  spotctl delete cs/my-cloudspace --cascade=foreground --timeout 20m
+
+  # Check that a cloudspace can be deleted without deleting it
+  spotctl delete cloudspace my-cloudspace --dry-run=server`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completeTypeAndName,
+		RunE:              runDelete,
+	}
+
+	cmd.Flags().Bool("confirm", false, "Skip confirmation prompt")
+	cmdutil.AddFilenameFlag(cmd)
+	cmdutil.AddCascadeFlag(cmd)
+	cmdutil.AddDryRunFlag(cmd)
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newDeleteCommand())
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 && !strings.Contains(args[0], "/") {
+		return fmt.Errorf("no names given: specify the %s to delete after the type", args[0])
+	}
+
+	dryRun, err := cmdutil.DryRun(cmd)
+	if err != nil {
+		return err
+	}
+	cascadeMode, err := cmdutil.CascadeMode(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Check flags before reading stdin, whose targets cannot be confirmed interactively
+	confirm, _ := cmd.Flags().GetBool("confirm")
+	if err := cmdutil.RequireConfirmForStdin(cmd, confirm, dryRun); err != nil {
+		return err
+	}
+
+	targets, err := cmdutil.Targets(cmd, args)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if !t.Kind.Resource().Supports(client.VerbDelete) {
+			return fmt.Errorf("%s cannot be deleted", t.Kind.Resource().Plural)
+		}
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	apiClient := newAPIClient(cfg, dryRun)
+
+	// Cloudspaces are deleted after their node pools, which are confirmed with the rest
+	ctx := cmd.Context()
+	cascade, err := cmdutil.PlanCascade(ctx, apiClient, targets, cascadeMode, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	// Ask for confirmation unless --confirm flag is used; a dry run deletes nothing
+	if !confirm && !dryRun {
+		if !cmdutil.Confirm(cmd, cmdutil.ConfirmDeleteMessage(cascade.All())) {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Delete cancelled")
+			return nil
+		}
+	}
+
+	return cascade.Delete(ctx, apiClient, cmd.OutOrStdout(), cmd.ErrOrStderr(), dryRun)
+}
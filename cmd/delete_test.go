@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/client/clientfake"
+)
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		stdin       string
+		fail        string
+		deleted     []string
+		expectError string
+	}{
+		{
+			name:    "names of one type",
+			args:    []string{"spotnodepool", "pool-a", "pool-b", "--confirm"},
+			deleted: []string{"spotnodepool/pool-a", "spotnodepool/pool-b"},
+		},
+		{
+			name:    "references from stdin",
+			args:    []string{"-f", "-", "--confirm"},
+			stdin:   "spotnodepool/pool-b\n",
+			deleted: []string{"spotnodepool/pool-b"},
+		},
+		{
+			name:  "declined",
+			args:  []string{"snp/pool-a"},
+			stdin: "n\n",
+		},
+		{
+			name:        "one of several fails",
+			args:        []string{"snp/pool-a", "snp/pool-b", "--confirm"},
+			fail:        "pool-a",
+			deleted:     []string{"spotnodepool/pool-b"},
+			expectError: "failed to delete 1 of 2 objects",
+		},
+		{
+			name:        "cloudspace with node pools",
+			args:        []string{"cs/web", "--confirm"},
+			expectError: "use --cascade=background",
+		},
+		{
+			name:    "cloudspace with its node pools",
+			args:    []string{"cs/web", "--cascade=background", "--confirm"},
+			deleted: []string{"cloudspace/web", "spotnodepool/pool-a"},
+		},
+		{
+			name:        "type that cannot be deleted",
+			args:        []string{"region", "us-central-dfw-1", "--confirm"},
+			expectError: "regions cannot be deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clientfake.NewClient(testObjects()...)
+			if tt.fail != "" {
+				fake.FailWith(client.VerbDelete, "spotnodepools", testNamespace, tt.fail, &client.APIError{Code: http.StatusConflict, Message: "conflict"})
+			}
+			useFake(t, fake)
+
+			_, stderr, err := execute(t, newDeleteCommand(), tt.stdin, tt.args...)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.expectError, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Delete failed: %v\n%s", err, stderr)
+			}
+
+			ctx := context.Background()
+			exists := map[string]error{}
+			for _, name := range []string{"web", "api"} {
+				_, exists["cloudspace/"+name] = fake.GetCloudSpace(ctx, testNamespace, name)
+			}
+			for _, name := range []string{"pool-a", "pool-b"} {
+				_, exists["spotnodepool/"+name] = fake.GetSpotNodePool(ctx, testNamespace, name)
+			}
+			for ref, err := range exists {
+				if deleted := slices.Contains(tt.deleted, ref); deleted != client.IsNotFound(err) {
+					t.Errorf("Expected %s deleted to be %v, got error %v", ref, deleted, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+	"github.com/spf13/cobra"
+)
+
+// devServerCmd represents the dev-server command
+var devServerCmd = &cobra.Command{
+	Use:   "dev-server",
+	Short: "Run a local fake of the Rackspace Spot API",
+	Long: `Run a stateful in-memory fake of the Rackspace Spot API for offline
+development and for exercising scripts without touching real resources.
+
+The fake serves regions, server classes, organizations, cloudspaces and node
+pools, plus the OAuth token endpoint. Created cloudspaces and node pools move
+from provisioning to ready after --provision-delay. Everything is lost when the
+server stops.
+
+Examples:
+  # Start the server and follow the printed instructions to point spotctl at it
+  spotctl dev-server
+
+  # Make provisioning take 30 seconds and fail 10% of requests with a 503
+  spotctl dev-server --provision-delay 30s --error-rate 0.1
+
+  # Add 500ms latency to every request
+  spotctl dev-server --latency 500ms`,
+	Args: cobra.NoArgs,
+	RunE: runDevServer,
+}
+
+func init() {
+	rootCmd.AddCommand(devServerCmd)
+
+	devServerCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+	devServerCmd.Flags().Bool("seed", true, "Add sample regions, server classes and an organization")
+	devServerCmd.Flags().Duration("provision-delay", 10*time.Second, "How long created cloudspaces and node pools take to become ready")
+	devServerCmd.Flags().Duration("deletion-delay", 0, "How long deleted objects remain visible while being deleted")
+	devServerCmd.Flags().Duration("latency", 0, "Latency added to every request")
+	devServerCmd.Flags().Float64("error-rate", 0, "Fraction of requests (0-1) that fail with --error-status")
+	devServerCmd.Flags().Int("error-status", http.StatusServiceUnavailable, "HTTP status returned by failed requests, e.g. 429 or 503")
+}
+
+func runDevServer(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	seed, _ := cmd.Flags().GetBool("seed")
+	provisionDelay, _ := cmd.Flags().GetDuration("provision-delay")
+	deletionDelay, _ := cmd.Flags().GetDuration("deletion-delay")
+	latency, _ := cmd.Flags().GetDuration("latency")
+	errorRate, _ := cmd.Flags().GetFloat64("error-rate")
+	errorStatus, _ := cmd.Flags().GetInt("error-status")
+
+	if errorRate < 0 || errorRate > 1 {
+		return fmt.Errorf("--error-rate must be between 0 and 1")
+	}
+	if errorStatus < 400 || errorStatus > 599 {
+		return fmt.Errorf("--error-status must be an HTTP error status between 400 and 599")
+	}
+
+	server := spottest.NewServer(spottest.WithProvisionDelay(provisionDelay), spottest.WithDeletionDelay(deletionDelay))
+	if seed {
+		server.SeedDefaults()
+	}
+	// Failed requests are delayed too; the others fall through to the latency-only fault
+	if errorRate > 0 {
+		server.InjectFault(spottest.Fault{Latency: latency, Status: errorStatus, RetryAfter: time.Second, Rate: errorRate})
+	}
+	if latency > 0 {
+		server.InjectFault(spottest.Fault{Latency: latency})
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	url := "http://" + listener.Addr().String()
+
+	fmt.Printf("Fake Rackspace Spot API listening on %s\n\n", url)
+	fmt.Println("Point spotctl at it with:")
+	fmt.Printf("  export SPOTCTL_BASE_URL=%s/apis\n", url)
+	fmt.Printf("  export SPOTCTL_OAUTH_URL=%s/oauth/token\n", url)
+	fmt.Printf("  export SPOTCTL_REFRESH_TOKEN=%s\n", spottest.DefaultRefreshToken)
+	if seed {
+		fmt.Printf("  export SPOTCTL_NAMESPACE=%s\n", spottest.DefaultNamespace)
+	}
+	fmt.Println("\nPress Ctrl+C to stop")
+
+	// Canceled by Ctrl-C
+	ctx := cmd.Context()
+
+	httpServer := &http.Server{Handler: server}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("dev server failed: %w", err)
+	}
+	return nil
+}
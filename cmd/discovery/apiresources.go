@@ -0,0 +1,86 @@
+package discovery
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+)
+
+// NewAPIResourcesCommand returns the api-resources command
+func NewAPIResourcesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api-resources",
+		Short: "Print the API resources the server supports",
+		Long: `Print the API resources the server supports, with their short names,
+API version, kind and, with -o wide, the verbs they support and their endpoint.
+
+The resources come from the server's discovery endpoint, for the preferred
+version of each API group or the one selected with --api-version. If the server
+does not support discovery, the resources spotctl knows about are printed instead.
+
+Examples:
+  # List every resource
+  spotctl api-resources
+
+  # Show verbs and endpoints
+  spotctl api-resources -o wide
+
+  # Resources of a preview version
+  spotctl api-resources --api-version ngpc.rxt.io/v2beta1
+
+  # Namespaced resources of one API group
+  spotctl api-resources --api-group ngpc.rxt.io --namespaced`,
+		Args: cobra.NoArgs,
+		RunE: runAPIResources,
+	}
+
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, wide, json, yaml)")
+	cmd.Flags().String("api-group", "", "Only list resources of this API group, e.g. auth.ngpc.rxt.io")
+	cmd.Flags().Bool("namespaced", false, "Only list namespaced resources, or with --namespaced=false cluster scoped ones")
+
+	return cmd
+}
+
+func runAPIResources(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	apiGroup, _ := cmd.Flags().GetString("api-group")
+	namespaced, _ := cmd.Flags().GetBool("namespaced")
+	filterNamespaced := cmd.Flags().Changed("namespaced")
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	apiClient := client.NewClient(cfg)
+	ctx := cmd.Context()
+
+	resources, err := discoverResources(ctx, apiClient)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		warnFallback(cmd.ErrOrStderr(), err, "resources")
+		if resources, err = knownResources(apiClient); err != nil {
+			return err
+		}
+	}
+
+	selected := []apiResource{}
+	for _, r := range resources {
+		if apiGroup != "" && client.APIVersion(r.APIVersion).Group() != apiGroup {
+			continue
+		}
+		if filterNamespaced && r.Namespaced != namespaced {
+			continue
+		}
+		selected = append(selected, r)
+	}
+	sortResources(selected)
+
+	formatter := output.NewFormatter(output.OutputOptions{Format: output.OutputFormat(outputFormat)})
+	return formatter.OutputToWriter(cmd.OutOrStdout(), selected, getAPIResourcesTableConfig())
+}
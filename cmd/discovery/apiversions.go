@@ -0,0 +1,53 @@
+package discovery
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+)
+
+// NewAPIVersionsCommand returns the api-versions command
+func NewAPIVersionsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "api-versions",
+		Short: "Print the API versions the server supports, as group/version",
+		Long: `Print the API versions the server supports, one group/version per line.
+
+The versions come from the server's discovery endpoint. If the server does not
+support discovery, the versions spotctl knows about are printed instead.
+
+Any of them can be selected for requests to its group with --api-version.
+
+Examples:
+  spotctl api-versions`,
+		Args: cobra.NoArgs,
+		RunE: runAPIVersions,
+	}
+}
+
+func runAPIVersions(cmd *cobra.Command, args []string) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	ctx := cmd.Context()
+
+	versions, err := discoverVersions(ctx, client.NewClient(cfg))
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		warnFallback(cmd.ErrOrStderr(), err, "API versions")
+		versions = knownVersions()
+	}
+
+	sort.Strings(versions)
+	for _, version := range versions {
+		fmt.Fprintln(cmd.OutOrStdout(), version)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Package discovery implements the api-versions and api-resources commands
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/output"
+)
+
+// nameList is a list of names printed comma separated in tables
+type nameList []string
+
+func (l nameList) String() string {
+	return strings.Join(l, ",")
+}
+
+// apiResource is a row of api-resources
+type apiResource struct {
+	Name       string   `json:"name" yaml:"name"`
+	ShortNames nameList `json:"shortNames,omitempty" yaml:"shortNames,omitempty"`
+	APIVersion string   `json:"apiVersion" yaml:"apiVersion"`
+	Namespaced bool     `json:"namespaced" yaml:"namespaced"`
+	Kind       string   `json:"kind" yaml:"kind"`
+	Verbs      nameList `json:"verbs" yaml:"verbs"`
+	// Endpoint is the collection path, as accepted by 'spotctl api'
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+}
+
+// newAPIResource returns the row for a resource served by apiVersion
+func newAPIResource(name string, shortNames []string, apiVersion client.APIVersion, namespaced bool, kind string, verbs []string) apiResource {
+	endpoint := "/apis/" + apiVersion.String()
+	if namespaced {
+		endpoint += "/namespaces/{namespace}"
+	}
+	return apiResource{
+		Name:       name,
+		ShortNames: shortNames,
+		APIVersion: apiVersion.String(),
+		Namespaced: namespaced,
+		Kind:       kind,
+		Verbs:      verbs,
+		Endpoint:   endpoint + "/" + name,
+	}
+}
+
+// discoverResources lists the resources of the preferred version of every API
+// group the server serves, or of the api-version override for its group
+func discoverResources(ctx context.Context, apiClient *client.Client) ([]apiResource, error) {
+	groups, err := apiClient.ServerGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []apiResource
+	for _, group := range groups.Groups {
+		list, err := apiClient.ServerResources(ctx, client.APIVersion(group.PreferredVersion.GroupVersion))
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover the resources of %s: %w", group.Name, err)
+		}
+		for _, r := range list.Resources {
+			// Subresources such as cloudspaces/status are not listed
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			resources = append(resources, newAPIResource(r.Name, r.ShortNames, client.APIVersion(list.GroupVersion), r.Namespaced, r.Kind, r.Verbs))
+		}
+	}
+	return resources, nil
+}
+
+// knownResources lists the resources spotctl knows about, with the api-version override applied
+func knownResources(apiClient *client.Client) ([]apiResource, error) {
+	var resources []apiResource
+	for _, r := range client.KnownResources() {
+		apiVersion, err := apiClient.ResolveAPIVersion(r.APIVersion)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, newAPIResource(r.Plural, r.ShortNames, apiVersion, r.Namespaced, r.Kind, r.Verbs))
+	}
+	return resources, nil
+}
+
+// discoverVersions lists the group versions the server serves
+func discoverVersions(ctx context.Context, apiClient *client.Client) ([]string, error) {
+	groups, err := apiClient.ServerGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, group := range groups.Groups {
+		for _, version := range group.Versions {
+			versions = append(versions, version.GroupVersion)
+		}
+	}
+	return versions, nil
+}
+
+// knownVersions lists the group versions spotctl knows about
+func knownVersions() []string {
+	var versions []string
+	for _, version := range client.GetAllAPIVersions() {
+		versions = append(versions, version.String())
+	}
+	return versions
+}
+
+// warnFallback explains on stderr why the static list is shown
+func warnFallback(w io.Writer, err error, what string) {
+	fmt.Fprintf(w, "Server discovery is unavailable (%v); showing the %s known to spotctl\n", err, what)
+}
+
+// sortResources orders resources by name, then API version
+func sortResources(resources []apiResource) {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Name != resources[j].Name {
+			return resources[i].Name < resources[j].Name
+		}
+		return resources[i].APIVersion < resources[j].APIVersion
+	})
+}
+
+// getAPIResourcesTableConfig returns the table configuration for api-resources
+func getAPIResourcesTableConfig() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "NAME", Field: "Name"},
+			{Header: "SHORTNAMES", Field: "ShortNames"},
+			{Header: "APIVERSION", Field: "APIVersion"},
+			{Header: "NAMESPACED", Field: "Namespaced"},
+			{Header: "KIND", Field: "Kind"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "VERBS", Field: "Verbs"},
+			{Header: "ENDPOINT", Field: "Endpoint"},
+		},
+	}
+}
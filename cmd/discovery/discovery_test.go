@@ -0,0 +1,108 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+)
+
+// useServer points the config at a fake API for the duration of the test
+func useServer(t *testing.T, opts ...spottest.Option) {
+	t.Helper()
+	spottest.NewTestServer(t, opts...).Configure(t)
+}
+
+func run(cmd *cobra.Command, args ...string) (string, string, error) {
+	var out, errOut bytes.Buffer
+	cmd.SetArgs(args)
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	err := cmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestAPIVersions(t *testing.T) {
+	useServer(t)
+	out, stderr, err := run(NewAPIVersionsCommand())
+	if err != nil {
+		t.Fatalf("api-versions failed: %v", err)
+	}
+	if out != "auth.ngpc.rxt.io/v1\nngpc.rxt.io/v1\n" || stderr != "" {
+		t.Errorf("Unexpected output %q, stderr %q", out, stderr)
+	}
+}
+
+func TestAPIVersionsFallback(t *testing.T) {
+	useServer(t, spottest.WithoutDiscovery())
+	out, stderr, err := run(NewAPIVersionsCommand())
+	if err != nil {
+		t.Fatalf("api-versions failed: %v", err)
+	}
+	if out != "auth.ngpc.rxt.io/v1\nngpc.rxt.io/v1\n" || !strings.Contains(stderr, "known to spotctl") {
+		t.Errorf("Unexpected output %q, stderr %q", out, stderr)
+	}
+}
+
+func TestAPIResources(t *testing.T) {
+	useServer(t)
+	out, stderr, err := run(NewAPIResourcesCommand(), "-o", "wide")
+	if err != nil {
+		t.Fatalf("api-resources failed: %v", err)
+	}
+	if stderr != "" {
+		t.Errorf("Expected discovery to succeed, got %q", stderr)
+	}
+	for _, want := range []string{"NAME", "VERBS", "cloudspaces", "cs", "CloudSpace", "get,list,create,patch,delete", "/apis/ngpc.rxt.io/v1/namespaces/{namespace}/cloudspaces", "/apis/auth.ngpc.rxt.io/v1/organizations"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+
+	out, _, err = run(NewAPIResourcesCommand(), "-o", "json", "--api-group", "ngpc.rxt.io", "--namespaced=false")
+	if err != nil {
+		t.Fatalf("api-resources failed: %v", err)
+	}
+	var resources []apiResource
+	if err := json.Unmarshal([]byte(out), &resources); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out)
+	}
+	if len(resources) != 2 || resources[0].Name != "regions" || resources[1].Name != "serverclasses" {
+		t.Errorf("Expected the cluster scoped ngpc.rxt.io resources, got %+v", resources)
+	}
+}
+
+func TestAPIResourcesFallback(t *testing.T) {
+	useServer(t, spottest.WithoutDiscovery())
+	viper.Set("api-version", "v2beta1")
+
+	out, stderr, err := run(NewAPIResourcesCommand(), "-o", "json")
+	if err != nil {
+		t.Fatalf("api-resources failed: %v", err)
+	}
+	if !strings.Contains(stderr, "known to spotctl") {
+		t.Errorf("Expected a note about the fallback, got %q", stderr)
+	}
+	var resources []apiResource
+	if err := json.Unmarshal([]byte(out), &resources); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out)
+	}
+	if len(resources) != len(client.KnownResources()) {
+		t.Fatalf("Expected every known resource, got %+v", resources)
+	}
+	for _, r := range resources {
+		want := "ngpc.rxt.io/v2beta1"
+		if r.Name == "organizations" {
+			want = client.APIVersionAuth.String()
+		}
+		if r.APIVersion != want {
+			t.Errorf("Expected %s to use %s, got %s", r.Name, want, r.APIVersion)
+		}
+	}
+}
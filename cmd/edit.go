@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/spf13/cobra"
+)
+
+// newEditCommand creates the generic edit command
+func newEditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit (<type> <name>... | <type>/<name>... | -f <file>) --patch <file>",
+		Short: "Edit resources of any type with JSON patch operations",
+		Long: `Apply the JSON patch operations in a file to resources of any type that
+supports patching. The same patch is applied to each object in turn; any that
+fail are reported and the rest are still edited.
+
+Objects are named as for 'spotctl delete': by a type followed by one or more
+names, by type/name references, or with --filename by a manifest or a list of
+type/name references one per line, as printed by -o name. Use --filename - to
+read them from stdin. The patch is read from --patch/-p, as -f names the
+objects, here and in the per-type edit commands such as 'spotctl spotnodepool edit'.
+
+The patch file holds operations such as:
+[
+  {
+    "op": "replace",
+    "path": "/spec/desired",
+    "value": 5
+  }
+]
+
+Examples:
+  # Edit a spot node pool
+  spotctl edit spotnodepool my-nodepool --patch patch.json
+
+  # Apply the same patch to two spot node pools without confirmation
+  spotctl edit snp/pool-a snp/pool-b --patch patch.json --confirm
+
+  # Scale the spot node pools labelled env=dev in every namespace
+  spotctl spotnodepool list -A -l env=dev -o name | spotctl edit -f - --patch patch.json --confirm
+
+  # Validate the patch with the API without applying it
+  spotctl edit snp my-nodepool --patch patch.json --dry-run=server`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completeTypeAndName,
+		RunE:              runEdit,
+	}
+
+	cmd.Flags().StringP("patch", "p", "", "Path to the JSON file containing patch operations (required)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, wide, json, yaml, name)")
+	cmd.Flags().Bool("confirm", false, "Skip confirmation prompt")
+	cmdutil.AddFilenameFlag(cmd)
+	cmdutil.AddDryRunFlag(cmd)
+	cmd.MarkFlagRequired("patch")
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newEditCommand())
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 && !strings.Contains(args[0], "/") {
+		return fmt.Errorf("no names given: specify the %s to edit after the type", args[0])
+	}
+
+	dryRun, err := cmdutil.DryRun(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Check flags before reading stdin, whose targets cannot be confirmed interactively
+	confirm, _ := cmd.Flags().GetBool("confirm")
+	if err := cmdutil.RequireConfirmForStdin(cmd, confirm, dryRun); err != nil {
+		return err
+	}
+
+	targets, err := cmdutil.Targets(cmd, args)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if !t.Kind.Resource().Supports(client.VerbPatch) {
+			return fmt.Errorf("%s cannot be edited", t.Kind.Resource().Plural)
+		}
+	}
+
+	patchFile, _ := cmd.Flags().GetString("patch")
+	patchOps, err := client.LoadPatchOperations(patchFile)
+	if err != nil {
+		return fmt.Errorf("failed to load patch operations: %w", err)
+	}
+
+	// The patch and prompt go to stderr so that -o json and -o yaml stay parseable
+	errOut := cmd.ErrOrStderr()
+	client.WritePatchOperations(errOut, patchOps)
+	if !confirm && !dryRun {
+		confirmed, err := client.PromptForConfirmationTo(errOut, cmdutil.DescribeTargets(targets))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(errOut, "Patch operation cancelled.")
+			return nil
+		}
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	apiClient := newAPIClient(cfg, dryRun)
+	ctx := cmd.Context()
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	// A single object is shown on its own and fails like any other command
+	if len(targets) == 1 {
+		t := targets[0]
+		object, err := t.Kind.Edit(ctx, apiClient, t.Namespace, t.Name, patchOps)
+		if err != nil {
+			return fmt.Errorf("failed to edit %s '%s': %w", t.Kind.Resource().DisplayName, t.Name, err)
+		}
+		if dryRun {
+			fmt.Fprintf(errOut, "Server dry run: %s '%s' was validated but not changed\n", t.Kind.Resource().DisplayName, t.Name)
+		}
+		return registry.Output(t.Kind, object, outputFormat)
+	}
+
+	// Objects are patched in turn and shown grouped by kind, after reporting any that failed
+	var kinds []registry.Kind
+	objects := map[registry.Kind][]any{}
+	failed := 0
+	for _, t := range targets {
+		object, err := t.Kind.Edit(ctx, apiClient, t.Namespace, t.Name, patchOps)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: failed to edit %s '%s': %v\n", t.Kind.Resource().DisplayName, t.Name, err)
+			failed++
+			continue
+		}
+		if dryRun {
+			fmt.Fprintf(errOut, "Server dry run: %s '%s' was validated but not changed\n", t.Kind.Resource().DisplayName, t.Name)
+		}
+		if _, ok := objects[t.Kind]; !ok {
+			kinds = append(kinds, t.Kind)
+		}
+		objects[t.Kind] = append(objects[t.Kind], object)
+	}
+
+	if err := outputKinds(kinds, objects, outputFormat); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to edit %d of %d objects", failed, len(targets))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/client/clientfake"
+)
+
+func TestEdit(t *testing.T) {
+	patch := filepath.Join(t.TempDir(), "patch.json")
+	if err := os.WriteFile(patch, []byte(`[{"op":"replace","path":"/spec/bidPrice","value":"0.05"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		stdin       string
+		fail        string
+		edited      []string
+		expectError string
+	}{
+		{
+			name:   "names of one type",
+			args:   []string{"spotnodepool", "pool-a", "pool-b", "-p", patch, "--confirm"},
+			edited: []string{"pool-a", "pool-b"},
+		},
+		{
+			name:   "references from stdin",
+			args:   []string{"-f", "-", "-p", patch, "--confirm"},
+			stdin:  "spotnodepool/pool-b\n",
+			edited: []string{"pool-b"},
+		},
+		{
+			name:   "declined",
+			args:   []string{"snp/pool-a", "-p", patch},
+			stdin:  "n\n",
+			edited: nil,
+		},
+		{
+			name:        "one of several fails",
+			args:        []string{"snp/pool-a", "snp/pool-b", "-p", patch, "--confirm"},
+			fail:        "pool-a",
+			edited:      []string{"pool-b"},
+			expectError: "failed to edit 1 of 2 objects",
+		},
+		{
+			name:        "type that cannot be patched",
+			args:        []string{"region", "us-central-dfw-1", "-p", patch, "--confirm"},
+			expectError: "regions cannot be edited",
+		},
+		{
+			name:        "type without names",
+			args:        []string{"spotnodepool", "-p", patch},
+			expectError: "no names given",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clientfake.NewClient(testObjects()...)
+			if tt.fail != "" {
+				fake.FailWith(client.VerbPatch, "spotnodepools", testNamespace, tt.fail, &client.APIError{Code: http.StatusConflict, Message: "conflict"})
+			}
+			useFake(t, fake)
+
+			_, stderr, err := execute(t, newEditCommand(), tt.stdin, append(tt.args, "-o", "name")...)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.expectError, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Edit failed: %v\n%s", err, stderr)
+			}
+
+			for _, name := range []string{"pool-a", "pool-b"} {
+				pool, err := fake.GetSpotNodePool(context.Background(), testNamespace, name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := "0.01"
+				for _, edited := range tt.edited {
+					if edited == name {
+						want = "0.05"
+					}
+				}
+				if pool.Spec.BidPrice != want {
+					t.Errorf("Expected %s to bid %s, got %s", name, want, pool.Spec.BidPrice)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/spf13/cobra"
+)
+
+// nameCompletions completes object names for the types that support it, keyed by kind
+var nameCompletions = map[string]cobra.CompletionFunc{
+	client.CloudSpaceResource.Kind:       completion.CloudSpaceNames,
+	client.SpotNodePoolResource.Kind:     completion.SpotNodePoolNames,
+	client.OnDemandNodePoolResource.Kind: completion.OnDemandNodePoolNames,
+	client.RegionResource.Kind:           completion.RegionNames,
+	client.ServerClassResource.Kind:      completion.ServerClassNames,
+}
+
+// newGetCommand creates the generic get command
+func newGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get (<type> [name...] | <type>/<name>... | -f <file>)",
+		Short: "Display one or many resources of any type",
+		Long: `Display one or many resources of any type.
+
+Without a name every resource of the type is listed; with names only those
+resources are shown. Types can be given by plural, singular or short name, e.g.
+cloudspaces, cloudspace or cs. Run 'spotctl get' without arguments to see them.
+Objects can also be named by type/name references, or with --filename by a
+manifest or a list of type/name references one per line, as printed by -o name.
+
+Namespaced types use the namespace of their manifest, or else the namespace
+from --namespace, --org or your config.
+
+Examples:
+  # List cloudspaces in the default namespace
+  spotctl get cloudspaces
+
+  # Show one spot node pool as YAML
+  spotctl get spotnodepool my-nodepool -o yaml
+
+  # Show two spot node pools and a cloudspace
+  spotctl get snp/pool-a snp/pool-b cs/my-cloudspace
+
+  # List the names of the spot node pools labelled env=dev
+  spotctl get spotnodepools -l env=dev -o name
+
+  # List the first 10 server classes
+  spotctl get sc --limit 10`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completeTypeAndName,
+		RunE:              runGet,
+	}
+
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, wide, json, yaml, csv, name, jsonpath=...)")
+	cmdutil.AddListFlags(cmd)
+	cmdutil.AddFilenameFlag(cmd)
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newGetCommand())
+}
+
+func runGet(cmd *cobra.Command, args []string) error {
+	filenames, _ := cmd.Flags().GetStringArray("filename")
+	if len(args) == 0 && len(filenames) == 0 {
+		return fmt.Errorf("a resource type is required: valid types are %s", strings.Join(registry.Plurals(), ", "))
+	}
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	// A type on its own lists every resource of the type
+	if len(args) == 1 && len(filenames) == 0 && !strings.Contains(args[0], "/") {
+		return runList(cmd, args[0], outputFormat)
+	}
+
+	targets, err := cmdutil.Targets(cmd, args)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if !t.Kind.Resource().Supports(client.VerbGet) {
+			return fmt.Errorf("%s cannot be retrieved with %s", t.Kind.Resource().Plural, client.VerbGet)
+		}
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	apiClient := newAPIClient(cfg, false)
+	ctx := cmd.Context()
+
+	// A single object is shown on its own and fails like any other command
+	if len(targets) == 1 {
+		t := targets[0]
+		object, err := t.Kind.Get(ctx, apiClient, t.Namespace, t.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get %s '%s': %w", t.Kind.Resource().DisplayName, t.Name, err)
+		}
+		return registry.Output(t.Kind, object, outputFormat)
+	}
+
+	// Objects are grouped by kind, as each kind has its own table
+	var kinds []registry.Kind
+	objects := map[registry.Kind][]any{}
+	failed := 0
+	for _, t := range targets {
+		object, err := t.Kind.Get(ctx, apiClient, t.Namespace, t.Name)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: failed to get %s '%s': %v\n", t.Kind.Resource().DisplayName, t.Name, err)
+			failed++
+			continue
+		}
+		if _, ok := objects[t.Kind]; !ok {
+			kinds = append(kinds, t.Kind)
+		}
+		objects[t.Kind] = append(objects[t.Kind], object)
+	}
+
+	if err := outputKinds(kinds, objects, outputFormat); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to get %d of %d objects", failed, len(targets))
+	}
+	return nil
+}
+
+// runList lists every resource of a type
+func runList(cmd *cobra.Command, resourceType, outputFormat string) error {
+	kind, err := registry.Lookup(resourceType)
+	if err != nil {
+		return err
+	}
+	resource := kind.Resource()
+	if !resource.Supports(client.VerbList) {
+		return fmt.Errorf("%s cannot be retrieved with %s", resource.Plural, client.VerbList)
+	}
+
+	namespace, err := kindNamespace(cmd, resource)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	apiClient := newAPIClient(cfg, false)
+
+	listOpts, limit, err := cmdutil.ListFlags(cmd)
+	if err != nil {
+		return err
+	}
+	objects, err := kind.List(cmd.Context(), apiClient, namespace, listOpts, limit)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", resource.Plural, err)
+	}
+	return registry.Output(kind, objects, outputFormat)
+}
+
+// outputKinds writes the objects of each kind in turn. JSON and YAML write all
+// of them as one list, so the output stays a single document.
+func outputKinds(kinds []registry.Kind, objects map[registry.Kind][]any, outputFormat string) error {
+	if len(kinds) == 0 {
+		return nil
+	}
+	if len(kinds) > 1 && (outputFormat == string(output.JSONFormat) || outputFormat == string(output.YAMLFormat) || strings.HasPrefix(outputFormat, output.JSONPathPrefix)) {
+		var all []any
+		for _, kind := range kinds {
+			all = append(all, objects[kind]...)
+		}
+		return registry.Output(kinds[0], all, outputFormat)
+	}
+
+	for i, kind := range kinds {
+		// Separate the tables of different kinds
+		if i > 0 && (outputFormat == string(output.TableFormat) || outputFormat == string(output.WideFormat)) {
+			fmt.Println()
+		}
+		if err := registry.Output(kind, objects[kind], outputFormat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// kindNamespace resolves the namespace for namespaced kinds; other kinds need none
+func kindNamespace(cmd *cobra.Command, resource client.Resource) (string, error) {
+	if !resource.Namespaced {
+		return "", nil
+	}
+	return cmdutil.ResolveNamespace(cmd)
+}
+
+// completeTypeAndName completes a resource type, then the name of a resource of that type
+func completeTypeAndName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		var types []string
+		for _, plural := range registry.Plurals() {
+			if strings.HasPrefix(plural, toComplete) {
+				types = append(types, plural)
+			}
+		}
+		return types, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	kind, err := registry.Lookup(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	complete, ok := nameCompletions[kind.Resource().Kind]
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// Any number of names can follow the type
+	return completion.Each(complete)(cmd, args[1:], toComplete)
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/client/clientfake"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const testNamespace = "org-abc123"
+
+// useFake makes the generic verbs use fake, with the config they need to run
+func useFake(t *testing.T, fake *clientfake.Client) {
+	t.Helper()
+	viper.Reset()
+	viper.Set("refresh-token", "test-token")
+	viper.Set("namespace", testNamespace)
+	t.Cleanup(viper.Reset)
+
+	original := newAPIClient
+	newAPIClient = func(*config.Config, bool) client.Interface { return fake }
+	t.Cleanup(func() { newAPIClient = original })
+}
+
+// execute runs cmd with args, returning what it wrote to stdout and stderr.
+// Objects are printed to os.Stdout, which is captured along with cmd's output.
+func execute(t *testing.T, cmd *cobra.Command, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	captured := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		captured <- string(data)
+	}()
+
+	var out, errOut bytes.Buffer
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	err = cmd.Execute()
+
+	os.Stdout = stdout
+	w.Close()
+	return <-captured + out.String(), errOut.String(), err
+}
+
+func testObjects() []any {
+	meta := func(name string) client.ObjectMeta {
+		return client.ObjectMeta{Name: name, Namespace: testNamespace}
+	}
+	return []any{
+		client.CloudSpace{Metadata: meta("web"), Spec: client.CloudSpaceSpec{Region: "us-central-dfw-1"}},
+		client.CloudSpace{Metadata: meta("api"), Spec: client.CloudSpaceSpec{Region: "us-central-dfw-1"}},
+		client.SpotNodePool{Metadata: meta("pool-a"), Spec: client.SpotNodePoolSpec{ServerClass: "gp.vs1.medium-dfw", BidPrice: "0.01", CloudSpace: "web"}},
+		client.SpotNodePool{Metadata: meta("pool-b"), Spec: client.SpotNodePoolSpec{ServerClass: "gp.vs1.medium-dfw", BidPrice: "0.01", CloudSpace: "api"}},
+		client.Region{Metadata: client.ObjectMeta{Name: "us-central-dfw-1"}},
+	}
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expected    []string
+		unexpected  []string
+		expectError string
+	}{
+		{
+			name:     "list a type by short name",
+			args:     []string{"cs", "-o", "name"},
+			expected: []string{"cloudspace/api", "cloudspace/web"},
+		},
+		{
+			name:       "cluster scoped type",
+			args:       []string{"regions", "-o", "name"},
+			expected:   []string{"region/us-central-dfw-1"},
+			unexpected: []string{"cloudspace"},
+		},
+		{
+			name:       "names of one type",
+			args:       []string{"spotnodepool", "pool-b", "-o", "name"},
+			expected:   []string{"spotnodepool/pool-b"},
+			unexpected: []string{"pool-a"},
+		},
+		{
+			name:     "references to several types",
+			args:     []string{"snp/pool-a", "cs/web", "-o", "name"},
+			expected: []string{"spotnodepool/pool-a", "cloudspace/web"},
+		},
+		{
+			name:        "missing object",
+			args:        []string{"cloudspace", "missing"},
+			expectError: "failed to get cloudspace 'missing'",
+		},
+		{
+			name:        "unknown type",
+			args:        []string{"widgets"},
+			expectError: "widgets",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFake(t, clientfake.NewClient(testObjects()...))
+
+			stdout, _, err := execute(t, newGetCommand(), "", tt.args...)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(stdout, expected) {
+					t.Errorf("Expected output to contain %q, got:\n%s", expected, stdout)
+				}
+			}
+			for _, unexpected := range tt.unexpected {
+				if strings.Contains(stdout, unexpected) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unexpected, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestGetReportsEachFailure(t *testing.T) {
+	fake := clientfake.NewClient(testObjects()...)
+	fake.FailWith(client.VerbGet, "spotnodepools", testNamespace, "pool-b", &client.APIError{Code: http.StatusForbidden, Message: "forbidden"})
+	useFake(t, fake)
+
+	stdout, stderr, err := execute(t, newGetCommand(), "", "snp/pool-a", "snp/pool-b", "snp/pool-c", "-o", "name")
+	if err == nil || !strings.Contains(err.Error(), "failed to get 2 of 3 objects") {
+		t.Fatalf("Expected 2 of 3 gets to fail, got %v", err)
+	}
+	if !strings.Contains(stdout, "spotnodepool/pool-a") {
+		t.Errorf("Expected the pool that was found to be shown, got:\n%s", stdout)
+	}
+	for _, expected := range []string{"'pool-b': API error 403", "'pool-c': API error 404"} {
+		if !strings.Contains(stderr, expected) {
+			t.Errorf("Expected stderr to contain %q, got:\n%s", expected, stderr)
+		}
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -18,8 +21,10 @@ func NewGetCommand() *cobra.Command {
 
 The namespace can be specified via:
 - The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
 - The 'namespace' field in your config file
 - The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
 
 Examples:
   # Get an on demand node pool using namespace from config
@@ -29,13 +34,17 @@ Examples:
   spotctl ondemandnodepool get my-pool --namespace org-abc123
 
   # Get with JSON output
-  spotctl ondemandnodepool get my-pool --output json`,
-		Args: cobra.ExactArgs(1),
-		RunE: runGet,
+  spotctl ondemandnodepool get my-pool --output json
+
+  # Get several on demand node pools
+  spotctl ondemandnodepool get pool-a pool-b`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completion.Each(completion.OnDemandNodePoolNames),
+		RunE:              runGet,
 	}
 
 	// Add flags for ondemandnodepool get command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
 	cmd.Flags().StringP("namespace", "n", "", "Namespace of the on demand node pool (overrides config)")
 
 	return cmd
@@ -44,7 +53,7 @@ Examples:
 func runGet(cmd *cobra.Command, args []string) error {
 	onDemandNodePoolName := args[0] // Get name from positional argument
 
-	namespace, err := getNamespace(cmd)
+	namespace, err := cmdutil.ResolveNamespace(cmd)
 	if err != nil {
 		return err
 	}
@@ -56,13 +65,26 @@ func runGet(cmd *cobra.Command, args []string) error {
 
 	apiClient := client.NewClient(cfg)
 
-	ctx := context.Background()
+	ctx := cmd.Context()
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	// Several on demand node pools are shown as a list, after reporting any that could not be found
+	if len(args) > 1 {
+		items, getErr := cmdutil.EachName(ctx, args, cmd.ErrOrStderr(), "get", "on demand node pool", func(ctx context.Context, name string) (*client.OnDemandNodePool, error) {
+			return apiClient.GetOnDemandNodePool(ctx, namespace, name)
+		})
+		if len(items) > 0 {
+			if err := registry.OutputList(registry.OnDemandNodePools, items, outputFormat, namespace); err != nil {
+				return err
+			}
+		}
+		return getErr
+	}
+
 	onDemandNodePool, err := apiClient.GetOnDemandNodePool(ctx, namespace, onDemandNodePoolName)
 	if err != nil {
 		return fmt.Errorf("failed to get on demand node pool: %w", err)
 	}
 
-	outputFormat, _ := cmd.Flags().GetString("output")
-
-	return outputOnDemandNodePool(onDemandNodePool, outputFormat)
+	return registry.Output(registry.OnDemandNodePools, onDemandNodePool, outputFormat)
 }
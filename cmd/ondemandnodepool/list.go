@@ -1,11 +1,12 @@
 package ondemandnodepools
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -18,8 +19,10 @@ func NewListCommand() *cobra.Command {
 
 The namespace can be specified via:
 - The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
 - The 'namespace' field in your config file
 - The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
 
 Examples:
   # List on demand node pools using namespace from config
@@ -32,20 +35,41 @@ Examples:
   spotctl ondemandnodepool list --output wide
 
   # List with JSON output
-  spotctl ondemandnodepool list --output json`,
+  spotctl ondemandnodepool list --output json
+
+  # List on demand node pools in the namespaces of all your organizations
+  spotctl ondemandnodepool list -A`,
 		Args: cobra.NoArgs,
 		RunE: runList,
 	}
 
 	// Add flags for ondemandnodepool list command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
 	cmd.Flags().StringP("namespace", "n", "", "Namespace to list on demand node pools from (overrides config)")
+	cmdutil.AddListFlags(cmd)
+	cmdutil.AddAllNamespacesFlag(cmd)
 
 	return cmd
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	namespace, err := getNamespace(cmd)
+	allNamespaces, err := cmdutil.AllNamespaces(cmd)
+	if err != nil {
+		return err
+	}
+
+	listOpts, limit, err := cmdutil.ListFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	if allNamespaces {
+		return cmdutil.ListKindAllNamespaces(cmd, registry.OnDemandNodePools, listOpts, limit, outputFormat)
+	}
+
+	namespace, err := cmdutil.ResolveNamespace(cmd)
 	if err != nil {
 		return err
 	}
@@ -57,13 +81,11 @@ func runList(cmd *cobra.Command, args []string) error {
 
 	apiClient := client.NewClient(cfg)
 
-	ctx := context.Background()
-	onDemandNodePoolList, err := apiClient.ListOnDemandNodePools(ctx, namespace)
+	ctx := cmd.Context()
+	items, err := client.Collect(apiClient.AllOnDemandNodePools(ctx, namespace, listOpts), limit)
 	if err != nil {
 		return fmt.Errorf("failed to list on demand node pools: %w", err)
 	}
 
-	outputFormat, _ := cmd.Flags().GetString("output")
-
-	return outputOnDemandNodePools(onDemandNodePoolList, outputFormat, namespace)
+	return registry.OutputList(registry.OnDemandNodePools, items, outputFormat, namespace)
 }
@@ -1,6 +1,8 @@
 package ondemandnodepools
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/spf13/cobra"
+)
 
 // NewCommand returns the main ondemandnodepool command with all subcommands
 func NewCommand() *cobra.Command {
@@ -1,11 +1,12 @@
 package organizations
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -22,27 +23,34 @@ your authenticated account, including organization details and metadata.`,
 	}
 
 	// Add flags for organizations list command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
+	cmdutil.AddListFlags(cmd)
 
 	return cmd
 }
 
 func runOrganizationsList(cmd *cobra.Command, args []string) error {
+	listOpts, limit, err := cmdutil.ListFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := config.GetConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	client := client.NewClient(cfg)
+	apiClient := client.NewClient(cfg)
 
-	ctx := context.Background()
-	orgList, err := client.ListOrganizations(ctx)
+	ctx := cmd.Context()
+	items, err := client.Collect(apiClient.AllOrganizations(ctx, listOpts), limit)
 	if err != nil {
 		return fmt.Errorf("failed to list organizations: %w", err)
 	}
+	orgList := &client.OrganizationList{Organizations: items}
 
 	// Get flag values
 	outputFormat, _ := cmd.Flags().GetString("output")
 
-	return outputOrganizations(orgList, outputFormat)
+	return registry.OutputList(registry.Organizations, orgList.Organizations, outputFormat, "")
 }
@@ -1,6 +1,8 @@
 package organizations
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/spf13/cobra"
+)
 
 // NewCommand returns the main organizations command with all subcommands
 func NewCommand() *cobra.Command {
@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -21,13 +24,15 @@ by its name, including provider details, location, and metadata.
 
 Examples:
   rackspace-spot regions get uk-lon-1
-  rackspace-spot regions get us-central-dfw-1 -o json`,
-		Args: cobra.ExactArgs(1),
-		RunE: runGet,
+  rackspace-spot regions get us-central-dfw-1 -o json
+  rackspace-spot regions get uk-lon-1 us-central-dfw-1`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completion.Each(completion.RegionNames),
+		RunE:              runGet,
 	}
 
 	// Add flags for get command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
 
 	return cmd
 }
@@ -40,20 +45,34 @@ func runGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if cfg.RefreshToken == "" {
+	if cfg.RefreshToken == "" && cfg.Replay == "" {
 		return fmt.Errorf("refresh token not configured. Run 'rackspace-spot-cli config init' to set up authentication")
 	}
 
-	client := client.NewClient(cfg)
+	apiClient := client.NewClient(cfg)
 
-	ctx := context.Background()
-	region, err := client.GetRegion(ctx, regionName)
-	if err != nil {
-		return fmt.Errorf("failed to get region '%s': %w", regionName, err)
-	}
+	ctx := cmd.Context()
 
 	// Get flag values
 	outputFormat, _ := cmd.Flags().GetString("output")
 
-	return outputRegion(region, outputFormat)
+	// Several regions are shown as a list, after reporting any that could not be found
+	if len(args) > 1 {
+		items, getErr := cmdutil.EachName(ctx, args, cmd.ErrOrStderr(), "get", "region", func(ctx context.Context, name string) (*client.Region, error) {
+			return apiClient.GetRegion(ctx, name)
+		})
+		if len(items) > 0 {
+			if err := registry.OutputList(registry.Regions, &client.RegionList{Items: items}, outputFormat, ""); err != nil {
+				return err
+			}
+		}
+		return getErr
+	}
+
+	region, err := apiClient.GetRegion(ctx, regionName)
+	if err != nil {
+		return fmt.Errorf("failed to get region '%s': %w", regionName, err)
+	}
+
+	return registry.Output(registry.Regions, region, outputFormat)
 }
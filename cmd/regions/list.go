@@ -1,11 +1,12 @@
 package regions
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -23,31 +24,38 @@ information and location.`,
 	}
 
 	// Add flags for list command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
+	cmdutil.AddListFlags(cmd)
 
 	return cmd
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	listOpts, limit, err := cmdutil.ListFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := config.GetConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if cfg.RefreshToken == "" {
+	if cfg.RefreshToken == "" && cfg.Replay == "" {
 		return fmt.Errorf("refresh token not configured. Run 'rackspace-spot-cli config init' to set up authentication")
 	}
 
-	client := client.NewClient(cfg)
+	apiClient := client.NewClient(cfg)
 
-	ctx := context.Background()
-	regionList, err := client.ListRegions(ctx)
+	ctx := cmd.Context()
+	items, err := client.Collect(apiClient.AllRegions(ctx, listOpts), limit)
 	if err != nil {
 		return fmt.Errorf("failed to list regions: %w", err)
 	}
+	regionList := &client.RegionList{Items: items}
 
 	// Get flag values
 	outputFormat, _ := cmd.Flags().GetString("output")
 
-	return outputRegions(regionList, outputFormat)
+	return registry.OutputList(registry.Regions, regionList, outputFormat, "")
 }
@@ -1,6 +1,8 @@
 package regions
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/spf13/cobra"
+)
 
 // NewCommand returns the main regions command with all subcommands
 func NewCommand() *cobra.Command {
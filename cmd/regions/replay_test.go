@@ -0,0 +1,54 @@
+package regions
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// captureStdout returns what fn writes to standard output
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	runErr := fn()
+	os.Stdout = stdout
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runErr != nil {
+		t.Fatalf("Command failed: %v", runErr)
+	}
+	return string(out)
+}
+
+// TestRegionsListReplay runs the list command against a recorded API session and
+// compares its output with the golden file next to the cassette
+func TestRegionsListReplay(t *testing.T) {
+	dir := filepath.Join("testdata", "list")
+	viper.Set("replay", dir)
+	viper.Set("no-pager", true)
+	t.Cleanup(viper.Reset)
+
+	cmd := NewCommand()
+	cmd.SetArgs([]string{"list"})
+	got := captureStdout(t, cmd.Execute)
+
+	want, err := os.ReadFile(filepath.Join(dir, "golden.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("Output does not match %s:\ngot:\n%s\nwant:\n%s", filepath.Join(dir, "golden.txt"), got, want)
+	}
+}
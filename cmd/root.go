@@ -1,18 +1,29 @@
 package cmd
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"log/slog"
 	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/georgetaylor/spotctl/cmd/api"
+	"github.com/georgetaylor/spotctl/cmd/auth"
 	"github.com/georgetaylor/spotctl/cmd/cloudspaces"
+	"github.com/georgetaylor/spotctl/cmd/cost"
+	"github.com/georgetaylor/spotctl/cmd/discovery"
 	ondemandnodepools "github.com/georgetaylor/spotctl/cmd/ondemandnodepool"
 	"github.com/georgetaylor/spotctl/cmd/organizations"
 	"github.com/georgetaylor/spotctl/cmd/regions"
 	"github.com/georgetaylor/spotctl/cmd/serverclasses"
 	"github.com/georgetaylor/spotctl/cmd/spotnodepool"
+	"github.com/georgetaylor/spotctl/pkg/completion"
+	"github.com/georgetaylor/spotctl/pkg/logging"
 )
 
 var cfgFile string
@@ -26,12 +37,82 @@ with Rackspace Spot resources through their public API.
 
 This tool allows you to manage spot instances, monitor pricing, 
 and perform various operations on your Rackspace Spot infrastructure.`,
+	PersistentPreRunE: preRun,
 }
 
+// Exit codes for commands that were cut short, distinct from the 1 of other failures
+const (
+	// ExitTimeout is returned when --timeout expires, as timeout(1) does
+	ExitTimeout = 124
+	// ExitInterrupted is returned on SIGINT or SIGTERM, as shells report Ctrl-C
+	ExitInterrupted = 130
+)
+
+var (
+	// interrupted is set when SIGINT or SIGTERM cancels the command
+	interrupted atomic.Bool
+	// timeoutCtx expires after --timeout; nil when no timeout is set
+	timeoutCtx    context.Context
+	cancelTimeout context.CancelFunc = func() {}
+)
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() error {
-	return rootCmd.Execute()
+	// Registered last so flags of every subcommand, wherever it was added, are covered
+	completion.RegisterFlagCompletions(rootCmd)
+
+	// The first Ctrl-C cancels the command's context and with it any in-flight
+	// request; a second one kills the process as usual
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		<-signals
+		signal.Stop(signals)
+		interrupted.Store(true)
+		cancel()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	return err
+}
+
+// preRun runs before every command, once its flags and config are loaded
+func preRun(cmd *cobra.Command, args []string) error {
+	// Warned here rather than per API client, so commands that make several say it once
+	if viper.GetBool("insecure-skip-tls-verify") {
+		slog.Warn("TLS certificate verification is disabled (insecure-skip-tls-verify); connections to the API and OAuth endpoints can be intercepted")
+	}
+	return applyTimeout(cmd, args)
+}
+
+// applyTimeout bounds the command's context by --timeout
+func applyTimeout(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		return nil
+	}
+	timeoutCtx, cancelTimeout = context.WithTimeout(cmd.Context(), timeout)
+	cmd.SetContext(timeoutCtx)
+	return nil
+}
+
+// ExitCode returns the process exit code for the error a command returned:
+// ExitInterrupted if it was interrupted, ExitTimeout if --timeout expired and 1 otherwise
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case interrupted.Load():
+		return ExitInterrupted
+	case timeoutCtx != nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded):
+		return ExitTimeout
+	}
+	return 1
 }
 
 func init() {
@@ -42,18 +123,48 @@ func init() {
 	rootCmd.PersistentFlags().String("refresh-token", "", "Rackspace Spot refresh token")
 	rootCmd.PersistentFlags().String("region", "", "Rackspace region")
 	rootCmd.PersistentFlags().StringP("namespace", "n", "", "Default namespace for operations")
-	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug output")
+	rootCmd.PersistentFlags().String("org", "", "Organization (name, display name or ID) whose namespace to use")
+	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug logging (same as --log-level=debug)")
+	rootCmd.PersistentFlags().CountP("verbose", "v", "Log more detail to stderr: -v for info, -vv for debug")
+	rootCmd.PersistentFlags().String("log-level", "", "Log level: debug, info, warn or error (overrides -v)")
+	rootCmd.PersistentFlags().String("log-format", logging.FormatText, "Log format: text or json")
+	rootCmd.PersistentFlags().Bool("http-trace", false, "Log every HTTP request and response, with credentials redacted")
 	rootCmd.PersistentFlags().Bool("no-pager", false, "Disable pager for long output")
+	rootCmd.PersistentFlags().String("record", "", "Record API requests and responses, with credentials redacted, to a cassette in this directory")
+	rootCmd.PersistentFlags().String("replay", "", "Answer API requests from the cassette in this directory instead of the network")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.PersistentFlags().String("ca-file", "", "PEM bundle of extra CA certificates to trust, e.g. for a TLS-inspecting proxy")
+	rootCmd.PersistentFlags().Bool("insecure-skip-tls-verify", false, "Skip TLS certificate verification (insecure)")
+	rootCmd.PersistentFlags().String("client-cert", "", "PEM client certificate for mutual TLS")
+	rootCmd.PersistentFlags().String("client-key", "", "PEM key for --client-cert")
+	rootCmd.PersistentFlags().String("proxy-url", "", "Proxy for API and OAuth requests (default from HTTPS_PROXY)")
+	rootCmd.PersistentFlags().String("api-version", "", "API version for requests to its group, e.g. ngpc.rxt.io/v2beta1 or v2beta1 (default per resource)")
+	rootCmd.PersistentFlags().Duration("request-timeout", 0, "Timeout for each API request, e.g. 45s (default from the timeout config setting, 30s)")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Overall time limit for the command, including waits and bulk operations, e.g. 10m (default none)")
 
 	// Bind flags to viper
 	viper.BindPFlag("refresh-token", rootCmd.PersistentFlags().Lookup("refresh-token"))
 	viper.BindPFlag("region", rootCmd.PersistentFlags().Lookup("region"))
 	viper.BindPFlag("namespace", rootCmd.PersistentFlags().Lookup("namespace"))
+	viper.BindPFlag("org", rootCmd.PersistentFlags().Lookup("org"))
 	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
+	viper.BindPFlag("log-level", rootCmd.PersistentFlags().Lookup("log-level"))
+	viper.BindPFlag("log-format", rootCmd.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("http-trace", rootCmd.PersistentFlags().Lookup("http-trace"))
 	viper.BindPFlag("no-pager", rootCmd.PersistentFlags().Lookup("no-pager"))
+	viper.BindPFlag("record", rootCmd.PersistentFlags().Lookup("record"))
+	viper.BindPFlag("replay", rootCmd.PersistentFlags().Lookup("replay"))
+	for _, name := range []string{"ca-file", "insecure-skip-tls-verify", "client-cert", "client-key", "proxy-url", "request-timeout", "api-version"} {
+		viper.BindPFlag(name, rootCmd.PersistentFlags().Lookup(name))
+	}
 
 	// Register commands
+	rootCmd.AddCommand(api.NewCommand())
+	rootCmd.AddCommand(discovery.NewAPIResourcesCommand())
+	rootCmd.AddCommand(discovery.NewAPIVersionsCommand())
+	rootCmd.AddCommand(auth.NewCommand())
 	rootCmd.AddCommand(cloudspaces.NewCommand())
+	rootCmd.AddCommand(cost.NewCommand())
 	rootCmd.AddCommand(ondemandnodepools.NewCommand())
 	rootCmd.AddCommand(organizations.NewCommand())
 	rootCmd.AddCommand(regions.NewCommand())
@@ -84,13 +195,52 @@ func initConfig() {
 	// Explicitly bind environment variables to handle hyphenated keys
 	viper.BindEnv("refresh-token", "SPOTCTL_REFRESH_TOKEN")
 	viper.BindEnv("namespace", "SPOTCTL_NAMESPACE")
+	viper.BindEnv("org", "SPOTCTL_ORG")
 	viper.BindEnv("base-url", "SPOTCTL_BASE_URL")
+	viper.BindEnv("oauth-url", "SPOTCTL_OAUTH_URL")
+	viper.BindEnv("device-auth-url", "SPOTCTL_DEVICE_AUTH_URL")
 	viper.BindEnv("no-pager", "SPOTCTL_NO_PAGER")
+	viper.BindEnv("log-level", "SPOTCTL_LOG_LEVEL")
+	viper.BindEnv("log-format", "SPOTCTL_LOG_FORMAT")
+	viper.BindEnv("ca-file", "SPOTCTL_CA_FILE")
+	viper.BindEnv("insecure-skip-tls-verify", "SPOTCTL_INSECURE_SKIP_TLS_VERIFY")
+	viper.BindEnv("client-cert", "SPOTCTL_CLIENT_CERT")
+	viper.BindEnv("client-key", "SPOTCTL_CLIENT_KEY")
+	viper.BindEnv("proxy-url", "SPOTCTL_PROXY_URL")
+	viper.BindEnv("request-timeout", "SPOTCTL_REQUEST_TIMEOUT")
+	viper.BindEnv("api-version", "SPOTCTL_API_VERSION")
+
+	cobra.CheckErr(initLogging())
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		if viper.GetBool("debug") {
-			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+		slog.Debug("Using config file", "path", viper.ConfigFileUsed())
+	}
+}
+
+// initLogging sets the default logger from --log-level, -v, --debug and --log-format.
+// Logs go to stderr so they never corrupt command output.
+func initLogging() error {
+	verbosity, _ := rootCmd.PersistentFlags().GetCount("verbose")
+	level := logging.VerbosityLevel(verbosity)
+	if viper.GetBool("debug") {
+		level = slog.LevelDebug
+	}
+	if value := viper.GetString("log-level"); value != "" {
+		var err error
+		if level, err = logging.ParseLevel(value); err != nil {
+			return err
 		}
 	}
+	// Traces are logged at debug level, so asking for them implies it
+	if viper.GetBool("http-trace") {
+		level = min(level, slog.LevelDebug)
+	}
+
+	logger, err := logging.New(os.Stderr, level, viper.GetString("log-format"))
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
 }
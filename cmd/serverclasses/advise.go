@@ -0,0 +1,246 @@
+package serverclasses
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/pricing"
+	"github.com/spf13/cobra"
+)
+
+// NewAdviseCommand creates the serverclasses advise command
+func NewAdviseCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "advise <name>",
+		Short: "Recommend a bid price for a server class",
+		Long: `Recommend a spot bid price for a server class.
+
+The recommendation combines the current availability, capacity, market price and
+last hammer price of the server class with its auction price history and price
+percentiles. The --risk flag controls how often the bid should have won recent
+auctions:
+
+  low     bid to win about 95% of auctions
+  medium  bid to win about 80% of auctions (default)
+  high    bid to win about 50% of auctions
+
+The bid is never lower than the current market price and is raised further when
+few servers are available. The reasoning and the estimated probability of
+winning are shown with the recommendation.
+
+With --apply the recommended bid is written to the spec.bidPrice of an existing
+spot node pool. The namespace of the pool can be specified via:
+- The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
+- The 'namespace' field in your config file
+- The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
+
+Examples:
+  # Recommend a bid for 3 nodes
+  spotctl serverclasses advise gp.vs1.large-lon --nodes 3
+
+  # Bid conservatively and output the recommendation as JSON
+  spotctl serverclasses advise gp.vs1.large-lon --risk low -o json
+
+  # Apply the recommendation to an existing spot node pool
+  spotctl serverclasses advise gp.vs1.large-lon --nodes 3 --apply my-nodepool -n org-abc123`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ServerClassNames,
+		RunE:              runAdvise,
+	}
+
+	// Add flags for advise command
+	cmd.Flags().Int("nodes", 1, "Number of nodes the bid is for")
+	cmd.Flags().String("risk", string(pricing.RiskMedium), "Acceptable risk of losing the auction (low, medium, high)")
+	cmd.Flags().StringP("output", "o", "text", "Output format (text, json, yaml)")
+	cmd.Flags().String("apply", "", "Set the recommended bid price on this spot node pool")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of the spot node pool to apply to (overrides config)")
+	cmd.Flags().Bool("confirm", false, "Skip confirmation prompt when applying")
+
+	return cmd
+}
+
+func runAdvise(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	nodes, _ := cmd.Flags().GetInt("nodes")
+	riskFlag, _ := cmd.Flags().GetString("risk")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	applyTo, _ := cmd.Flags().GetString("apply")
+
+	risk, err := pricing.ParseRisk(riskFlag)
+	if err != nil {
+		return err
+	}
+	if outputFormat != "text" && outputFormat != "json" && outputFormat != "yaml" {
+		return fmt.Errorf("unsupported output format: %s (use text, json or yaml)", outputFormat)
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+	input, err := fetchAdviceInput(ctx, apiClient, name)
+	if err != nil {
+		return err
+	}
+	input.Nodes = nodes
+	input.Risk = risk
+
+	advice, err := pricing.Advise(*input)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "text" {
+		if err := writeAdvice(cmd.OutOrStdout(), advice); err != nil {
+			return err
+		}
+	} else {
+		formatter := output.NewFormatter(output.OutputOptions{Format: output.OutputFormat(outputFormat)})
+		if err := formatter.OutputToWriter(cmd.OutOrStdout(), advice, nil); err != nil {
+			return err
+		}
+	}
+
+	if applyTo == "" {
+		return nil
+	}
+	return applyBidPrice(cmd, apiClient, applyTo, advice)
+}
+
+// fetchAdviceInput fetches a server class with its price history and percentiles concurrently.
+// History and percentiles the API has none of are optional: the advisor falls back to the market
+// price without them, and says so. Any other failure to fetch them fails the advice.
+func fetchAdviceInput(ctx context.Context, apiClient *client.Client, name string) (*pricing.AdviceInput, error) {
+	var (
+		serverClass    *client.ServerClass
+		history        *client.PriceHistory
+		percentiles    *client.PercentileInfo
+		serverClassErr error
+		historyErr     error
+		percentilesErr error
+		wg             sync.WaitGroup
+	)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		serverClass, serverClassErr = apiClient.GetServerClass(ctx, name)
+	}()
+	go func() {
+		defer wg.Done()
+		history, historyErr = apiClient.GetPriceHistory(ctx, name)
+	}()
+	go func() {
+		defer wg.Done()
+		percentiles, percentilesErr = apiClient.GetPercentileInfo(ctx, name)
+	}()
+	wg.Wait()
+
+	if serverClassErr != nil {
+		return nil, fmt.Errorf("failed to get server class '%s': %w", name, serverClassErr)
+	}
+	// Only missing data is optional; any other failure would silently weaken the advice
+	if historyErr != nil && !client.IsNotFound(historyErr) {
+		return nil, fmt.Errorf("failed to get price history of server class '%s': %w", name, historyErr)
+	}
+	if percentilesErr != nil && !client.IsNotFound(percentilesErr) {
+		return nil, fmt.Errorf("failed to get price percentiles of server class '%s': %w", name, percentilesErr)
+	}
+
+	input := &pricing.AdviceInput{
+		ServerClass: *serverClass,
+		History:     pricing.ParsePriceHistory(history),
+		Percentiles: pricing.ParsePercentiles(percentiles),
+	}
+	if historyErr != nil {
+		input.Unavailable = append(input.Unavailable, "price history")
+	}
+	if percentilesErr != nil {
+		input.Unavailable = append(input.Unavailable, "price percentiles")
+	}
+	return input, nil
+}
+
+// writeAdvice writes a bid recommendation and its reasoning in human-readable form
+func writeAdvice(w io.Writer, advice *pricing.Advice) error {
+	d := output.NewDescribeWriter(w)
+	d.Field(0, "Server Class", advice.ServerClass)
+	d.Field(0, "Nodes", fmt.Sprintf("%d", advice.Nodes))
+	d.Field(0, "Risk", string(advice.Risk))
+	d.Field(0, "Recommended Bid", fmt.Sprintf("$%s/hour per node", advice.BidPrice))
+
+	probability := "unknown"
+	if advice.WinProbability != nil {
+		probability = fmt.Sprintf("%.0f%%", *advice.WinProbability*100)
+	}
+	d.Field(0, "Win Probability", probability)
+	d.Field(0, "Max Cost", fmt.Sprintf("%s/hour, %s/month", advice.MaxHourlyCost.Dollars(), advice.MaxHourlyCost.Monthly().Dollars()))
+	d.List(0, "Reasoning", advice.Reasons)
+	if len(advice.Warnings) > 0 {
+		d.List(0, "Warnings", advice.Warnings)
+	}
+	return d.Flush()
+}
+
+// applyBidPrice patches spec.bidPrice of a spot node pool with the recommended bid
+func applyBidPrice(cmd *cobra.Command, apiClient *client.Client, poolName string, advice *pricing.Advice) error {
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	pool, err := apiClient.GetSpotNodePool(ctx, namespace, poolName)
+	if err != nil {
+		return fmt.Errorf("failed to get spot node pool '%s': %w", poolName, err)
+	}
+	if pool.Spec.ServerClass != advice.ServerClass {
+		return fmt.Errorf("spot node pool '%s' uses server class '%s', not '%s'", poolName, pool.Spec.ServerClass, advice.ServerClass)
+	}
+
+	patchOps := []client.PatchOperation{
+		{Op: "replace", Path: "/spec/bidPrice", Value: bidPriceValue(advice.BidPrice)},
+	}
+
+	// The patch is reported on stderr so -o json and -o yaml output stays parseable
+	errOut := cmd.ErrOrStderr()
+	fmt.Fprintln(errOut)
+	client.WritePatchOperations(errOut, patchOps)
+
+	skipConfirmation, _ := cmd.Flags().GetBool("confirm")
+	if !skipConfirmation {
+		confirmed, err := client.PromptForConfirmationTo(errOut, fmt.Sprintf("spot node pool '%s'", poolName))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(errOut, "Patch operation cancelled.")
+			return nil
+		}
+	}
+
+	if _, err := apiClient.EditSpotNodePool(ctx, namespace, poolName, patchOps); err != nil {
+		return fmt.Errorf("failed to edit spot node pool: %w", err)
+	}
+
+	fmt.Fprintf(errOut, "Spot node pool '%s' bid price set to %s (was %s)\n", poolName, bidPriceValue(advice.BidPrice), pool.Spec.BidPrice)
+	return nil
+}
+
+// bidPriceValue formats a bid for spec.bidPrice; bids are rounded to a tenth of a cent
+func bidPriceValue(bid pricing.Amount) string {
+	return bid.Format(3)
+}
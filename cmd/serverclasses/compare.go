@@ -0,0 +1,157 @@
+package serverclasses
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/completion"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/pricing"
+	"github.com/spf13/cobra"
+)
+
+// NewCompareCommand creates the serverclasses compare command
+func NewCompareCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare <name> <name> [name...]",
+		Short: "Compare server classes side by side",
+		Long: `Compare two or more server classes side by side.
+
+Resources, availability and prices are shown in one column per server class,
+including the spot price per vCPU and per GiB of memory. The lowest price in
+each price row is marked with an asterisk.
+
+Examples:
+  spotctl serverclasses compare gp.vs1.large-lon gp.vs1.xlarge-lon mh.vs1.large-lon
+  spotctl serverclasses compare gp.vs1.large-lon gp.vs1.xlarge-lon -o json`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completion.ServerClassFlagValues,
+		RunE:              runCompare,
+	}
+
+	// Add flags for compare command
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat != "table" && outputFormat != "json" && outputFormat != "yaml" {
+		return fmt.Errorf("unsupported output format: %s (use table, json or yaml)", outputFormat)
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+	serverClassList, err := client.ListServerClasses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list server classes: %w", err)
+	}
+
+	summaries, err := selectServerClasses(serverClassList.Items, args)
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "table" {
+		return writeComparison(cmd.OutOrStdout(), summaries)
+	}
+	formatter := output.NewFormatter(output.OutputOptions{Format: output.OutputFormat(outputFormat)})
+	return formatter.OutputToWriter(cmd.OutOrStdout(), summaries, nil)
+}
+
+// selectServerClasses returns summaries of the named server classes in the order given
+func selectServerClasses(serverClasses []client.ServerClass, names []string) ([]serverClassSummary, error) {
+	byName := make(map[string]client.ServerClass, len(serverClasses))
+	for _, serverClass := range serverClasses {
+		byName[serverClass.Metadata.Name] = serverClass
+	}
+
+	summaries := make([]serverClassSummary, 0, len(names))
+	var missing []string
+	for _, name := range names {
+		serverClass, ok := byName[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		summaries = append(summaries, summarizeServerClass(serverClass))
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("server class not found: %s", strings.Join(missing, ", "))
+	}
+	return summaries, nil
+}
+
+// writeComparison writes one row per attribute and one column per server class
+func writeComparison(w io.Writer, summaries []serverClassSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	row := func(label string, value func(serverClassSummary) string) {
+		cells := []string{label}
+		for _, summary := range summaries {
+			cell := value(summary)
+			if cell == "" {
+				cell = "N/A"
+			}
+			cells = append(cells, cell)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	priceRow := func(label string, price func(serverClassSummary) *pricing.Amount) {
+		var lowest *pricing.Amount
+		for _, summary := range summaries {
+			if p := price(summary); p != nil && (lowest == nil || *p < *lowest) {
+				lowest = p
+			}
+		}
+		row(label, func(summary serverClassSummary) string {
+			p := price(summary)
+			if p == nil {
+				return ""
+			}
+			if *p == *lowest {
+				return fmt.Sprintf("$%s *", p)
+			}
+			return fmt.Sprintf("$%s", p)
+		})
+	}
+
+	row("", func(s serverClassSummary) string { return s.Name })
+	row("Display Name", func(s serverClassSummary) string { return s.DisplayName })
+	row("Region", func(s serverClassSummary) string { return s.Region })
+	row("Category", func(s serverClassSummary) string { return s.Category })
+	row("Flavor Type", func(s serverClassSummary) string { return s.FlavorType })
+	row("CPU", func(s serverClassSummary) string { return s.CPU })
+	row("Memory", func(s serverClassSummary) string { return s.Memory })
+	row("Available", func(s serverClassSummary) string {
+		if s.Available == nil {
+			return ""
+		}
+		if s.Capacity == nil {
+			return fmt.Sprintf("%d", *s.Available)
+		}
+		return fmt.Sprintf("%d/%d", *s.Available, *s.Capacity)
+	})
+	priceRow("Spot Price/h", func(s serverClassSummary) *pricing.Amount { return s.SpotPrice })
+	priceRow("Hammer Price/h", func(s serverClassSummary) *pricing.Amount { return s.HammerPrice })
+	priceRow("On-Demand Price/h", func(s serverClassSummary) *pricing.Amount { return s.OnDemandPrice })
+	priceRow("Spot per vCPU/h", func(s serverClassSummary) *pricing.Amount { return s.PricePerCPU })
+	priceRow("Spot per GiB/h", func(s serverClassSummary) *pricing.Amount { return s.PricePerGiB })
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\n* lowest price")
+	return nil
+}
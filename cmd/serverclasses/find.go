@@ -0,0 +1,319 @@
+package serverclasses
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/pager"
+	"github.com/georgetaylor/spotctl/pkg/pricing"
+	"github.com/georgetaylor/spotctl/pkg/quantity"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Sort orders supported by the find command
+const (
+	sortByPricePerCPU = "price-per-cpu"
+	sortByPricePerGiB = "price-per-gib"
+	sortByPrice       = "price"
+	sortByCPU         = "cpu"
+	sortByMemory      = "memory"
+)
+
+// NewFindCommand creates the serverclasses find command
+func NewFindCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find and rank server classes matching requirements",
+		Long: `Find server classes that match CPU, memory, region, category, price and
+availability requirements, ranked by value.
+
+CPU and memory are parsed as quantities, so --min-cpu accepts values such as
+"8" or "500m" and --min-memory accepts values such as "32Gi", "16G" or "512Mi".
+As in Kubernetes, Gi and Mi are binary units and G and M decimal ones. Server
+class memory is reported in GB, which is read as decimal: "3.75GB" is
+3,750,000,000 bytes, shown as 3.5Gi, so it does not match --min-memory 4G.
+
+Prices are the current spot market price per hour; the price per vCPU and per
+GiB of memory is computed for every server class to make them comparable.
+
+Results are ranked by price per vCPU by default. Server classes without a spot
+market price are listed last.
+
+Examples:
+  # Find compute server classes with at least 8 vCPUs and 32Gi of memory
+  spotctl serverclasses find --min-cpu 8 --min-memory 32Gi --category compute
+
+  # Find available server classes in a region under $0.20/hour
+  spotctl serverclasses find --region uk-lon-1 --max-price 0.20 --available
+
+  # Rank by price per GiB of memory and export as CSV
+  spotctl serverclasses find --sort-by price-per-gib -o csv`,
+		Args: cobra.NoArgs,
+		RunE: runFind,
+	}
+
+	// Add flags for find command
+	cmd.Flags().String("min-cpu", "", "Minimum number of vCPUs, e.g. 8")
+	cmd.Flags().String("min-memory", "", "Minimum memory, e.g. 32Gi")
+	cmd.Flags().String("region", "", "Only show server classes in this region")
+	cmd.Flags().String("category", "", "Only show server classes whose category contains this value")
+	cmd.Flags().String("max-price", "", "Maximum spot market price per hour, e.g. 0.20")
+	cmd.Flags().Bool("available", false, "Only show server classes with servers available")
+	cmd.Flags().String("sort-by", sortByPricePerCPU, "Sort by price-per-cpu, price-per-gib, price, cpu or memory")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, wide, json, yaml, csv)")
+
+	return cmd
+}
+
+// serverClassSummary is a server class with parsed resources and comparable prices
+type serverClassSummary struct {
+	Name          string          `json:"name" yaml:"name"`
+	DisplayName   string          `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Region        string          `json:"region,omitempty" yaml:"region,omitempty"`
+	Category      string          `json:"category,omitempty" yaml:"category,omitempty"`
+	FlavorType    string          `json:"flavorType,omitempty" yaml:"flavorType,omitempty"`
+	CPU           string          `json:"cpu" yaml:"cpu"`
+	Memory        string          `json:"memory" yaml:"memory"`
+	Millicores    int64           `json:"millicores" yaml:"millicores"`
+	MemoryBytes   int64           `json:"memoryBytes" yaml:"memoryBytes"`
+	Available     *int            `json:"available,omitempty" yaml:"available,omitempty"`
+	Capacity      *int            `json:"capacity,omitempty" yaml:"capacity,omitempty"`
+	SpotPrice     *pricing.Amount `json:"spotPrice,omitempty" yaml:"spotPrice,omitempty"`
+	HammerPrice   *pricing.Amount `json:"hammerPrice,omitempty" yaml:"hammerPrice,omitempty"`
+	OnDemandPrice *pricing.Amount `json:"onDemandPrice,omitempty" yaml:"onDemandPrice,omitempty"`
+	PricePerCPU   *pricing.Amount `json:"pricePerCpu,omitempty" yaml:"pricePerCpu,omitempty"`
+	PricePerGiB   *pricing.Amount `json:"pricePerGiB,omitempty" yaml:"pricePerGiB,omitempty"`
+}
+
+// serverClassFilter holds the find requirements; zero values match everything
+type serverClassFilter struct {
+	MinMillicores  int64
+	MinMemoryBytes int64
+	Region         string
+	Category       string
+	MaxPrice       *pricing.Amount
+	Available      bool
+}
+
+func runFind(cmd *cobra.Command, args []string) error {
+	filter, err := filterFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	sortBy, _ := cmd.Flags().GetString("sort-by")
+	switch sortBy {
+	case sortByPricePerCPU, sortByPricePerGiB, sortByPrice, sortByCPU, sortByMemory:
+	default:
+		return fmt.Errorf("invalid --sort-by value: %s (use price-per-cpu, price-per-gib, price, cpu or memory)", sortBy)
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+	serverClassList, err := client.ListServerClasses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list server classes: %w", err)
+	}
+
+	summaries := findServerClasses(serverClassList.Items, filter)
+	sortServerClassSummaries(summaries, sortBy)
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	return outputServerClassSummaries(summaries, outputFormat)
+}
+
+// filterFromFlags parses the find requirement flags
+func filterFromFlags(cmd *cobra.Command) (*serverClassFilter, error) {
+	filter := &serverClassFilter{}
+
+	if minCPU, _ := cmd.Flags().GetString("min-cpu"); minCPU != "" {
+		millicores, err := quantity.ParseCPU(minCPU)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --min-cpu: %w", err)
+		}
+		filter.MinMillicores = millicores
+	}
+	if minMemory, _ := cmd.Flags().GetString("min-memory"); minMemory != "" {
+		bytes, err := quantity.ParseMemory(minMemory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --min-memory: %w", err)
+		}
+		filter.MinMemoryBytes = bytes
+	}
+	if maxPrice, _ := cmd.Flags().GetString("max-price"); maxPrice != "" {
+		price, err := pricing.Parse(maxPrice)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --max-price: %w", err)
+		}
+		filter.MaxPrice = &price
+	}
+	filter.Region, _ = cmd.Flags().GetString("region")
+	filter.Category, _ = cmd.Flags().GetString("category")
+	filter.Available, _ = cmd.Flags().GetBool("available")
+
+	return filter, nil
+}
+
+// summarizeServerClass parses the resources and prices of a server class.
+// Values that cannot be parsed are left empty rather than failing the whole listing.
+func summarizeServerClass(serverClass client.ServerClass) serverClassSummary {
+	summary := serverClassSummary{
+		Name:        serverClass.Metadata.Name,
+		DisplayName: serverClass.Spec.DisplayName,
+		Region:      serverClass.Spec.Region,
+		Category:    serverClass.Spec.Category,
+		FlavorType:  serverClass.Spec.FlavorType,
+		CPU:         serverClass.Spec.Resources.CPU,
+		Memory:      serverClass.Spec.Resources.Memory,
+		Available:   serverClass.Status.Available,
+		Capacity:    serverClass.Status.Capacity,
+	}
+
+	if millicores, err := quantity.ParseCPU(serverClass.Spec.Resources.CPU); err == nil {
+		summary.Millicores = millicores
+		summary.CPU = quantity.FormatCPU(millicores)
+	}
+	if bytes, err := quantity.ParseMemory(serverClass.Spec.Resources.Memory); err == nil {
+		summary.MemoryBytes = bytes
+		summary.Memory = quantity.FormatMemory(bytes)
+	}
+
+	if price, err := pricing.SpotHourly(serverClass); err == nil {
+		summary.SpotPrice = &price
+		if summary.Millicores > 0 {
+			perCPU := price.Mul(1000).Div(summary.Millicores)
+			summary.PricePerCPU = &perCPU
+		}
+		if summary.MemoryBytes > 0 {
+			perGiB := price.Mul(int(quantity.GiB)).Div(summary.MemoryBytes)
+			summary.PricePerGiB = &perGiB
+		}
+	}
+	if price, err := pricing.Parse(serverClass.Status.SpotPricing.HammerPricePerHour); err == nil {
+		summary.HammerPrice = &price
+	}
+	if price, err := pricing.OnDemandHourly(serverClass); err == nil {
+		summary.OnDemandPrice = &price
+	}
+
+	return summary
+}
+
+// findServerClasses returns summaries of the server classes matching the filter
+func findServerClasses(serverClasses []client.ServerClass, filter *serverClassFilter) []serverClassSummary {
+	summaries := []serverClassSummary{}
+	for _, serverClass := range serverClasses {
+		summary := summarizeServerClass(serverClass)
+		if filter.matches(summary) {
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
+
+// matches reports whether a server class meets every requirement of the filter
+func (f *serverClassFilter) matches(summary serverClassSummary) bool {
+	if f.MinMillicores > 0 && summary.Millicores < f.MinMillicores {
+		return false
+	}
+	if f.MinMemoryBytes > 0 && summary.MemoryBytes < f.MinMemoryBytes {
+		return false
+	}
+	if f.Region != "" && !strings.EqualFold(summary.Region, f.Region) {
+		return false
+	}
+	if f.Category != "" && !strings.Contains(strings.ToLower(summary.Category), strings.ToLower(f.Category)) {
+		return false
+	}
+	if f.MaxPrice != nil && (summary.SpotPrice == nil || *summary.SpotPrice > *f.MaxPrice) {
+		return false
+	}
+	if f.Available && (summary.Available == nil || *summary.Available <= 0) {
+		return false
+	}
+	return true
+}
+
+// sortServerClassSummaries ranks summaries; prices sort cheapest first, resources largest first
+func sortServerClassSummaries(summaries []serverClassSummary, sortBy string) {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		switch sortBy {
+		case sortByCPU:
+			if a.Millicores != b.Millicores {
+				return a.Millicores > b.Millicores
+			}
+		case sortByMemory:
+			if a.MemoryBytes != b.MemoryBytes {
+				return a.MemoryBytes > b.MemoryBytes
+			}
+		default:
+			priceA, priceB := rankingPrice(a, sortBy), rankingPrice(b, sortBy)
+			if (priceA == nil) != (priceB == nil) {
+				return priceA != nil
+			}
+			if priceA != nil && *priceA != *priceB {
+				return *priceA < *priceB
+			}
+		}
+		return a.Name < b.Name
+	})
+}
+
+// rankingPrice returns the price a summary is ranked by
+func rankingPrice(summary serverClassSummary, sortBy string) *pricing.Amount {
+	switch sortBy {
+	case sortByPricePerGiB:
+		return summary.PricePerGiB
+	case sortByPrice:
+		return summary.SpotPrice
+	default:
+		return summary.PricePerCPU
+	}
+}
+
+// getServerClassSummaryTableConfig returns the table configuration for ranked server classes
+func getServerClassSummaryTableConfig() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "NAME", Field: "name"},
+			{Header: "REGION", Field: "region"},
+			{Header: "CPU", Field: "cpu", Default: "<unknown>"},
+			{Header: "MEMORY", Field: "memory", Default: "<unknown>"},
+			{Header: "AVAILABLE", Field: "available", Default: "N/A"},
+			{Header: "SPOT PRICE", Field: "spotPrice", Default: "N/A"},
+			{Header: "PER VCPU", Field: "pricePerCpu", Default: "N/A"},
+			{Header: "PER GIB", Field: "pricePerGiB", Default: "N/A"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "CATEGORY", Field: "category"},
+			{Header: "CAPACITY", Field: "capacity", Default: "N/A"},
+			{Header: "HAMMER PRICE", Field: "hammerPrice", Default: "N/A"},
+			{Header: "ON-DEMAND PRICE", Field: "onDemandPrice", Default: "N/A"},
+		},
+	}
+}
+
+// outputServerClassSummaries handles formatting and output of ranked server classes
+func outputServerClassSummaries(summaries []serverClassSummary, format string) error {
+	if len(summaries) == 0 && (format == "table" || format == "wide") {
+		fmt.Println("No server classes match the given requirements")
+		return nil
+	}
+
+	p := pager.NewPager()
+	p.Disable = viper.GetBool("no-pager")
+	formatter := output.NewFormatterWithPager(output.OutputOptions{Format: output.OutputFormat(format)}, p)
+	return formatter.Output(summaries, getServerClassSummaryTableConfig())
+}
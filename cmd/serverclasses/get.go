@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -21,13 +24,15 @@ by its name, including specifications, pricing, and current availability.
 
 Examples:
   spotctl serverclasses get standard-2
-  spotctl serverclasses get compute-optimized-4 -o json`,
-		Args: cobra.ExactArgs(1),
-		RunE: runGet,
+  spotctl serverclasses get compute-optimized-4 -o json
+  spotctl serverclasses get standard-2 compute-optimized-4`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completion.Each(completion.ServerClassNames),
+		RunE:              runGet,
 	}
 
 	// Add flags for get command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
 
 	return cmd
 }
@@ -38,17 +43,31 @@ func runGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	client := client.NewClient(cfg)
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+
+	// Read flags directly from command
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	// Several server classes are shown as a list, after reporting any that could not be found
+	if len(args) > 1 {
+		items, getErr := cmdutil.EachName(ctx, args, cmd.ErrOrStderr(), "get", "server class", func(ctx context.Context, name string) (*client.ServerClass, error) {
+			return apiClient.GetServerClass(ctx, name)
+		})
+		if len(items) > 0 {
+			if err := registry.OutputList(registry.ServerClasses, &client.ServerClassList{Items: items}, outputFormat, ""); err != nil {
+				return err
+			}
+		}
+		return getErr
+	}
 
-	ctx := context.Background()
 	name := args[0]
-	serverClass, err := client.GetServerClass(ctx, name)
+	serverClass, err := apiClient.GetServerClass(ctx, name)
 	if err != nil {
 		return fmt.Errorf("failed to get server class '%s': %w", name, err)
 	}
 
-	// Read flags directly from command
-	outputFormat, _ := cmd.Flags().GetString("output")
-
-	return outputServerClass(serverClass, outputFormat)
+	return registry.Output(registry.ServerClasses, serverClass, outputFormat)
 }
@@ -1,11 +1,12 @@
 package serverclasses
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -22,27 +23,34 @@ all regions, including their specifications, pricing, and current availability.`
 	}
 
 	// Add flags for list command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
+	cmdutil.AddListFlags(cmd)
 
 	return cmd
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	listOpts, limit, err := cmdutil.ListFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := config.GetConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	client := client.NewClient(cfg)
+	apiClient := client.NewClient(cfg)
 
-	ctx := context.Background()
-	serverClassList, err := client.ListServerClasses(ctx)
+	ctx := cmd.Context()
+	items, err := client.Collect(apiClient.AllServerClasses(ctx, listOpts), limit)
 	if err != nil {
 		return fmt.Errorf("failed to list server classes: %w", err)
 	}
+	serverClassList := &client.ServerClassList{Items: items}
 
 	// Read flags directly from command
 	outputFormat, _ := cmd.Flags().GetString("output")
 
-	return outputServerClasses(serverClassList, outputFormat)
+	return registry.OutputList(registry.ServerClasses, serverClassList, outputFormat, "")
 }
@@ -1,6 +1,8 @@
 package serverclasses
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/spf13/cobra"
+)
 
 // NewCommand returns the main serverclasses command with all subcommands
 func NewCommand() *cobra.Command {
@@ -16,6 +18,9 @@ server classes in Rackspace Spot regions.`,
 	// Add all subcommands
 	cmd.AddCommand(NewListCommand())
 	cmd.AddCommand(NewGetCommand())
+	cmd.AddCommand(NewFindCommand())
+	cmd.AddCommand(NewCompareCommand())
+	cmd.AddCommand(NewAdviseCommand())
 
 	return cmd
 }
@@ -2,10 +2,19 @@ package serverclasses
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/pricing"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
 	"github.com/spf13/cobra"
 )
 
@@ -251,3 +260,307 @@ func TestServerClassesGetCommand(t *testing.T) {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestWriteAdvice(t *testing.T) {
+	probability := 0.8
+	advice := &pricing.Advice{
+		ServerClass:    "gp.vs1.large-lon",
+		Nodes:          3,
+		Risk:           pricing.RiskMedium,
+		BidPrice:       pricing.MustParse("0.08"),
+		WinProbability: &probability,
+		MaxHourlyCost:  pricing.MustParse("0.24"),
+		Reasons:        []string{"80% of the last 10 auctions cleared at or below $0.0800/hour"},
+		Warnings:       []string{"only 2 servers are available"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeAdvice(&buf, advice); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"Recommended Bid:",
+		"$0.0800/hour per node",
+		"Win Probability:",
+		"80%",
+		"$0.24/hour, $175.20/month",
+		"Reasoning:",
+		"auctions cleared",
+		"Warnings:",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestFetchAdviceInput(t *testing.T) {
+	server := spottest.NewTestServer(t)
+	apiClient := client.NewClient(server.Config())
+
+	input, err := fetchAdviceInput(context.Background(), apiClient, "gp.vs1.large-dfw")
+	if err != nil {
+		t.Fatalf("fetchAdviceInput failed: %v", err)
+	}
+	if len(input.History) == 0 {
+		t.Error("Expected a price history")
+	}
+
+	// A server class without price history is advised from the market price
+	server.InjectFault(spottest.Fault{Path: "/ngpc.rxt.io/v1/price-history", Status: http.StatusNotFound})
+	input, err = fetchAdviceInput(context.Background(), apiClient, "gp.vs1.large-dfw")
+	if err != nil {
+		t.Fatalf("Expected missing history to be optional, got %v", err)
+	}
+	if len(input.History) != 0 {
+		t.Errorf("Expected no price history, got %d points", len(input.History))
+	}
+	if len(input.Unavailable) != 1 || input.Unavailable[0] != "price history" {
+		t.Errorf("Expected the missing history to be noted, got %v", input.Unavailable)
+	}
+
+	// Any other failure fails the advice rather than weakening it
+	server.ClearFaults()
+	server.InjectFault(spottest.Fault{Path: "/ngpc.rxt.io/v1/percentile-info", Status: http.StatusForbidden})
+	if _, err := fetchAdviceInput(context.Background(), apiClient, "gp.vs1.large-dfw"); err == nil || !strings.Contains(err.Error(), "failed to get price percentiles") {
+		t.Errorf("Expected the percentile failure to be returned, got %v", err)
+	}
+}
+
+func TestApplyBidPrice(t *testing.T) {
+	var patched []client.PatchOperation
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oauth/token":
+			w.Write([]byte(`{"id_token":"stand-in-id-token","expires_in":86400,"token_type":"Bearer"}`))
+		case r.URL.Path == "/ngpc.rxt.io/v1/namespaces/org-abc123/spotnodepools/my-pool" && r.Method == http.MethodGet:
+			w.Write([]byte(`{"metadata":{"name":"my-pool"},"spec":{"serverClass":"gp.vs1.large-lon","bidPrice":"0.05"}}`))
+		case r.URL.Path == "/ngpc.rxt.io/v1/namespaces/org-abc123/spotnodepools/my-pool" && r.Method == http.MethodPatch:
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &patched)
+			w.Write([]byte(`{"metadata":{"name":"my-pool"},"spec":{"serverClass":"gp.vs1.large-lon","bidPrice":"0.080"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	apiClient := client.NewClient(&config.Config{
+		RefreshToken: "test-refresh-token",
+		BaseURL:      server.URL,
+		OAuthURL:     server.URL + "/oauth/token",
+		Timeout:      5,
+	})
+
+	tests := []struct {
+		name        string
+		serverClass string
+		expectError bool
+	}{
+		{name: "matching server class", serverClass: "gp.vs1.large-lon"},
+		{name: "different server class", serverClass: "mh.vs1.xlarge-lon", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched = nil
+			cmd := NewAdviseCommand()
+			cmd.SetContext(context.Background())
+			var stdout, stderr bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			cmd.Flags().Set("namespace", "org-abc123")
+			cmd.Flags().Set("confirm", "true")
+
+			advice := &pricing.Advice{ServerClass: tt.serverClass, BidPrice: pricing.MustParse("0.08")}
+			err := applyBidPrice(cmd, apiClient, "my-pool", advice)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				if patched != nil {
+					t.Error("Expected no patch to be sent")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(patched) != 1 || patched[0].Op != "replace" || patched[0].Path != "/spec/bidPrice" || patched[0].Value != "0.080" {
+				t.Errorf("Unexpected patch operations: %+v", patched)
+			}
+			// Stdout carries the advice, which may be -o json or -o yaml
+			if stdout.Len() != 0 {
+				t.Errorf("Expected nothing on stdout, got %q", stdout.String())
+			}
+			if !strings.Contains(stderr.String(), "replace /spec/bidPrice") || !strings.Contains(stderr.String(), "bid price set to 0.080") {
+				t.Errorf("Expected the patch and result on stderr, got %q", stderr.String())
+			}
+		})
+	}
+}
+
+func testServerClasses() []client.ServerClass {
+	available, none, capacity := 10, 0, 20
+	newClass := func(name, region, category, cpu, memory, spotPrice string, available *int) client.ServerClass {
+		return client.ServerClass{
+			Metadata: client.ObjectMeta{Name: name},
+			Spec: client.ServerClassSpec{
+				Region:          region,
+				Category:        category,
+				Resources:       client.ServerClassResources{CPU: cpu, Memory: memory},
+				OnDemandPricing: client.ServerClassPricing{Cost: "0.50", Interval: "hour"},
+			},
+			Status: client.ServerClassStatus{
+				Available:   available,
+				Capacity:    &capacity,
+				SpotPricing: client.ServerClassSpotPricing{MarketPricePerHour: spotPrice},
+			},
+		}
+	}
+
+	return []client.ServerClass{
+		newClass("gp.large", "uk-lon-1", "General Purpose", "4", "15GB", "0.04", &available),
+		newClass("ch.xlarge", "uk-lon-1", "Compute Heavy", "16", "30GB", "0.08", &available),
+		newClass("mh.xlarge", "uk-lon-1", "Memory Heavy", "8", "64GB", "0.12", &none),
+		newClass("ch.large-dfw", "us-central-dfw-1", "Compute Heavy", "8", "15GB", "0.16", &available),
+		newClass("gp.unpriced", "uk-lon-1", "General Purpose", "8", "32GB", "", &available),
+	}
+}
+
+func TestSummarizeServerClass(t *testing.T) {
+	summary := summarizeServerClass(testServerClasses()[1])
+
+	if summary.Millicores != 16000 || summary.CPU != "16" || summary.Memory != "27.9Gi" {
+		t.Errorf("Unexpected resources: %+v", summary)
+	}
+	if summary.PricePerCPU == nil || *summary.PricePerCPU != pricing.MustParse("0.005") {
+		t.Errorf("Expected price per vCPU 0.0050, got %v", summary.PricePerCPU)
+	}
+	// 0.08 / 30GB, which is 27.9 GiB
+	if summary.PricePerGiB == nil || *summary.PricePerGiB != pricing.MustParse("0.002863") {
+		t.Errorf("Expected price per GiB 0.002863, got %v", summary.PricePerGiB)
+	}
+	if summary.OnDemandPrice == nil || *summary.OnDemandPrice != pricing.MustParse("0.5") {
+		t.Errorf("Expected on-demand price 0.5, got %v", summary.OnDemandPrice)
+	}
+
+	unpriced := summarizeServerClass(testServerClasses()[4])
+	if unpriced.SpotPrice != nil || unpriced.PricePerCPU != nil {
+		t.Errorf("Expected no spot prices for unpriced class, got %+v", unpriced)
+	}
+}
+
+func TestFindServerClasses(t *testing.T) {
+	maxPrice := pricing.MustParse("0.10")
+
+	tests := []struct {
+		name     string
+		filter   serverClassFilter
+		sortBy   string
+		expected []string
+	}{
+		{
+			name:     "no filter ranks by price per vCPU with unpriced last",
+			sortBy:   sortByPricePerCPU,
+			expected: []string{"ch.xlarge", "gp.large", "mh.xlarge", "ch.large-dfw", "gp.unpriced"},
+		},
+		{
+			name:     "min cpu and memory",
+			filter:   serverClassFilter{MinMillicores: 8000, MinMemoryBytes: 32_000_000_000},
+			sortBy:   sortByPricePerCPU,
+			expected: []string{"mh.xlarge", "gp.unpriced"},
+		},
+		{
+			name:     "region, category, price and availability",
+			filter:   serverClassFilter{Region: "UK-LON-1", Category: "compute", MaxPrice: &maxPrice, Available: true},
+			sortBy:   sortByPricePerCPU,
+			expected: []string{"ch.xlarge"},
+		},
+		{
+			name:     "available only",
+			filter:   serverClassFilter{Available: true, MaxPrice: &maxPrice},
+			sortBy:   sortByPrice,
+			expected: []string{"gp.large", "ch.xlarge"},
+		},
+		{
+			name:     "sort by memory",
+			filter:   serverClassFilter{Region: "uk-lon-1"},
+			sortBy:   sortByMemory,
+			expected: []string{"mh.xlarge", "gp.unpriced", "ch.xlarge", "gp.large"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summaries := findServerClasses(testServerClasses(), &tt.filter)
+			sortServerClassSummaries(summaries, tt.sortBy)
+
+			var names []string
+			for _, summary := range summaries {
+				names = append(names, summary.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestFindFilterFromFlags(t *testing.T) {
+	cmd := NewFindCommand()
+	cmd.Flags().Set("min-cpu", "8")
+	cmd.Flags().Set("min-memory", "32Gi")
+	cmd.Flags().Set("max-price", "0.20")
+
+	filter, err := filterFromFlags(cmd)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if filter.MinMillicores != 8000 || filter.MinMemoryBytes != 32<<30 || *filter.MaxPrice != pricing.MustParse("0.2") {
+		t.Errorf("Unexpected filter: %+v", filter)
+	}
+
+	cmd = NewFindCommand()
+	cmd.Flags().Set("min-memory", "lots")
+	if _, err := filterFromFlags(cmd); err == nil {
+		t.Error("Expected error for invalid --min-memory")
+	}
+}
+
+func TestCompareServerClasses(t *testing.T) {
+	if _, err := selectServerClasses(testServerClasses(), []string{"gp.large", "missing"}); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Expected error naming the missing server class, got %v", err)
+	}
+
+	summaries, err := selectServerClasses(testServerClasses(), []string{"mh.xlarge", "gp.large"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summaries[0].Name != "mh.xlarge" {
+		t.Errorf("Expected summaries in the order given, got %s first", summaries[0].Name)
+	}
+
+	var buf bytes.Buffer
+	if err := writeComparison(&buf, summaries); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"mh.xlarge",
+		"Memory Heavy",
+		"59.6Gi",
+		"0/20",
+		"$0.0400 *",
+		"$0.0100 *",
+		"* lowest price",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
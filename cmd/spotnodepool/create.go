@@ -1,13 +1,14 @@
 package spotnodepool
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -29,14 +30,17 @@ Examples:
   spotctl spotnodepool create my-nodepool --namespace org-abc123 --server-class gp.vs1.large-lon --cloudspace my-cloudspace --desired 3 --bid-price 0.50
 
   # Create a spot node pool from a spec file
-  spotctl spotnodepool create my-nodepool --namespace org-abc123 --file spec.json`,
+  spotctl spotnodepool create my-nodepool --namespace org-abc123 --file spec.json
+
+  # Validate a spot node pool spec with the API without creating it
+  spotctl spotnodepool create my-nodepool --namespace org-abc123 --file spec.json --dry-run=server`,
 		Args: cobra.ExactArgs(1),
 		RunE: runCreate,
 	}
 
 	// Add flags for spotnodepool create command
 	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml)")
-	cmd.Flags().StringP("namespace", "n", "", "Namespace to create the spot node pool in (required)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace to create the spot node pool in (overrides config)")
 	cmd.Flags().StringP("file", "f", "", "Path to JSON file containing spot node pool spec")
 	cmd.Flags().String("server-class", "", "Server class for the spot node pool (required unless using --file)")
 	cmd.Flags().String("cloudspace", "", "Cloud space for the spot node pool (required unless using --file)")
@@ -45,6 +49,7 @@ Examples:
 	cmd.Flags().Int("autoscaling-min-nodes", 0, "Minimum number of nodes for autoscaling")
 	cmd.Flags().Int("autoscaling-max-nodes", 0, "Maximum number of nodes for autoscaling")
 	cmd.Flags().String("bid-price", "", "Bid price for spot instances")
+	cmdutil.AddDryRunFlag(cmd)
 
 	return cmd
 }
@@ -53,7 +58,6 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	spotNodePoolName := args[0] // Get name from positional argument
 
 	// Get flag values
-	namespace, _ := cmd.Flags().GetString("namespace")
 	file, _ := cmd.Flags().GetString("file")
 	serverClass, _ := cmd.Flags().GetString("server-class")
 	cloudSpace, _ := cmd.Flags().GetString("cloudspace")
@@ -68,8 +72,14 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	if spotNodePoolName == "" {
 		return fmt.Errorf("spot node pool name is required (use positional argument)")
 	}
-	if namespace == "" {
-		return fmt.Errorf("namespace is required (use --namespace flag)")
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	dryRun, err := cmdutil.DryRun(cmd)
+	if err != nil {
+		return err
 	}
 
 	cfg, err := config.GetConfig()
@@ -78,6 +88,9 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	apiClient := client.NewClient(cfg)
+	if dryRun {
+		apiClient = apiClient.WithDryRun()
+	}
 
 	var spotNodePool *client.SpotNodePool
 
@@ -142,14 +155,18 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	ctx := context.Background()
+	ctx := cmd.Context()
 	createdSpotNodePool, err := apiClient.CreateSpotNodePool(ctx, namespace, spotNodePool)
 	if err != nil {
 		return fmt.Errorf("failed to create spot node pool: %w", err)
 	}
 
+	if dryRun {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Server dry run: spot node pool '%s' was validated but not created\n", spotNodePoolName)
+	}
+
 	// Output the created spot node pool
-	return outputCreatedSpotNodePool(createdSpotNodePool, outputFormat)
+	return registry.Output(registry.SpotNodePools, createdSpotNodePool, outputFormat)
 }
 
 // loadSpecFromFile loads a SpotNodePoolSpec from a JSON file
@@ -1,11 +1,12 @@
 package spotnodepool
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -21,8 +22,10 @@ ask for confirmation before proceeding with the deletion.
 
 The namespace can be specified via:
 - The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
 - The 'namespace' field in your config file
 - The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
 
 Examples:
   # Delete all spot node pools using namespace from config
@@ -32,7 +35,10 @@ Examples:
   spotctl spotnodepool delete-all --namespace org-abc123
 
   # Delete with confirmation
-  spotctl spotnodepool delete-all --confirm`,
+  spotctl spotnodepool delete-all --confirm
+
+  # Check that the spot node pools can be deleted without deleting them
+  spotctl spotnodepool delete-all --dry-run=server`,
 		Args: cobra.NoArgs,
 		RunE: runDeleteAll,
 	}
@@ -40,12 +46,18 @@ Examples:
 	// Add flags for spotnodepool delete-all command
 	cmd.Flags().StringP("namespace", "n", "", "Namespace to delete spot node pools from (overrides config)")
 	cmd.Flags().Bool("confirm", false, "Skip confirmation prompt")
+	cmdutil.AddDryRunFlag(cmd)
 
 	return cmd
 }
 
 func runDeleteAll(cmd *cobra.Command, args []string) error {
-	namespace, err := getNamespace(cmd)
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	dryRun, err := cmdutil.DryRun(cmd)
 	if err != nil {
 		return err
 	}
@@ -58,7 +70,10 @@ func runDeleteAll(cmd *cobra.Command, args []string) error {
 	}
 
 	client := client.NewClient(cfg)
-	ctx := context.Background()
+	if dryRun {
+		client = client.WithDryRun()
+	}
+	ctx := cmd.Context()
 
 	// First, list all spot node pools to show what will be deleted
 	fmt.Printf("Listing spot node pools in namespace '%s':\n\n", namespace)
@@ -79,20 +94,18 @@ func runDeleteAll(cmd *cobra.Command, args []string) error {
 		outputFormat = "table"
 	}
 
-	err = outputSpotNodePools(spotNodePoolList, outputFormat, namespace)
+	err = registry.OutputList(registry.SpotNodePools, spotNodePoolList, outputFormat, namespace)
 	if err != nil {
 		return fmt.Errorf("failed to output spot node pools: %w", err)
 	}
 
 	fmt.Printf("\nFound %d spot node pool(s) to delete.\n", len(spotNodePoolList.Items))
 
-	// Ask for confirmation unless --confirm flag is used
-	if !confirm {
-		fmt.Printf("\nAre you sure you want to delete ALL %d spot node pool(s) in namespace '%s'? (y/N): ", len(spotNodePoolList.Items), namespace)
-		var response string
-		fmt.Scanln(&response)
-		if response != "y" && response != "Y" && response != "yes" && response != "Yes" {
-			fmt.Println("Delete cancelled")
+	// Ask for confirmation unless --confirm flag is used; a dry run deletes nothing
+	if !confirm && !dryRun {
+		fmt.Fprintln(cmd.ErrOrStderr())
+		if !cmdutil.Confirm(cmd, fmt.Sprintf("Are you sure you want to delete ALL %d spot node pool(s) in namespace '%s'?", len(spotNodePoolList.Items), namespace)) {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Delete cancelled")
 			return nil
 		}
 	}
@@ -105,9 +118,9 @@ func runDeleteAll(cmd *cobra.Command, args []string) error {
 
 	// Check if the deletion was successful
 	if deleteResponse.Status == "Success" || deleteResponse.Status == "" {
-		fmt.Printf("Successfully deleted all %d spot node pool(s) from namespace '%s'\n", len(spotNodePoolList.Items), namespace)
+		fmt.Printf("Successfully deleted all %d spot node pool(s) from namespace '%s'%s\n", len(spotNodePoolList.Items), namespace, cmdutil.DryRunSuffix(dryRun))
 	} else {
-		fmt.Printf("Delete operation completed with status: %s\n", deleteResponse.Status)
+		fmt.Printf("Delete operation completed with status: %s%s\n", deleteResponse.Status, cmdutil.DryRunSuffix(dryRun))
 		if deleteResponse.Message != "" {
 			fmt.Printf("Message: %s\n", deleteResponse.Message)
 		}
@@ -1,25 +1,35 @@
 package spotnodepool
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
 // NewDeleteCommand returns the spotnodepool delete command
 func NewDeleteCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "delete [NAME]",
-		Short: "Delete a spot node pool",
-		Long: `Delete a spot node pool by name in the specified namespace.
+		Use:   "delete (NAME... | -f FILE)",
+		Short: "Delete spot node pools",
+		Long: `Delete one or more spot node pools by name in the specified namespace. Several
+spot node pools are deleted concurrently, followed by a summary; the command fails if
+any of them could not be deleted.
+
+The spot node pools can also be named with --filename/-f by a manifest or a list of
+references one per line, as printed by -o name; use -f - to read them from stdin,
+together with --confirm.
 
 The namespace can be specified via:
 - The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
 - The 'namespace' field in your config file
 - The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
 
 Examples:
   # Delete a spot node pool using namespace from config
@@ -29,35 +39,46 @@ Examples:
   spotctl spotnodepool delete my-spot-pool --namespace org-abc123
 
   # Delete with confirmation
-  spotctl spotnodepool delete my-spot-pool --confirm`,
-		Args: cobra.ExactArgs(1),
-		RunE: runDelete,
+  spotctl spotnodepool delete my-spot-pool --confirm
+
+  # Check that the spot node pool can be deleted without deleting it
+  spotctl spotnodepool delete my-spot-pool --dry-run=server
+
+  # Delete several spot node pools
+  spotctl spotnodepool delete pool-a pool-b --confirm`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completion.Each(completion.SpotNodePoolNames),
+		RunE:              runDelete,
 	}
 
 	// Add flags for spotnodepool delete command
-	cmd.Flags().StringP("namespace", "n", "", "Namespace of the spot node pool (overrides config)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of the spot node pools (overrides config)")
 	cmd.Flags().Bool("confirm", false, "Skip confirmation prompt")
+	cmdutil.AddFilenameFlag(cmd)
+	cmdutil.AddDryRunFlag(cmd)
 
 	return cmd
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
-	spotNodePoolName := args[0] // Get name from positional argument
-
-	namespace, err := getNamespace(cmd)
+	dryRun, err := cmdutil.DryRun(cmd)
 	if err != nil {
 		return err
 	}
 
 	confirm, _ := cmd.Flags().GetBool("confirm")
+	if err := cmdutil.RequireConfirmForStdin(cmd, confirm, dryRun); err != nil {
+		return err
+	}
+	targets, err := cmdutil.KindTargets(cmd, registry.SpotNodePools, args)
+	if err != nil {
+		return err
+	}
 
-	// Ask for confirmation unless --confirm flag is used
-	if !confirm {
-		fmt.Printf("Are you sure you want to delete spot node pool '%s' in namespace '%s'? (y/N): ", spotNodePoolName, namespace)
-		var response string
-		fmt.Scanln(&response)
-		if response != "y" && response != "Y" && response != "yes" && response != "Yes" {
-			fmt.Println("Delete cancelled")
+	// Ask for confirmation unless --confirm flag is used; a dry run deletes nothing
+	if !confirm && !dryRun {
+		if !cmdutil.Confirm(cmd, cmdutil.ConfirmDeleteMessage(targets)) {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Delete cancelled")
 			return nil
 		}
 	}
@@ -68,21 +89,10 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	apiClient := client.NewClient(cfg)
-
-	ctx := context.Background()
-	deleteResponse, err := apiClient.DeleteSpotNodePool(ctx, namespace, spotNodePoolName)
-	if err != nil {
-		return fmt.Errorf("failed to delete spot node pool: %w", err)
+	if dryRun {
+		apiClient = apiClient.WithDryRun()
 	}
 
-	// Check if the deletion was successful
-	if deleteResponse.Status == "Success" || deleteResponse.Status == "" {
-		fmt.Printf("Spot node pool '%s' deleted successfully from namespace '%s'\n", spotNodePoolName, namespace)
-	} else {
-		fmt.Printf("Delete operation completed with status: %s\n", deleteResponse.Status)
-		if deleteResponse.Message != "" {
-			fmt.Printf("Message: %s\n", deleteResponse.Message)
-		}
-	}
-	return nil
+	// Several spot node pools are deleted concurrently, with a summary at the end
+	return cmdutil.DeleteTargets(cmd.Context(), apiClient, targets, cmd.OutOrStdout(), cmd.ErrOrStderr(), dryRun)
 }
@@ -0,0 +1,147 @@
+package spotnodepool
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/pager"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// NewDescribeCommand returns the spotnodepool describe command
+func NewDescribeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe <spotnodepool-name>",
+		Short: "Show a detailed description of a spot node pool",
+		Long: `Show a detailed, human-readable description of a spot node pool.
+
+The description includes the node pool metadata, its spec (server class, bid
+price, autoscaling and custom metadata) and its status, including the status of
+custom labels, annotations and taints applied to the nodes. Its events show
+only when it was created and when its deletion was requested, as spot node pools
+report no conditions or transition times.
+
+The namespace can be specified via:
+- The --namespace/-n flag
+- The --org flag, naming an organization whose namespace to use
+- The 'namespace' field in your config file
+- The SPOTCTL_NAMESPACE environment variable
+- The 'org' field in your config file or SPOTCTL_ORG (see 'spotctl config set-org')
+
+Examples:
+  # Describe a spot node pool using namespace from config
+  spotctl spotnodepool describe my-nodepool
+
+  # Describe a spot node pool in a specific namespace
+  spotctl spotnodepool describe my-nodepool --namespace org-abc123`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.SpotNodePoolNames,
+		RunE:              runDescribe,
+	}
+
+	// Add flags for spotnodepool describe command
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of the spot node pool (overrides config)")
+
+	return cmd
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	spotNodePoolName := args[0] // Get name from positional argument
+
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+	spotNodePool, err := apiClient.GetSpotNodePool(ctx, namespace, spotNodePoolName)
+	if err != nil {
+		return fmt.Errorf("failed to get spot node pool: %w", err)
+	}
+
+	p := pager.NewPager()
+	p.Disable = viper.GetBool("no-pager")
+	return p.WriteToWriter(func(w io.Writer) error {
+		return describeSpotNodePool(w, spotNodePool, time.Now())
+	})
+}
+
+// describeSpotNodePool renders the spot node pool description to w
+func describeSpotNodePool(w io.Writer, pool *client.SpotNodePool, now time.Time) error {
+	d := output.NewDescribeWriter(w)
+
+	d.Field(0, "Name", pool.Metadata.Name)
+	d.Field(0, "Namespace", pool.Metadata.Namespace)
+	d.Field(0, "Created", output.Timestamp(pool.Metadata.CreationTimestamp, now))
+	d.Map(0, "Labels", pool.Metadata.Labels)
+	d.Map(0, "Annotations", pool.Metadata.Annotations)
+
+	d.Section(0, "Spec")
+	d.Field(1, "Cloud Space", pool.Spec.CloudSpace)
+	d.Field(1, "Server Class", pool.Spec.ServerClass)
+	d.Field(1, "Desired", pool.Spec.Desired)
+	d.Field(1, "Bid Price", pool.Spec.BidPrice)
+	if pool.Spec.Autoscaling != nil {
+		d.Section(1, "Autoscaling")
+		d.Field(2, "Enabled", strconv.FormatBool(pool.Spec.Autoscaling.Enabled))
+		d.Field(2, "Min Nodes", pool.Spec.Autoscaling.MinNodes)
+		d.Field(2, "Max Nodes", pool.Spec.Autoscaling.MaxNodes)
+	} else {
+		d.Field(1, "Autoscaling", "")
+	}
+	d.Map(1, "Custom Labels", pool.Spec.CustomLabels)
+	d.Map(1, "Custom Annotations", pool.Spec.CustomAnnotations)
+	taints := make([]string, 0, len(pool.Spec.CustomTaints))
+	for _, taint := range pool.Spec.CustomTaints {
+		taints = append(taints, formatTaint(taint))
+	}
+	d.List(1, "Custom Taints", taints)
+
+	d.Section(0, "Status")
+	d.Field(1, "Bid Status", pool.Status.BidStatus)
+	d.Field(1, "Won Count", pool.Status.WonCount)
+	if metadataStatus := pool.Status.CustomMetadataStatus; metadataStatus != nil {
+		d.Section(1, "Custom Metadata Status")
+		d.List(2, "Labels", metadataStatus.Labels)
+		d.List(2, "Annotations", metadataStatus.Annotations)
+		d.List(2, "Taints", metadataStatus.Taints)
+	} else {
+		d.Field(1, "Custom Metadata Status", "")
+	}
+
+	// Spot node pools report no conditions or transition times, so only their
+	// creation and deletion can be placed in time
+	d.Events(0, []output.Event{
+		{Time: pool.Metadata.CreationTimestamp, Type: output.EventNormal, Reason: "Created", Message: "Spot node pool created"},
+		{Time: pool.Metadata.DeletionTimestamp, Type: output.EventNormal, Reason: "Deleting", Message: "Spot node pool deletion requested"},
+	}, now)
+
+	return d.Flush()
+}
+
+// formatTaint renders a taint in kubectl's key=value:Effect form
+func formatTaint(taint client.SpotNodePoolTaint) string {
+	result := taint.Key
+	if taint.Value != "" {
+		result += "=" + taint.Value
+	}
+	if taint.Effect != "" {
+		result += ":" + taint.Effect
+	}
+	return result
+}
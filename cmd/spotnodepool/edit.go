@@ -5,16 +5,26 @@ import (
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
 // NewEditCommand returns the spotnodepool edit command
 func NewEditCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "edit <spotnodepool-name>",
-		Short: "Edit a spot node pool",
-		Long: `Edit a spot node pool in the specified namespace using JSON patch operations.
+		Use:   "edit (NAME... | -f FILE) --patch FILE",
+		Short: "Edit spot node pools",
+		Long: `Edit one or more spot node pools in the specified namespace using JSON patch operations.
+The same patch is applied to each spot node pool named in turn; any that fail are
+reported and the rest are still edited.
+
+The spot node pools can also be named with --filename/-f by a manifest or a list of
+references one per line, as printed by -o name; use -f - to read them from stdin.
+The patch is read from --patch/-p. Earlier versions read it from --file/-f, which
+is deprecated; -f now names the spot node pools to edit, as for 'spotctl edit'.
 
 The patch operations should be provided in a JSON file with the following format:
 [
@@ -39,46 +49,66 @@ Supported operations are: add, remove, replace, move, copy, test.
 
 Examples:
   # Edit a spot node pool and show the result in table format
-  spotctl spotnodepool edit my-nodepool --namespace org-abc123 --file patch.json
+  spotctl spotnodepool edit my-nodepool --namespace org-abc123 --patch patch.json
+
+  # Apply the same patch to several spot node pools
+  spotctl spotnodepool edit pool-a pool-b --namespace org-abc123 --patch patch.json
 
   # Edit with detailed information output
-  spotctl spotnodepool edit my-nodepool --namespace org-abc123 --file patch.json -o wide
+  spotctl spotnodepool edit my-nodepool --namespace org-abc123 --patch patch.json -o wide
 
   # Edit and output the result as JSON
-  spotctl spotnodepool edit my-nodepool --namespace org-abc123 --file patch.json --output json
+  spotctl spotnodepool edit my-nodepool --namespace org-abc123 --patch patch.json --output json
 
   # Edit and output the result as YAML (skip confirmation)
-  spotctl spotnodepool edit my-nodepool --namespace org-abc123 --file patch.json --output yaml --confirm`,
-		Args: cobra.ExactArgs(1),
-		RunE: runEdit,
+  spotctl spotnodepool edit my-nodepool --namespace org-abc123 --patch patch.json --output yaml --confirm
+
+  # Edit the spot node pools labelled env=dev
+  spotctl spotnodepool list -l env=dev -o name | spotctl spotnodepool edit -f - --patch patch.json --confirm
+
+  # Validate the patch with the API without applying it
+  spotctl spotnodepool edit my-nodepool --namespace org-abc123 --patch patch.json --dry-run=server`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completion.Each(completion.SpotNodePoolNames),
+		RunE:              runEdit,
 	}
 
 	// Add flags for spotnodepool edit command
-	cmd.Flags().StringP("namespace", "n", "", "Namespace of the spot node pool (required)")
-	cmd.Flags().StringP("file", "f", "", "Path to the JSON file containing patch operations (required)")
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of the spot node pools (overrides config)")
+	cmdutil.AddPatchFlags(cmd)
+	cmdutil.AddFilenameFlag(cmd)
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
 	cmd.Flags().Bool("confirm", false, "Skip confirmation prompt")
-
-	// Mark flags as required
-	cmd.MarkFlagRequired("namespace")
-	cmd.MarkFlagRequired("file")
+	cmdutil.AddDryRunFlag(cmd)
 
 	return cmd
 }
 
 func runEdit(cmd *cobra.Command, args []string) error {
-	namespace, _ := cmd.Flags().GetString("namespace")
-	file, _ := cmd.Flags().GetString("file")
+	dryRun, err := cmdutil.DryRun(cmd)
+	if err != nil {
+		return err
+	}
+	skipConfirmation, _ := cmd.Flags().GetBool("confirm")
+	if err := cmdutil.RequireConfirmForStdin(cmd, skipConfirmation, dryRun); err != nil {
+		return err
+	}
+
+	targets, err := cmdutil.KindTargets(cmd, registry.SpotNodePools, args)
+	if err != nil {
+		return err
+	}
 	outputFormat, _ := cmd.Flags().GetString("output")
 
 	// Load the JSON patch operations from the file
-	patchOps, err := client.LoadPatchOperations(file)
+	patchOps, err := cmdutil.LoadPatch(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to load patch operations: %w", err)
+		return err
 	}
 
-	// Display the patch operations that will be applied
-	client.DisplayPatchOperations(patchOps)
+	// The patch and prompt go to stderr so that -o json and -o yaml stay parseable
+	errOut := cmd.ErrOrStderr()
+	client.WritePatchOperations(errOut, patchOps)
 
 	// Create a new client
 	cfg, err := config.GetConfig()
@@ -86,26 +116,49 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 	apiClient := client.NewClient(cfg)
+	if dryRun {
+		apiClient = apiClient.WithDryRun()
+	}
 
-	// Prompt for confirmation if the --confirm flag is not set
-	skipConfirmation, _ := cmd.Flags().GetBool("confirm")
-	if !skipConfirmation {
-		confirmed, err := client.PromptForConfirmation(fmt.Sprintf("spot node pool '%s'", args[0]))
+	// Prompt for confirmation if the --confirm flag is not set; a dry run changes nothing
+	if !skipConfirmation && !dryRun {
+		confirmed, err := client.PromptForConfirmationTo(errOut, cmdutil.DescribeTargets(targets))
 		if err != nil {
 			return err
 		}
 		if !confirmed {
-			fmt.Println("Patch operation cancelled.")
+			fmt.Fprintln(errOut, "Patch operation cancelled.")
 			return nil
 		}
 	}
 
-	// Apply the patch operations
-	updatedSpotNodePool, err := apiClient.EditSpotNodePool(context.Background(), namespace, args[0], patchOps)
-	if err != nil {
-		return fmt.Errorf("failed to edit spot node pool: %w", err)
+	// A single spot node pool fails like any other command
+	if len(targets) == 1 {
+		t := targets[0]
+		updatedSpotNodePool, err := apiClient.EditSpotNodePool(cmd.Context(), t.Namespace, t.Name, patchOps)
+		if err != nil {
+			return fmt.Errorf("failed to edit spot node pool: %w", err)
+		}
+		if dryRun {
+			fmt.Fprintf(errOut, "Server dry run: spot node pool '%s' was validated but not changed\n", t.Name)
+		}
+
+		// Output the updated spot node pool using the same formatting as the get command
+		return registry.Output(registry.SpotNodePools, updatedSpotNodePool, outputFormat)
 	}
 
-	// Output the updated spot node pool using the same formatting as the get command
-	return outputSpotNodePool(updatedSpotNodePool, outputFormat)
+	// Several spot node pools are patched in turn and shown as a list, after reporting any that failed
+	items, editErr := cmdutil.EachTarget(cmd.Context(), targets, errOut, "edit", func(ctx context.Context, t cmdutil.Target) (*client.SpotNodePool, error) {
+		updatedSpotNodePool, err := apiClient.EditSpotNodePool(ctx, t.Namespace, t.Name, patchOps)
+		if err == nil && dryRun {
+			fmt.Fprintf(errOut, "Server dry run: spot node pool '%s' was validated but not changed\n", t.Name)
+		}
+		return updatedSpotNodePool, err
+	})
+	if len(items) > 0 {
+		if err := registry.OutputList(registry.SpotNodePools, items, outputFormat, ""); err != nil {
+			return err
+		}
+	}
+	return editErr
 }
@@ -5,16 +5,19 @@ import (
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/completion"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
 // NewGetCommand returns the spotnodepool get command
 func NewGetCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "get [NAME]",
-		Short: "Get a specific spot node pool",
-		Long: `Get detailed information about a specific spot node pool by name in the specified namespace.
+		Use:   "get NAME...",
+		Short: "Get specific spot node pools",
+		Long: `Get detailed information about one or more spot node pools by name in the specified namespace.
 
 Examples:
   # Get a specific spot node pool
@@ -27,14 +30,18 @@ Examples:
   spotctl spotnodepool get my-nodepool --namespace org-abc123 --output json
 
   # Get spot node pool with YAML output
-  spotctl spotnodepool get my-nodepool --namespace org-abc123 --output yaml`,
-		Args: cobra.ExactArgs(1),
-		RunE: runGet,
+  spotctl spotnodepool get my-nodepool --namespace org-abc123 --output yaml
+
+  # Get several spot node pools
+  spotctl spotnodepool get pool-a pool-b --namespace org-abc123`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completion.Each(completion.SpotNodePoolNames),
+		RunE:              runGet,
 	}
 
 	// Add flags for spotnodepool get command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
-	cmd.Flags().StringP("namespace", "n", "", "Namespace of the spot node pool (required)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace of the spot node pool (overrides config)")
 
 	return cmd
 }
@@ -43,11 +50,11 @@ func runGet(cmd *cobra.Command, args []string) error {
 	spotNodePoolName := args[0] // Get name from positional argument
 
 	// Get flag values
-	namespace, _ := cmd.Flags().GetString("namespace")
 	outputFormat, _ := cmd.Flags().GetString("output")
 
-	if namespace == "" {
-		return fmt.Errorf("namespace is required")
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
 	}
 
 	cfg, err := config.GetConfig()
@@ -55,13 +62,27 @@ func runGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	client := client.NewClient(cfg)
+	apiClient := client.NewClient(cfg)
+
+	ctx := cmd.Context()
+
+	// Several spot node pools are shown as a list, after reporting any that could not be found
+	if len(args) > 1 {
+		items, getErr := cmdutil.EachName(ctx, args, cmd.ErrOrStderr(), "get", "spot node pool", func(ctx context.Context, name string) (*client.SpotNodePool, error) {
+			return apiClient.GetSpotNodePool(ctx, namespace, name)
+		})
+		if len(items) > 0 {
+			if err := registry.OutputList(registry.SpotNodePools, items, outputFormat, namespace); err != nil {
+				return err
+			}
+		}
+		return getErr
+	}
 
-	ctx := context.Background()
-	spotNodePool, err := client.GetSpotNodePool(ctx, namespace, spotNodePoolName)
+	spotNodePool, err := apiClient.GetSpotNodePool(ctx, namespace, spotNodePoolName)
 	if err != nil {
 		return fmt.Errorf("failed to get spot node pool: %w", err)
 	}
 
-	return outputSpotNodePool(spotNodePool, outputFormat)
+	return registry.Output(registry.SpotNodePools, spotNodePool, outputFormat)
 }
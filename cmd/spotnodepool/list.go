@@ -1,11 +1,12 @@
 package spotnodepool
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
 	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
@@ -24,22 +25,47 @@ Examples:
   spotctl spotnodepool list --namespace my-namespace -o wide
 
   # List spot node pools with JSON output
-  spotctl spotnodepool list --namespace my-namespace --output json`,
+  spotctl spotnodepool list --namespace my-namespace --output json
+
+  # List spot node pools in the namespaces of all your organizations
+  spotctl spotnodepool list -A
+
+  # Delete the spot node pools labelled env=dev in every namespace; with -A the
+  # names are prefixed by their namespace, e.g. org-abc123/spotnodepool/my-pool
+  spotctl spotnodepool list -A -l env=dev -o name | spotctl delete -f - --confirm`,
 		Args: cobra.NoArgs,
 		RunE: runList,
 	}
 
 	// Add flags for spotnodepool list command
-	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide)")
-	cmd.Flags().StringP("namespace", "n", "", "Namespace to list spot node pools from (required)")
+	cmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml, wide, name)")
+	cmd.Flags().StringP("namespace", "n", "", "Namespace to list spot node pools from (overrides config)")
+	cmdutil.AddListFlags(cmd)
+	cmdutil.AddAllNamespacesFlag(cmd)
 
 	return cmd
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	namespace, _ := cmd.Flags().GetString("namespace")
-	if namespace == "" {
-		return fmt.Errorf("namespace is required")
+	allNamespaces, err := cmdutil.AllNamespaces(cmd)
+	if err != nil {
+		return err
+	}
+
+	listOpts, limit, err := cmdutil.ListFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	if allNamespaces {
+		return cmdutil.ListKindAllNamespaces(cmd, registry.SpotNodePools, listOpts, limit, outputFormat)
+	}
+
+	namespace, err := cmdutil.ResolveNamespace(cmd)
+	if err != nil {
+		return err
 	}
 
 	cfg, err := config.GetConfig()
@@ -47,15 +73,13 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	client := client.NewClient(cfg)
+	apiClient := client.NewClient(cfg)
 
-	ctx := context.Background()
-	spotNodePoolList, err := client.ListSpotNodePools(ctx, namespace)
+	ctx := cmd.Context()
+	items, err := client.Collect(apiClient.AllSpotNodePools(ctx, namespace, listOpts), limit)
 	if err != nil {
 		return fmt.Errorf("failed to list spot node pools: %w", err)
 	}
 
-	outputFormat, _ := cmd.Flags().GetString("output")
-
-	return outputSpotNodePools(spotNodePoolList, outputFormat, namespace)
+	return registry.OutputList(registry.SpotNodePools, items, outputFormat, namespace)
 }
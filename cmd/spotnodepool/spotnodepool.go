@@ -1,6 +1,8 @@
 package spotnodepool
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/spf13/cobra"
+)
 
 // NewCommand returns the main spotnodepool command with all subcommands
 func NewCommand() *cobra.Command {
@@ -16,6 +18,7 @@ Spot node pools represent groups of worker nodes deployed through Rackspace Spot
 	// Add all subcommands
 	cmd.AddCommand(NewListCommand())
 	cmd.AddCommand(NewGetCommand())
+	cmd.AddCommand(NewDescribeCommand())
 	cmd.AddCommand(NewCreateCommand())
 	cmd.AddCommand(NewEditCommand())
 	cmd.AddCommand(NewDeleteCommand())
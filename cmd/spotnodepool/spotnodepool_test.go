@@ -2,12 +2,19 @@ package spotnodepool
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // MockSpotNodePoolClient implements a simple mock for testing spotnodepool command business logic
@@ -171,7 +178,7 @@ func TestSpotNodePoolGetCommand(t *testing.T) {
 }
 
 func TestGetSpotNodePoolTableConfig(t *testing.T) {
-	config := getSpotNodePoolTableConfig()
+	config := registry.SpotNodePools.TableConfig()
 
 	// Check that we have the expected columns
 	if len(config.Columns) != 6 {
@@ -505,3 +512,115 @@ func TestSpotNodePoolCreateCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribeSpotNodePool(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-2 * time.Hour)
+	desired, won, minNodes, maxNodes := 3, 2, 1, 5
+
+	pool := &client.SpotNodePool{
+		Metadata: client.ObjectMeta{Name: "pool-a", Namespace: "org-abc123", CreationTimestamp: &created},
+		Spec: client.SpotNodePoolSpec{
+			CloudSpace:   "my-cloudspace",
+			ServerClass:  "gp.vs1.large-lon",
+			Desired:      &desired,
+			BidPrice:     "0.05",
+			Autoscaling:  &client.SpotNodePoolAutoscaling{Enabled: true, MinNodes: &minNodes, MaxNodes: &maxNodes},
+			CustomLabels: map[string]string{"env": "dev"},
+			CustomTaints: []client.SpotNodePoolTaint{{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}},
+		},
+		Status: client.SpotNodePoolStatus{
+			BidStatus: "Won",
+			WonCount:  &won,
+			CustomMetadataStatus: &client.SpotNodePoolMetadataStatus{
+				Labels: []string{"env=dev applied"},
+				Taints: []string{"dedicated=gpu:NoSchedule pending"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := describeSpotNodePool(&buf, pool, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"pool-a",
+		"(2h ago)",
+		"my-cloudspace",
+		"Autoscaling:",
+		"Max Nodes:",
+		"env=dev",
+		"dedicated=gpu:NoSchedule",
+		"Custom Metadata Status:",
+		"env=dev applied",
+		"dedicated=gpu:NoSchedule pending",
+		"Events:",
+		"Spot node pool created",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestSpotNodePoolEditFilename(t *testing.T) {
+	server := spottest.NewTestServer(t)
+	server.Configure(t)
+	viper.Set("namespace", spottest.DefaultNamespace)
+	pool := func(namespace, name string) client.SpotNodePool {
+		return client.SpotNodePool{Metadata: client.ObjectMeta{Name: name, Namespace: namespace}, Spec: client.SpotNodePoolSpec{ServerClass: "gp.vs1.medium-dfw", BidPrice: "0.01"}}
+	}
+	if err := server.Seed(pool(spottest.DefaultNamespace, "pool-a"), pool("org-other", "pool-b")); err != nil {
+		t.Fatal(err)
+	}
+	// The edited pools are printed to stdout, which the test does not need
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = devNull
+	t.Cleanup(func() { os.Stdout = stdout; devNull.Close() })
+
+	patch := filepath.Join(t.TempDir(), "patch.json")
+	if err := os.WriteFile(patch, []byte(`[{"op":"replace","path":"/spec/bidPrice","value":"0.05"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(stdin string, args ...string) (string, error) {
+		var errOut bytes.Buffer
+		cmd := NewEditCommand()
+		cmd.SetArgs(args)
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&errOut)
+		err := cmd.Execute()
+		return errOut.String(), err
+	}
+
+	// -f names the pools, each in its own namespace, and -p the patch
+	stderr, err := run("spotnodepool/pool-a\norg-other/spotnodepool/pool-b\n", "-f", "-", "-p", patch, "-o", "name", "--confirm")
+	if err != nil {
+		t.Fatalf("Edit failed: %v\n%s", err, stderr)
+	}
+	if !strings.Contains(stderr, "Applying 1 patch operation(s)") {
+		t.Errorf("Expected the patch on stderr, got %q", stderr)
+	}
+	apiClient := client.NewClient(server.Config())
+	for _, ref := range [][2]string{{spottest.DefaultNamespace, "pool-a"}, {"org-other", "pool-b"}} {
+		edited, err := apiClient.GetSpotNodePool(context.Background(), ref[0], ref[1])
+		if err != nil || edited.Spec.BidPrice != "0.05" {
+			t.Errorf("Expected %s/%s to be patched, got %+v, %v", ref[0], ref[1], edited, err)
+		}
+	}
+
+	// The deprecated --file still names the patch
+	if _, err := run("", "pool-a", "--file", patch, "-o", "name", "--confirm"); err != nil {
+		t.Errorf("Expected the deprecated --file to name the patch, got %v", err)
+	}
+	if _, err := run("", "pool-a", "-o", "name", "--confirm"); err == nil {
+		t.Error("Expected an error without --patch")
+	}
+}
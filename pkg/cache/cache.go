@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache is a small file based cache for JSON serialisable values that expire after a TTL.
+// It is intended for data that is cheap to refetch, such as resource names used by shell
+// completion, so read and write failures are never fatal.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// entry is the on-disk representation of a cached value
+type entry struct {
+	StoredAt time.Time       `json:"storedAt"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// New creates a cache storing entries in dir that expire after ttl
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+// DefaultDir returns the spotctl cache directory for the given purpose,
+// e.g. ~/.cache/spotctl/completion on Linux
+func DefaultDir(purpose string) (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+	return filepath.Join(base, "spotctl", purpose), nil
+}
+
+// ClearAll removes everything spotctl has cached, for every purpose
+func ClearAll() error {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+	if err := os.RemoveAll(filepath.Join(base, "spotctl")); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// Get loads the value stored under key into value. It returns false if there is
+// no entry, the entry has expired or it cannot be decoded.
+func (c *Cache) Get(key string, value interface{}) bool {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false
+	}
+	if c.now().Sub(e.StoredAt) > c.ttl {
+		return false
+	}
+	return json.Unmarshal(e.Value, value) == nil
+}
+
+// Set stores value under key, replacing any existing entry
+func (c *Cache) Set(key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	data, err := json.Marshal(entry{StoredAt: c.now(), Value: raw})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file and rename so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(c.dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// path returns the file an entry is stored in; keys are hashed so they can contain any characters
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheSetGet(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "completion")
+	c := New(dir, time.Minute)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	var names []string
+	if c.Get("regions", &names) {
+		t.Fatal("Expected miss for empty cache")
+	}
+
+	if err := c.Set("regions", []string{"uk-lon-1", "us-central-dfw-1"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if !c.Get("regions", &names) {
+		t.Fatal("Expected hit after Set")
+	}
+	if len(names) != 2 || names[0] != "uk-lon-1" {
+		t.Errorf("Unexpected cached value: %v", names)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("Expected cache directory to be created: %v", err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("Expected cache directory mode 0700, got %o", info.Mode().Perm())
+	}
+
+	// Entries expire after the TTL
+	now = now.Add(2 * time.Minute)
+	if c.Get("regions", &names) {
+		t.Error("Expected miss for expired entry")
+	}
+}
+
+func TestCacheKeysAreIndependent(t *testing.T) {
+	c := New(t.TempDir(), time.Minute)
+
+	if err := c.Set("https://example.com|cloudspaces|org-a", []string{"a"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := c.Set("https://example.com|cloudspaces|org-b", []string{"b"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	var names []string
+	if !c.Get("https://example.com|cloudspaces|org-a", &names) || names[0] != "a" {
+		t.Errorf("Expected org-a entry, got %v", names)
+	}
+}
+
+func TestCacheCorruptEntry(t *testing.T) {
+	c := New(t.TempDir(), time.Minute)
+	if err := os.WriteFile(c.path("regions"), []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	if c.Get("regions", &names) {
+		t.Error("Expected miss for corrupt entry")
+	}
+}
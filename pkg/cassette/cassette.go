@@ -0,0 +1,214 @@
+// Package cassette records HTTP interactions to a file and replays them, so API
+// sessions can be attached to bug reports and used as test fixtures.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the name of the cassette file inside a cassette directory
+const FileName = "cassette.json"
+
+// Redacted replaces secrets in recorded interactions
+const Redacted = "REDACTED"
+
+// Version is the cassette file format version
+const Version = 1
+
+// secretFields are JSON and form fields whose values are never recorded
+var secretFields = []string{"refresh_token", "access_token", "id_token", "device_code"}
+
+// Cassette is a recorded sequence of HTTP interactions
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request and the response it received
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Body is a recorded message body. JSON object and array bodies are stored as
+// JSON so cassettes stay readable; anything else is stored as a string.
+type Body []byte
+
+// MarshalJSON implements json.Marshaler
+func (b Body) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(`""`), nil
+	}
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(b) {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, b); err == nil {
+			return compact.Bytes(), nil
+		}
+	}
+	return json.Marshal(string(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+	// Undo the indentation added when the cassette was saved
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return err
+	}
+	*b = compact.Bytes()
+	return nil
+}
+
+// Load reads the cassette in dir
+func Load(dir string) (*Cassette, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", filepath.Join(dir, FileName), err)
+	}
+	if c.Version != Version {
+		return nil, fmt.Errorf("unsupported cassette version %d (expected %d)", c.Version, Version)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to dir, creating it if needed
+func (c *Cassette) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	// Write atomically so an interrupted recording never leaves a truncated cassette
+	tmp := filepath.Join(dir, FileName+".tmp")
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(dir, FileName))
+}
+
+// key identifies requests that replay the same recorded response
+func (r Request) key() string {
+	return r.Method + " " + r.URL + "\n" + string(r.Body)
+}
+
+// newRequest captures req with its secrets redacted, leaving req readable
+func newRequest(req *http.Request) (Request, error) {
+	recorded := Request{Method: req.Method, URL: req.URL.String(), Headers: RedactHeaders(req.Header)}
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return recorded, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	recorded.Body = RedactBody(body, req.Header.Get("Content-Type"))
+	return recorded, nil
+}
+
+// RedactHeaders copies headers, hiding credentials
+func RedactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for _, name := range []string{"Authorization", "Cookie", "Set-Cookie"} {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, Redacted)
+		}
+	}
+	return redacted
+}
+
+// RedactBody hides token fields in JSON and form encoded bodies
+func RedactBody(body []byte, contentType string) Body {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		for _, field := range secretFields {
+			if form.Has(field) {
+				form.Set(field, Redacted)
+			}
+		}
+		return Body(form.Encode())
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return body
+	}
+	if !redactJSON(doc) {
+		return body
+	}
+	redacted, err := json.Marshal(doc)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactJSON hides secret fields anywhere in a decoded JSON document, reporting whether any were found
+func redactJSON(doc any) bool {
+	found := false
+	switch v := doc.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if isSecretField(key) {
+				if _, ok := value.(string); ok {
+					v[key] = Redacted
+					found = true
+					continue
+				}
+			}
+			found = redactJSON(value) || found
+		}
+	case []any:
+		for _, value := range v {
+			found = redactJSON(value) || found
+		}
+	}
+	return found
+}
+
+func isSecretField(name string) bool {
+	for _, field := range secretFields {
+		if strings.EqualFold(name, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package cassette
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/oauth/token" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"id_token":"secret-id-token","expires_in":3600}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"call":%d}`, calls)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	recording := &http.Client{Transport: recorder}
+
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"secret-refresh-token"}}
+	resp, err := recording.PostForm(server.URL+"/oauth/token", form)
+	if err != nil {
+		t.Fatalf("Token request failed: %v", err)
+	}
+	if body := readBody(t, resp); !strings.Contains(body, "secret-id-token") {
+		t.Errorf("Expected the caller to receive the real token while recording, got %s", body)
+	}
+	for range 2 {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/regions", nil)
+		req.Header.Set("Authorization", "Bearer secret-id-token")
+		resp, err := recording.Do(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		readBody(t, resp)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("Cassette was not written: %v", err)
+	}
+	for _, secret := range []string{"secret-refresh-token", "secret-id-token"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette contains %q", secret)
+		}
+	}
+
+	server.Close()
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	replaying := &http.Client{Transport: replayer}
+
+	// The refresh token differs, but it is redacted before matching
+	form.Set("refresh_token", "another-token")
+	resp, err = replaying.PostForm(server.URL+"/oauth/token", form)
+	if err != nil {
+		t.Fatalf("Replayed token request failed: %v", err)
+	}
+	if body := readBody(t, resp); !strings.Contains(body, `"id_token":"REDACTED"`) {
+		t.Errorf("Expected the redacted token response, got %s", body)
+	}
+
+	// Identical requests get their responses in recorded order, then the last one again
+	for _, want := range []string{`{"call":2}`, `{"call":3}`, `{"call":3}`} {
+		resp, err := replaying.Get(server.URL + "/regions")
+		if err != nil {
+			t.Fatalf("Replayed request failed: %v", err)
+		}
+		if body := readBody(t, resp); body != want {
+			t.Errorf("Expected %s, got %s", want, body)
+		}
+	}
+
+	if _, err := replaying.Get(server.URL + "/serverclasses"); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("Expected an error for an unrecorded request, got %v", err)
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	if _, err := NewReplayer(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error replaying a missing cassette")
+	}
+}
+
+func TestBodyJSON(t *testing.T) {
+	for _, body := range []string{`{"a":1}`, `[1,2]`, `plain text`, `"quoted"`, ``} {
+		data, err := Body(body).MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON(%q) failed: %v", body, err)
+		}
+		var decoded Body
+		if err := decoded.UnmarshalJSON(data); err != nil {
+			t.Fatalf("UnmarshalJSON(%s) failed: %v", data, err)
+		}
+		if string(decoded) != body {
+			t.Errorf("Round trip of %q gave %q", body, decoded)
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	form := url.Values{"client_id": {"spotctl"}, "device_code": {"secret-device-code"}}
+	if got := string(RedactBody([]byte(form.Encode()), "application/x-www-form-urlencoded")); got != "client_id=spotctl&device_code="+Redacted {
+		t.Errorf("Expected the device code to be redacted, got %q", got)
+	}
+
+	got := string(RedactBody([]byte(`{"device_code":"secret-device-code","interval":5}`), "application/json"))
+	if strings.Contains(got, "secret-device-code") {
+		t.Errorf("Expected the device code to be redacted, got %q", got)
+	}
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
@@ -0,0 +1,173 @@
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"sync"
+)
+
+// recording is the cassette being recorded in a directory
+type recording struct {
+	mu       sync.Mutex
+	dir      string
+	cassette *Cassette
+}
+
+// playback is the cassette being replayed from a directory
+type playback struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	served   map[string]int
+}
+
+// Every client in the process shares one recording or playback per directory, so
+// interactions are appended and replayed as a single sequence
+var (
+	openMu     sync.Mutex
+	recordings = map[string]*recording{}
+	playbacks  = map[string]*playback{}
+)
+
+// recorder is a RoundTripper that records interactions made through next
+type recorder struct {
+	next      http.RoundTripper
+	recording *recording
+}
+
+// NewRecorder returns a RoundTripper that sends requests through next (or
+// http.DefaultTransport if nil) and appends every interaction, with credentials
+// redacted, to the cassette in dir. An existing cassette is extended.
+func NewRecorder(dir string, next http.RoundTripper) (http.RoundTripper, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	openMu.Lock()
+	defer openMu.Unlock()
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	rec, ok := recordings[abs]
+	if !ok {
+		c, err := Load(abs)
+		if errors.Is(err, fs.ErrNotExist) {
+			c, err = &Cassette{Version: Version}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rec = &recording{dir: abs, cassette: c}
+		recordings[abs] = rec
+	}
+	return &recorder{next: next, recording: rec}, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recordedReq, err := newRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: failed to read request body: %w", err)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Redaction can change the body's length, so the recorded length would be stale
+	headers := RedactHeaders(resp.Header)
+	headers.Del("Content-Length")
+	interaction := Interaction{
+		Request: recordedReq,
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: headers,
+			Body:    RedactBody(body, resp.Header.Get("Content-Type")),
+		},
+	}
+
+	r.recording.mu.Lock()
+	defer r.recording.mu.Unlock()
+	r.recording.cassette.Interactions = append(r.recording.cassette.Interactions, interaction)
+	if err := r.recording.cassette.Save(r.recording.dir); err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// replayer is a RoundTripper that answers requests from a cassette
+type replayer struct {
+	playback *playback
+}
+
+// NewReplayer returns a RoundTripper that answers requests from the cassette in
+// dir without touching the network. Identical requests receive their recorded
+// responses in order; once those run out the last one is repeated. A request
+// that was never recorded fails.
+func NewReplayer(dir string) (http.RoundTripper, error) {
+	openMu.Lock()
+	defer openMu.Unlock()
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	pb, ok := playbacks[abs]
+	if !ok {
+		c, err := Load(abs)
+		if err != nil {
+			return nil, err
+		}
+		pb = &playback{cassette: c, served: map[string]int{}}
+		playbacks[abs] = pb
+	}
+	return &replayer{playback: pb}, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	recordedReq, err := newRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: failed to read request body: %w", err)
+	}
+	key := recordedReq.key()
+
+	r.playback.mu.Lock()
+	var matches []Interaction
+	for _, interaction := range r.playback.cassette.Interactions {
+		if interaction.Request.key() == key {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		r.playback.mu.Unlock()
+		return nil, fmt.Errorf("cassette: no recorded response for %s %s", req.Method, req.URL)
+	}
+	index := min(r.playback.served[key], len(matches)-1)
+	r.playback.served[key]++
+	r.playback.mu.Unlock()
+
+	recorded := matches[index].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
@@ -3,12 +3,16 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/version"
 )
 
 // TokenManagerInterface defines the interface for token management
@@ -21,33 +25,79 @@ const (
 	OAuthURL  = "https://login.spot.rackspace.com/oauth/token"
 	ClientID  = "mwG3lUMV8KyeMqHe4fJ5Bb3nM1vBvRNa"
 	GrantType = "refresh_token"
+
+	// DeviceAuthURL is the device authorization endpoint (RFC 8628)
+	DeviceAuthURL = "https://login.spot.rackspace.com/oauth/device/code"
+	// DeviceCodeGrantType is the grant type used to poll for device authorization
+	DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// DeviceScope requests an ID token and a refresh token for the CLI to keep
+	DeviceScope = "openid profile email offline_access"
 )
 
 // TokenResponse represents the OAuth token response
 type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	IDToken     string `json:"id_token"`
-	Scope       string `json:"scope"`
-	ExpiresIn   int    `json:"expires_in"`
-	TokenType   string `json:"token_type"`
+	AccessToken  string `json:"access_token"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope"`
+	ExpiresIn    int    `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+}
+
+// OAuthError is an error response from an OAuth endpoint (RFC 6749 section 5.2)
+type OAuthError struct {
+	StatusCode  int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("%s: %s (status %d)", e.Code, e.Description, e.StatusCode)
+	}
+	return fmt.Sprintf("%s (status %d)", e.Code, e.StatusCode)
+}
+
+// readOAuthError builds an error from a failed OAuth response, falling back to
+// the status code when the body is not an OAuth error
+func readOAuthError(resp *http.Response) error {
+	var oauthErr OAuthError
+	if err := json.NewDecoder(resp.Body).Decode(&oauthErr); err != nil || oauthErr.Code == "" {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	oauthErr.StatusCode = resp.StatusCode
+	return &oauthErr
+}
+
+// postForm sends an OAuth form request to endpoint
+func postForm(ctx context.Context, httpClient *http.Client, endpoint string, form url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", version.GetUserAgent())
+	return httpClient.Do(req)
 }
 
 // TokenManager handles OAuth token management
 type TokenManager struct {
 	refreshToken string
+	tokenURL     string
 	accessToken  string
 	expiresAt    time.Time
 	httpClient   *http.Client
 	mutex        sync.RWMutex
-	debug        bool
+	logger       *slog.Logger
+	rotated      func(refreshToken string) error
 }
 
 // NewTokenManager creates a new token manager
-func NewTokenManager(refreshToken string, httpClient *http.Client, debug bool) *TokenManager {
+func NewTokenManager(refreshToken string, httpClient *http.Client) *TokenManager {
 	return &TokenManager{
 		refreshToken: refreshToken,
+		tokenURL:     OAuthURL,
 		httpClient:   httpClient,
-		debug:        debug,
 	}
 }
 
@@ -76,9 +126,9 @@ func (tm *TokenManager) refreshAccessToken(ctx context.Context) (string, error)
 		return tm.accessToken, nil
 	}
 
-	if tm.debug {
-		fmt.Println("Refreshing OAuth access token...")
-	}
+	logger := tm.log()
+	logger.Debug("Refreshing OAuth access token", "url", tm.tokenURL)
+	start := time.Now()
 
 	// Prepare form data
 	data := url.Values{}
@@ -86,22 +136,16 @@ func (tm *TokenManager) refreshAccessToken(ctx context.Context) (string, error)
 	data.Set("client_id", ClientID)
 	data.Set("refresh_token", tm.refreshToken)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, OAuthURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		return "", fmt.Errorf("failed to create token request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("User-Agent", "rackspace-spot-cli/1.0.0")
-
-	resp, err := tm.httpClient.Do(req)
+	resp, err := postForm(ctx, tm.httpClient, tm.tokenURL, data)
 	if err != nil {
+		logger.Debug("OAuth token refresh failed", "url", tm.tokenURL, "latency", time.Since(start), "error", err)
 		return "", fmt.Errorf("token request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("token request failed with status %d", resp.StatusCode)
+		logger.Debug("OAuth token refresh failed", "url", tm.tokenURL, "latency", time.Since(start), "status", resp.StatusCode)
+		return "", fmt.Errorf("token request failed: %w", readOAuthError(resp))
 	}
 
 	var tokenResp TokenResponse
@@ -113,14 +157,199 @@ func (tm *TokenManager) refreshAccessToken(ctx context.Context) (string, error)
 	tm.accessToken = tokenResp.IDToken
 	tm.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
 
-	if tm.debug {
-		fmt.Printf("Access token refreshed, expires at: %s\n", tm.expiresAt.Format(time.RFC3339))
+	// With rotation the old refresh token stops working, so the new one must be kept
+	if tokenResp.RefreshToken != "" && tokenResp.RefreshToken != tm.refreshToken {
+		tm.refreshToken = tokenResp.RefreshToken
+		logger.Info("OAuth refresh token rotated")
+		if tm.rotated != nil {
+			if err := tm.rotated(tokenResp.RefreshToken); err != nil {
+				logger.Warn("Failed to save rotated refresh token; log in again if later commands fail to authenticate", "error", err)
+			}
+		}
 	}
 
+	logger.Debug("OAuth access token refreshed", "latency", time.Since(start), "expiresAt", tm.expiresAt.Format(time.RFC3339))
+
 	return tm.accessToken, nil
 }
 
+// Invalidate discards the cached access token so the next request exchanges
+// the refresh token for a new one
+func (tm *TokenManager) Invalidate() {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm.accessToken = ""
+	tm.expiresAt = time.Time{}
+}
+
+// SetTokenURL overrides the OAuth token endpoint, e.g. to point at a local stand-in server
+func (tm *TokenManager) SetTokenURL(tokenURL string) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm.tokenURL = tokenURL
+}
+
+// OnRefreshTokenRotated sets fn to be called with the new refresh token whenever
+// the authorization server rotates it, e.g. to save it for the next run
+func (tm *TokenManager) OnRefreshTokenRotated(fn func(refreshToken string) error) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm.rotated = fn
+}
+
+// SetLogger sets the logger token refreshes are reported to; nil uses slog.Default()
+func (tm *TokenManager) SetLogger(logger *slog.Logger) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm.logger = logger
+}
+
+// log returns the token manager's logger. The caller holds tm.mutex.
+func (tm *TokenManager) log() *slog.Logger {
+	if tm.logger != nil {
+		return tm.logger
+	}
+	return slog.Default()
+}
+
 // IsValid checks if the refresh token can be used
 func (tm *TokenManager) IsValid() bool {
 	return tm.refreshToken != ""
 }
+
+// DeviceAuthorization is a pending device login (RFC 8628 section 3.2): the user
+// visits VerificationURI and enters UserCode while the CLI polls for a token
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// DeviceFlow performs the OAuth 2.0 device authorization grant, letting a user
+// log in on another device's browser, e.g. from a headless machine
+type DeviceFlow struct {
+	httpClient       *http.Client
+	authorizationURL string
+	tokenURL         string
+	// wait pauses between polls; overridden in tests
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+// NewDeviceFlow creates a device flow against the given authorization and token endpoints
+func NewDeviceFlow(httpClient *http.Client, authorizationURL, tokenURL string) *DeviceFlow {
+	return &DeviceFlow{
+		httpClient:       httpClient,
+		authorizationURL: authorizationURL,
+		tokenURL:         tokenURL,
+		wait:             sleepContext,
+	}
+}
+
+// Start requests a device and user code
+func (f *DeviceFlow) Start(ctx context.Context) (*DeviceAuthorization, error) {
+	form := url.Values{}
+	form.Set("client_id", ClientID)
+	form.Set("scope", DeviceScope)
+
+	resp, err := postForm(ctx, f.httpClient, f.authorizationURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("device authorization request failed: %w", readOAuthError(resp))
+	}
+
+	var auth DeviceAuthorization
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return nil, fmt.Errorf("failed to decode device authorization response: %w", err)
+	}
+	if auth.DeviceCode == "" || auth.UserCode == "" || auth.VerificationURI == "" {
+		return nil, fmt.Errorf("device authorization response is missing device_code, user_code or verification_uri")
+	}
+	return &auth, nil
+}
+
+// Poll waits for the user to approve auth, polling the token endpoint at the
+// interval the server asks for and backing off when told to slow down
+func (f *DeviceFlow) Poll(ctx context.Context, auth *DeviceAuthorization) (*TokenResponse, error) {
+	// RFC 8628 section 3.5: poll every 5 seconds unless told otherwise
+	interval := 5 * time.Second
+	if auth.Interval > 0 {
+		interval = time.Duration(auth.Interval) * time.Second
+	}
+	var deadline time.Time
+	if auth.ExpiresIn > 0 {
+		deadline = time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", DeviceCodeGrantType)
+	form.Set("device_code", auth.DeviceCode)
+	form.Set("client_id", ClientID)
+
+	for {
+		if err := f.wait(ctx, interval); err != nil {
+			return nil, err
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return nil, fmt.Errorf("device code expired before the login was approved")
+		}
+
+		token, err := f.pollOnce(ctx, form)
+		var oauthErr *OAuthError
+		if !errors.As(err, &oauthErr) {
+			return token, err
+		}
+		switch oauthErr.Code {
+		case "authorization_pending":
+		case "slow_down":
+			// Section 3.5: increase the interval by 5 seconds for this and all later requests
+			interval += 5 * time.Second
+		case "access_denied":
+			return nil, fmt.Errorf("login was denied")
+		case "expired_token":
+			return nil, fmt.Errorf("device code expired before the login was approved")
+		default:
+			return nil, fmt.Errorf("device login failed: %w", err)
+		}
+	}
+}
+
+// pollOnce makes a single device token request
+func (f *DeviceFlow) pollOnce(ctx context.Context, form url.Values) (*TokenResponse, error) {
+	resp, err := postForm(ctx, f.httpClient, f.tokenURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, readOAuthError(resp)
+	}
+
+	var token TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("the authorization server did not issue a refresh token")
+	}
+	return &token, nil
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -1,8 +1,18 @@
 package client
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/config"
 )
 
 func TestTokenManager_IsValid(t *testing.T) {
@@ -60,7 +70,143 @@ func TestTokenManager_tokenExpiry(t *testing.T) {
 	}
 }
 
+func TestTokenManager_Invalidate(t *testing.T) {
+	tm := &TokenManager{accessToken: "test-access-token", expiresAt: time.Now().Add(time.Hour)}
+	tm.Invalidate()
+	if tm.hasValidToken() {
+		t.Error("Token should not be valid after Invalidate")
+	}
+}
+
+func TestParseIDToken(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"auth0|123","email":"dev@example.com","exp":1700000000,"org_id":"org_abc"}`))
+	claims, err := ParseIDToken("eyJhbGciOiJSUzI1NiJ9." + payload + ".signature")
+	if err != nil {
+		t.Fatalf("ParseIDToken failed: %v", err)
+	}
+	if claims.Subject != "auth0|123" || claims.Email != "dev@example.com" || claims.OrgID != "org_abc" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+	if !claims.ExpiresAt().Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Unexpected expiry %v", claims.ExpiresAt())
+	}
+
+	for _, token := range []string{"opaque-token", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte("not json")) + ".c"} {
+		if _, err := ParseIDToken(token); err == nil {
+			t.Errorf("Expected an error parsing %q", token)
+		}
+	}
+}
+
 // Helper method for testing token validity
 func (tm *TokenManager) hasValidToken() bool {
 	return tm.accessToken != "" && time.Now().Add(5*time.Minute).Before(tm.expiresAt)
 }
+
+func TestTokenManager_RotationAndErrors(t *testing.T) {
+	rotations := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("refresh_token") != fmt.Sprintf("refresh-%d", rotations) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Unknown or invalid refresh token."}`)
+			return
+		}
+		rotations++
+		fmt.Fprintf(w, `{"id_token":"id-token","refresh_token":"refresh-%d","expires_in":3600}`, rotations)
+	}))
+	defer server.Close()
+
+	tm := NewTokenManager("refresh-1", server.Client())
+	tm.SetTokenURL(server.URL)
+	var saved []string
+	tm.OnRefreshTokenRotated(func(refreshToken string) error {
+		saved = append(saved, refreshToken)
+		return nil
+	})
+
+	// The second exchange only succeeds if it uses the rotated token
+	for range 2 {
+		tm.Invalidate()
+		if _, err := tm.GetValidAccessToken(context.Background()); err != nil {
+			t.Fatalf("Token exchange failed: %v", err)
+		}
+	}
+	if len(saved) != 2 || saved[0] != "refresh-2" || saved[1] != "refresh-3" {
+		t.Errorf("Expected the rotated tokens to be saved, got %v", saved)
+	}
+
+	stale := NewTokenManager("refresh-1", server.Client())
+	stale.SetTokenURL(server.URL)
+	_, err := stale.GetValidAccessToken(context.Background())
+	var oauthErr *OAuthError
+	if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_grant" || oauthErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("Expected an invalid_grant OAuth error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Unknown or invalid refresh token.") {
+		t.Errorf("Expected the error description in %q", err.Error())
+	}
+}
+
+// rotatingTokens issues a new token on every call
+type rotatingTokens struct {
+	issued      int
+	invalidated int
+}
+
+func (r *rotatingTokens) GetValidAccessToken(ctx context.Context) (string, error) {
+	r.issued++
+	return fmt.Sprintf("token-%d", r.issued), nil
+}
+
+func (r *rotatingTokens) Invalidate() {
+	r.invalidated++
+}
+
+func TestClient_RetryOnUnauthorized(t *testing.T) {
+	tests := []struct {
+		name        string
+		validToken  string
+		wantErr     bool
+		wantCalls   int
+		wantInvalid int
+	}{
+		{name: "succeeds with a refreshed token", validToken: "token-2", wantCalls: 2, wantInvalid: 1},
+		{name: "retries only once", validToken: "token-3", wantErr: true, wantCalls: 2, wantInvalid: 1},
+		{name: "no retry when the token is accepted", validToken: "token-1", wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				if r.Header.Get("Authorization") != "Bearer "+tt.validToken {
+					w.WriteHeader(http.StatusUnauthorized)
+					fmt.Fprint(w, `{"code":401,"message":"Unauthorized"}`)
+					return
+				}
+				fmt.Fprint(w, `{}`)
+			}))
+			defer server.Close()
+
+			tokens := &rotatingTokens{}
+			c := NewClient(&config.Config{BaseURL: server.URL, Timeout: 30}, WithTokenSource(tokens))
+			_, err := c.Post(context.Background(), "/things", map[string]string{"name": "a"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Post() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls || tokens.invalidated != tt.wantInvalid {
+				t.Errorf("Expected %d calls and %d invalidations, got %d and %d", tt.wantCalls, tt.wantInvalid, calls, tokens.invalidated)
+			}
+			for _, body := range bodies {
+				if body != `{"name":"a"}` {
+					t.Errorf("Expected the body to be resent, got %q", body)
+				}
+			}
+		})
+	}
+}
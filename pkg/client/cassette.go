@@ -0,0 +1,36 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/georgetaylor/spotctl/pkg/cassette"
+)
+
+// errorTransport fails every request, reporting why the client could not be set up
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+// withCassette returns a copy of httpClient that records interactions to
+// recordDir or replays them from replayDir
+func withCassette(httpClient *http.Client, recordDir, replayDir string) *http.Client {
+	wrapped := *httpClient
+
+	var transport http.RoundTripper
+	var err error
+	if replayDir != "" {
+		transport, err = cassette.NewReplayer(replayDir)
+	} else {
+		transport, err = cassette.NewRecorder(recordDir, httpClient.Transport)
+	}
+	if err != nil {
+		transport = errorTransport{err: err}
+	}
+
+	wrapped.Transport = transport
+	return &wrapped
+}
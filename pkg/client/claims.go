@@ -0,0 +1,49 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims are the identity claims carried by an OAuth ID token
+type Claims struct {
+	Subject  string `json:"sub"`
+	Email    string `json:"email,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Issuer   string `json:"iss,omitempty"`
+	IssuedAt int64  `json:"iat,omitempty"`
+	Expiry   int64  `json:"exp,omitempty"`
+	// OrgID and OrgName identify the organization the token was issued for, if any
+	OrgID   string `json:"org_id,omitempty"`
+	OrgName string `json:"org_name,omitempty"`
+}
+
+// ExpiresAt returns when the token expires, or the zero time if it does not say
+func (c *Claims) ExpiresAt() time.Time {
+	if c.Expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(c.Expiry, 0)
+}
+
+// ParseIDToken decodes the claims of a JWT ID token. The signature is not
+// verified: the claims are only used to describe the caller, never to make
+// authorization decisions, and the API verifies the token itself.
+func ParseIDToken(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("ID token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ID token claims: %w", err)
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse ID token claims: %w", err)
+	}
+	return &claims, nil
+}
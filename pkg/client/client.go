@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/georgetaylor/spotctl/pkg/config"
@@ -46,6 +51,29 @@ func (v APIVersion) IsValid() bool {
 	}
 }
 
+// Group returns the API group of the version, e.g. ngpc.rxt.io
+func (v APIVersion) Group() string {
+	group, _, _ := strings.Cut(string(v), "/")
+	return group
+}
+
+// versionPattern matches Kubernetes style versions such as v1, v2beta1 or v1alpha3
+var versionPattern = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]+)?$`)
+
+// ParseAPIVersion parses an API version given as group/version, e.g.
+// ngpc.rxt.io/v2beta1, or as a bare version of the default group, e.g. v2beta1.
+// Versions spotctl does not know are accepted so preview versions can be used.
+func ParseAPIVersion(s string) (APIVersion, error) {
+	group, version, found := strings.Cut(strings.TrimSpace(s), "/")
+	if !found {
+		group, version = APIVersionDefault.Group(), group
+	}
+	if group == "" || !versionPattern.MatchString(version) {
+		return "", fmt.Errorf("invalid API version %q: expected group/version such as %s, or a version such as v1beta1", s, APIVersionDefault)
+	}
+	return APIVersion(group + "/" + version), nil
+}
+
 // GetAllAPIVersions returns a slice of all known API versions
 func GetAllAPIVersions() []APIVersion {
 	return []APIVersion{
@@ -59,6 +87,13 @@ type Client struct {
 	httpClient   *http.Client
 	config       *config.Config
 	tokenManager TokenManagerInterface
+	baseURL      string
+	userAgent    string
+	headers      http.Header
+	apiVersion   string
+	retry        RetryPolicy
+	logger       *slog.Logger
+	dryRun       bool
 }
 
 // APIError represents an error response from the API
@@ -75,19 +110,145 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error %d: %s", e.Code, e.Message)
 }
 
-// NewClient creates a new Rackspace Spot API client
-func NewClient(cfg *config.Config) *Client {
-	httpClient := &http.Client{
-		Timeout: time.Duration(cfg.Timeout) * time.Second,
+// IsNotFound reports whether err is an API error for an object that does not exist
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return stderrors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// NewClient creates a new Rackspace Spot API client from cfg, adjusted by opts
+func NewClient(cfg *config.Config, opts ...Option) *Client {
+	// A transport that cannot be built fails every request with the reason
+	var transport http.RoundTripper
+	if configured, err := NewTransport(cfg); err != nil {
+		transport = errorTransport{err: err}
+	} else {
+		transport = configured
+	}
+
+	c := &Client{
+		httpClient: &http.Client{
+			Timeout:   requestTimeout(cfg),
+			Transport: transport,
+		},
+		config:     cfg,
+		apiVersion: cfg.APIVersion,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if cfg.Record != "" || cfg.Replay != "" {
+		c.httpClient = withCassette(c.httpClient, cfg.Record, cfg.Replay)
+	}
+	if cfg.HTTPTrace {
+		c.httpClient = withTrace(c.httpClient, c.log)
+	}
+
+	// The default token manager shares the HTTP client so it sees any custom transport
+	if c.tokenManager == nil {
+		tokenManager := NewTokenManager(cfg.RefreshToken, c.httpClient)
+		if cfg.OAuthURL != "" {
+			tokenManager.SetTokenURL(cfg.OAuthURL)
+		}
+		tokenManager.SetLogger(c.logger)
+		// Replayed cassettes carry redacted refresh tokens, which must never be saved
+		if cfg.StoreRefreshToken != nil && cfg.Replay == "" {
+			tokenManager.OnRefreshTokenRotated(cfg.StoreRefreshToken)
+		}
+		c.tokenManager = tokenManager
+	}
+
+	return c
+}
+
+// AccessToken returns a valid bearer token for the configured refresh token,
+// refreshing it if necessary
+func (c *Client) AccessToken(ctx context.Context) (string, error) {
+	return c.tokenManager.GetValidAccessToken(ctx)
+}
+
+// DeviceFlow returns a device authorization flow against the configured
+// authorization server, sharing the client's transport
+func (c *Client) DeviceFlow() *DeviceFlow {
+	tokenURL := c.config.OAuthURL
+	if tokenURL == "" {
+		tokenURL = OAuthURL
 	}
+	return NewDeviceFlow(c.httpClient, deviceAuthURL(c.config.DeviceAuthURL, tokenURL), tokenURL)
+}
+
+// ResolveAPIVersion returns the version requests for version are sent with: the
+// api-version override when it is for the same API group, otherwise version itself
+func (c *Client) ResolveAPIVersion(version APIVersion) (APIVersion, error) {
+	if c.apiVersion == "" || version == "" {
+		return version, nil
+	}
+	override, err := ParseAPIVersion(c.apiVersion)
+	if err != nil {
+		return "", err
+	}
+	if override.Group() != version.Group() {
+		return version, nil
+	}
+	return override, nil
+}
+
+// requestTimeout returns the http.Client timeout for cfg: request-timeout if set,
+// otherwise timeout seconds
+func requestTimeout(cfg *config.Config) time.Duration {
+	if cfg.RequestTimeout > 0 {
+		return cfg.RequestTimeout
+	}
+	return time.Duration(cfg.Timeout) * time.Second
+}
+
+// deviceAuthURL returns the configured device authorization endpoint, or the one
+// next to the token endpoint so a custom oauth-url needs no separate setting
+func deviceAuthURL(configured, tokenURL string) string {
+	if configured != "" {
+		return configured
+	}
+	if base, ok := strings.CutSuffix(tokenURL, "/oauth/token"); ok {
+		return base + "/oauth/device/code"
+	}
+	return DeviceAuthURL
+}
+
+// WithDryRun returns a copy of the client whose create, edit and delete requests are
+// validated by the API server without being persisted (dryRun=All)
+func (c *Client) WithDryRun() *Client {
+	dryRunClient := *c
+	dryRunClient.dryRun = true
+	return &dryRunClient
+}
+
+// DryRun reports whether mutating requests made by the client are server-side dry runs
+func (c *Client) DryRun() bool {
+	return c.dryRun
+}
 
-	tokenManager := NewTokenManager(cfg.RefreshToken, httpClient, cfg.Debug)
+// apiBaseURL returns the base URL set with WithBaseURL, falling back to the configured one
+func (c *Client) apiBaseURL() string {
+	if c.baseURL != "" {
+		return c.baseURL
+	}
+	return c.config.BaseURL
+}
+
+// log returns the logger set with WithLogger, falling back to the default logger
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return slog.Default()
+}
 
-	return &Client{
-		httpClient:   httpClient,
-		config:       cfg,
-		tokenManager: tokenManager,
+// userAgentHeader returns the User-Agent set with WithUserAgent, falling back to spotctl's own
+func (c *Client) userAgentHeader() string {
+	if c.userAgent != "" {
+		return c.userAgent
 	}
+	return version.GetUserAgent()
 }
 
 // requestOptions contains options for making HTTP requests
@@ -97,12 +258,21 @@ type requestOptions struct {
 	body        interface{}
 	apiVersion  APIVersion
 	contentType string
+	dryRun      bool
 }
 
 // prepareRequest prepares an HTTP request with the given options
 func (c *Client) prepareRequest(ctx context.Context, opts requestOptions) (*http.Request, error) {
+	apiVersion, err := c.ResolveAPIVersion(opts.apiVersion)
+	if err != nil {
+		return nil, errors.NewValidationError(err.Error(), nil)
+	}
+
 	// Construct the full URL by combining base URL, API version, and endpoint
-	url := fmt.Sprintf("%s/%s%s", c.config.BaseURL, opts.apiVersion.String(), opts.endpoint)
+	url := fmt.Sprintf("%s/%s%s", c.apiBaseURL(), apiVersion.String(), opts.endpoint)
+	if opts.dryRun {
+		url = withQuery(url, "dryRun=All")
+	}
 
 	var reqBody io.Reader
 	if opts.body != nil {
@@ -130,18 +300,23 @@ func (c *Client) prepareRequest(ctx context.Context, opts requestOptions) (*http
 
 	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
-	req.Header.Set("User-Agent", version.GetUserAgent())
-
-	if c.config.Debug {
-		fmt.Printf("Making %s request to %s (API: %s)\n", opts.method, url, opts.apiVersion)
+	req.Header.Set("User-Agent", c.userAgentHeader())
+	for key, values := range c.headers {
+		req.Header[key] = values
 	}
 
+	c.log().Debug("Making API request", "method", opts.method, "url", url, "apiVersion", apiVersion)
+
 	return req, nil
 }
 
-// doRequest executes an HTTP request and handles the response
+// doRequest executes an HTTP request, retrying it according to the retry policy,
+// and handles the response
 func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doWithRetry(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		resp, err = c.retryUnauthorized(req, resp)
+	}
 	if err != nil {
 		return nil, errors.NewAPIError(0, fmt.Sprintf("request failed for %s %s", req.Method, req.URL.String()), err)
 	}
@@ -179,6 +354,32 @@ func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
 	}
 }
 
+// retryUnauthorized resends a request rejected with 401 once with a fresh token,
+// since the cached one may have been revoked or expired early. It returns resp
+// unchanged if the token source cannot be refreshed or the body cannot be resent.
+func (c *Client) retryUnauthorized(req *http.Request, resp *http.Response) (*http.Response, error) {
+	tokenManager, ok := c.tokenManager.(interface{ Invalidate() })
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	tokenManager.Invalidate()
+	accessToken, err := c.tokenManager.GetValidAccessToken(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh access token after 401: %w", err)
+	}
+	if req.GetBody != nil {
+		if req.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	c.log().Debug("Retrying request with a refreshed token after 401", "method", req.Method, "url", req.URL.String())
+	return c.doWithRetry(req)
+}
+
 // MakeRequest performs an HTTP request to the API
 func (c *Client) MakeRequest(ctx context.Context, method, endpoint string, body interface{}, apiVersion APIVersion, contentType ...string) (*http.Response, error) {
 	opts := requestOptions{
@@ -187,6 +388,7 @@ func (c *Client) MakeRequest(ctx context.Context, method, endpoint string, body
 		body:        body,
 		apiVersion:  apiVersion,
 		contentType: "",
+		dryRun:      c.dryRun && method != http.MethodGet,
 	}
 
 	if len(contentType) > 0 {
@@ -244,285 +446,121 @@ func (c *Client) Patch(ctx context.Context, endpoint string, body interface{}, a
 
 // ListRegions retrieves a list of available regions
 func (c *Client) ListRegions(ctx context.Context, apiVersion ...APIVersion) (*RegionList, error) {
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	return genericList[RegionList](c, ctx, "/regions", ListOptions{APIVersion: version})
+	return c.Regions(apiVersion...).List(ctx, "")
 }
 
 // GetRegion retrieves a specific region by name
 func (c *Client) GetRegion(ctx context.Context, name string, apiVersion ...APIVersion) (*Region, error) {
-	if err := validateName(name); err != nil {
-		return nil, fmt.Errorf("region name cannot be empty")
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	return genericGet[Region](c, ctx, fmt.Sprintf("/regions/%s", name), GetOptions{Name: name, APIVersion: version})
+	return c.Regions(apiVersion...).Get(ctx, "", name)
 }
 
 // ListServerClasses retrieves all available server classes
 func (c *Client) ListServerClasses(ctx context.Context, apiVersion ...APIVersion) (*ServerClassList, error) {
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	return genericList[ServerClassList](c, ctx, "/serverclasses", ListOptions{APIVersion: version})
+	return c.ServerClasses(apiVersion...).List(ctx, "")
 }
 
 // GetServerClass retrieves a specific server class by name
 func (c *Client) GetServerClass(ctx context.Context, name string, apiVersion ...APIVersion) (*ServerClass, error) {
-	if err := validateName(name); err != nil {
-		return nil, fmt.Errorf("server class name is required")
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	return genericGet[ServerClass](c, ctx, fmt.Sprintf("/serverclasses/%s", name), GetOptions{Name: name, APIVersion: version})
+	return c.ServerClasses(apiVersion...).Get(ctx, "", name)
 }
 
 // ListOrganizations retrieves all organizations for the authenticated user
 func (c *Client) ListOrganizations(ctx context.Context, apiVersion ...APIVersion) (*OrganizationList, error) {
 	// Organizations API defaults to auth version but can be overridden
-	version := APIVersionAuth
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	return genericList[OrganizationList](c, ctx, "/organizations", ListOptions{APIVersion: version})
+	return c.Organizations(apiVersion...).List(ctx, "")
 }
 
 // ListCloudSpaces retrieves all cloudspaces for a given namespace
 func (c *Client) ListCloudSpaces(ctx context.Context, namespace string, apiVersion ...APIVersion) (*CloudSpaceList, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/cloudspaces", namespace)
-	return genericList[CloudSpaceList](c, ctx, endpoint, ListOptions{Namespace: namespace, APIVersion: version})
+	return c.CloudSpaces(apiVersion...).List(ctx, namespace)
 }
 
 // CreateCloudSpace creates a new cloudspace in the specified namespace
 func (c *Client) CreateCloudSpace(ctx context.Context, namespace string, cloudSpace *CloudSpace, apiVersion ...APIVersion) (*CloudSpace, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-	if err := validateCreateInput(cloudSpace); err != nil {
-		return nil, fmt.Errorf("cloudspace configuration is required")
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/cloudspaces", namespace)
-	return genericCreate[CloudSpace](c, ctx, endpoint, cloudSpace, CreateOptions{Namespace: namespace, APIVersion: version})
+	return c.CloudSpaces(apiVersion...).Create(ctx, namespace, cloudSpace)
 }
 
 // DeleteCloudSpace deletes a cloudspace by name in the specified namespace
 func (c *Client) DeleteCloudSpace(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*DeleteResponse, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-	if err := validateName(name); err != nil {
-		return nil, fmt.Errorf("cloudspace name is required")
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/cloudspaces/%s", namespace, name)
-	return genericDelete[DeleteResponse](c, ctx, endpoint, DeleteOptions{
-		Namespace:    namespace,
-		Name:         name,
-		ResourceType: "CloudSpace",
-		APIVersion:   version,
-	})
+	return c.CloudSpaces(apiVersion...).Delete(ctx, namespace, name)
 }
 
 // GetCloudSpace retrieves a specific cloudspace by name in the specified namespace
 func (c *Client) GetCloudSpace(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*CloudSpace, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-	if err := validateName(name); err != nil {
-		return nil, fmt.Errorf("cloudspace name is required")
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/cloudspaces/%s", namespace, name)
-	return genericGet[CloudSpace](c, ctx, endpoint, GetOptions{Namespace: namespace, Name: name, APIVersion: version})
+	return c.CloudSpaces(apiVersion...).Get(ctx, namespace, name)
 }
 
 // EditCloudSpace edits a cloudspace using JSON patch operations
 func (c *Client) EditCloudSpace(ctx context.Context, namespace, name string, patchOps []PatchOperation, apiVersion ...APIVersion) (*CloudSpace, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-	if err := validateName(name); err != nil {
-		return nil, fmt.Errorf("cloudspace name is required")
-	}
-	if err := validatePatchOperations(patchOps); err != nil {
-		return nil, err
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/cloudspaces/%s", namespace, name)
-	return genericEdit[CloudSpace](c, ctx, endpoint, patchOps, EditOptions{Namespace: namespace, Name: name, APIVersion: version})
+	return c.CloudSpaces(apiVersion...).Edit(ctx, namespace, name, patchOps)
 }
 
 // ListSpotNodePools retrieves all spot node pools for a given namespace
 func (c *Client) ListSpotNodePools(ctx context.Context, namespace string, apiVersion ...APIVersion) (*SpotNodePoolList, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/spotnodepools", namespace)
-	return genericList[SpotNodePoolList](c, ctx, endpoint, ListOptions{Namespace: namespace, APIVersion: version})
+	return c.SpotNodePools(apiVersion...).List(ctx, namespace)
 }
 
 // CreateSpotNodePool creates a new spot node pool in the specified namespace
 func (c *Client) CreateSpotNodePool(ctx context.Context, namespace string, spotNodePool *SpotNodePool, apiVersion ...APIVersion) (*SpotNodePool, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-	if err := validateCreateInput(spotNodePool); err != nil {
-		return nil, fmt.Errorf("spot node pool configuration is required")
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/spotnodepools", namespace)
-	return genericCreate[SpotNodePool](c, ctx, endpoint, spotNodePool, CreateOptions{Namespace: namespace, APIVersion: version})
+	return c.SpotNodePools(apiVersion...).Create(ctx, namespace, spotNodePool)
 }
 
 // EditSpotNodePool edits a spot node pool using JSON patch operations
 func (c *Client) EditSpotNodePool(ctx context.Context, namespace, name string, patchOps []PatchOperation, apiVersion ...APIVersion) (*SpotNodePool, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-	if err := validateName(name); err != nil {
-		return nil, fmt.Errorf("spot node pool name is required")
-	}
-	if err := validatePatchOperations(patchOps); err != nil {
-		return nil, err
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/spotnodepools/%s", namespace, name)
-	return genericEdit[SpotNodePool](c, ctx, endpoint, patchOps, EditOptions{Namespace: namespace, Name: name, APIVersion: version})
+	return c.SpotNodePools(apiVersion...).Edit(ctx, namespace, name, patchOps)
 }
 
 // GetSpotNodePool retrieves a specific spot node pool by name in the specified namespace
 func (c *Client) GetSpotNodePool(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*SpotNodePool, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-	if err := validateName(name); err != nil {
-		return nil, fmt.Errorf("spot node pool name is required")
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/spotnodepools/%s", namespace, name)
-	return genericGet[SpotNodePool](c, ctx, endpoint, GetOptions{Namespace: namespace, Name: name, APIVersion: version})
+	return c.SpotNodePools(apiVersion...).Get(ctx, namespace, name)
 }
 
 // DeleteSpotNodePool deletes a spot node pool by name in the specified namespace
 func (c *Client) DeleteSpotNodePool(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*DeleteResponse, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-	if err := validateName(name); err != nil {
-		return nil, fmt.Errorf("spot node pool name is required")
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/spotnodepools/%s", namespace, name)
-	return genericDelete[DeleteResponse](c, ctx, endpoint, DeleteOptions{
-		Namespace:    namespace,
-		Name:         name,
-		ResourceType: "SpotNodePool",
-		APIVersion:   version,
-	})
+	return c.SpotNodePools(apiVersion...).Delete(ctx, namespace, name)
 }
 
 // DeleteAllSpotNodePools deletes all spot node pools in the specified namespace
 func (c *Client) DeleteAllSpotNodePools(ctx context.Context, namespace string, apiVersion ...APIVersion) (*DeleteResponse, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-
-	version := APIVersionDefault
-	if len(apiVersion) > 0 {
-		version = apiVersion[0]
-	}
-	endpoint := fmt.Sprintf("/namespaces/%s/spotnodepools", namespace)
-	return genericDelete[DeleteResponse](c, ctx, endpoint, DeleteOptions{
-		Namespace:    namespace,
-		ResourceType: "SpotNodePools",
-		APIVersion:   version,
-	})
+	return c.SpotNodePools(apiVersion...).DeleteCollection(ctx, namespace)
 }
 
 // GetOnDemandNodePool retrieves a specific on demand node pool by name in the specified namespace
 func (c *Client) GetOnDemandNodePool(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*OnDemandNodePool, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
-	}
-	if err := validateName(name); err != nil {
-		return nil, fmt.Errorf("on demand node pool name is required")
+	return c.OnDemandNodePools(apiVersion...).Get(ctx, namespace, name)
+}
+
+// ListOnDemandNodePools retrieves all on demand node pools for a given namespace
+func (c *Client) ListOnDemandNodePools(ctx context.Context, namespace string, apiVersion ...APIVersion) (*OnDemandNodePoolList, error) {
+	return c.OnDemandNodePools(apiVersion...).List(ctx, namespace)
+}
+
+// GetPriceHistory retrieves the auction price history of a server class
+func (c *Client) GetPriceHistory(ctx context.Context, serverClass string, apiVersion ...APIVersion) (*PriceHistory, error) {
+	if err := validateName(serverClass); err != nil {
+		return nil, fmt.Errorf("server class name is required")
 	}
 
 	version := APIVersionDefault
 	if len(apiVersion) > 0 {
 		version = apiVersion[0]
 	}
-	endpoint := fmt.Sprintf("/namespaces/%s/ondemandnodepools/%s", namespace, name)
-	return genericGet[OnDemandNodePool](c, ctx, endpoint, GetOptions{Namespace: namespace, Name: name, APIVersion: version})
+	endpoint := fmt.Sprintf("/price-history?serverClass=%s", url.QueryEscape(serverClass))
+	return genericGet[PriceHistory](c, ctx, endpoint, GetOptions{Name: serverClass, APIVersion: version})
 }
 
-// ListOnDemandNodePools retrieves all on demand node pools for a given namespace
-func (c *Client) ListOnDemandNodePools(ctx context.Context, namespace string, apiVersion ...APIVersion) (*OnDemandNodePoolList, error) {
-	if err := validateNamespace(namespace); err != nil {
-		return nil, err
+// GetPercentileInfo retrieves the hammer price percentiles of a server class
+func (c *Client) GetPercentileInfo(ctx context.Context, serverClass string, apiVersion ...APIVersion) (*PercentileInfo, error) {
+	if err := validateName(serverClass); err != nil {
+		return nil, fmt.Errorf("server class name is required")
 	}
 
 	version := APIVersionDefault
 	if len(apiVersion) > 0 {
 		version = apiVersion[0]
 	}
-	endpoint := fmt.Sprintf("/namespaces/%s/ondemandnodepools", namespace)
-	return genericList[OnDemandNodePoolList](c, ctx, endpoint, ListOptions{Namespace: namespace, APIVersion: version})
+	endpoint := fmt.Sprintf("/percentile-info?serverClass=%s", url.QueryEscape(serverClass))
+	return genericGet[PercentileInfo](c, ctx, endpoint, GetOptions{Name: serverClass, APIVersion: version})
 }
 
 // HandleAPIError processes API error responses and returns appropriate error types
@@ -0,0 +1,458 @@
+// Package clientfake provides an in-memory implementation of client.Interface for
+// unit tests of programs that use the Rackspace Spot API.
+//
+//	fake := clientfake.NewClient(
+//		&client.CloudSpace{Metadata: client.ObjectMeta{Name: "dev", Namespace: "org-abc123"}},
+//	)
+//	err := myTool(ctx, fake) // myTool accepts a client.Interface
+package clientfake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// Action records a call made to the fake client
+type Action struct {
+	// Verb is one of the client.Verb constants
+	Verb string
+	// Resource is the plural resource name, e.g. cloudspaces
+	Resource  string
+	Namespace string
+	Name      string
+}
+
+// Client is an in-memory client.Interface. Objects are copied on the way in and
+// out, so callers never share state with the fake. It is safe for concurrent use.
+type Client struct {
+	mu sync.Mutex
+
+	regions           objects[client.Region]
+	serverClasses     objects[client.ServerClass]
+	organizations     objects[client.Organization]
+	cloudSpaces       objects[client.CloudSpace]
+	spotNodePools     objects[client.SpotNodePool]
+	onDemandNodePools objects[client.OnDemandNodePool]
+	priceHistory      map[string]client.PriceHistory
+	percentileInfo    map[string]client.PercentileInfo
+
+	actions []Action
+	errors  map[Action]error
+}
+
+var _ client.Interface = (*Client)(nil)
+
+// NewClient returns a fake client seeded with objects. Each object must be one of the
+// client resource types (Region, ServerClass, Organization, CloudSpace, SpotNodePool,
+// OnDemandNodePool, PriceHistory or PercentileInfo), by value or pointer; namespaced
+// objects are stored in their Metadata.Namespace.
+func NewClient(objs ...any) *Client {
+	f := &Client{
+		regions:           objects[client.Region]{},
+		serverClasses:     objects[client.ServerClass]{},
+		organizations:     objects[client.Organization]{},
+		cloudSpaces:       objects[client.CloudSpace]{},
+		spotNodePools:     objects[client.SpotNodePool]{},
+		onDemandNodePools: objects[client.OnDemandNodePool]{},
+		priceHistory:      map[string]client.PriceHistory{},
+		percentileInfo:    map[string]client.PercentileInfo{},
+		errors:            map[Action]error{},
+	}
+	for _, obj := range objs {
+		if err := f.Add(obj); err != nil {
+			panic(err)
+		}
+	}
+	return f
+}
+
+// Add stores an object, replacing any existing object with the same name
+func (f *Client) Add(obj any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch o := obj.(type) {
+	case *client.Region:
+		f.regions.put("", o.Metadata.Name, *o)
+	case client.Region:
+		f.regions.put("", o.Metadata.Name, o)
+	case *client.ServerClass:
+		f.serverClasses.put("", o.Metadata.Name, *o)
+	case client.ServerClass:
+		f.serverClasses.put("", o.Metadata.Name, o)
+	case *client.Organization:
+		f.organizations.put("", o.Name, *o)
+	case client.Organization:
+		f.organizations.put("", o.Name, o)
+	case *client.CloudSpace:
+		f.cloudSpaces.put(o.Metadata.Namespace, o.Metadata.Name, *o)
+	case client.CloudSpace:
+		f.cloudSpaces.put(o.Metadata.Namespace, o.Metadata.Name, o)
+	case *client.SpotNodePool:
+		f.spotNodePools.put(o.Metadata.Namespace, o.Metadata.Name, *o)
+	case client.SpotNodePool:
+		f.spotNodePools.put(o.Metadata.Namespace, o.Metadata.Name, o)
+	case *client.OnDemandNodePool:
+		f.onDemandNodePools.put(o.Metadata.Namespace, o.Metadata.Name, *o)
+	case client.OnDemandNodePool:
+		f.onDemandNodePools.put(o.Metadata.Namespace, o.Metadata.Name, o)
+	case *client.PriceHistory:
+		f.priceHistory[o.ServerClass] = deepCopy(*o)
+	case client.PriceHistory:
+		f.priceHistory[o.ServerClass] = deepCopy(o)
+	case *client.PercentileInfo:
+		f.percentileInfo[o.ServerClass] = deepCopy(*o)
+	case client.PercentileInfo:
+		f.percentileInfo[o.ServerClass] = deepCopy(o)
+	default:
+		return fmt.Errorf("clientfake: unsupported object type %T", obj)
+	}
+	return nil
+}
+
+// FailWith makes calls matching verb, resource, namespace and name return err.
+// Empty namespace and name match only calls without them.
+func (f *Client) FailWith(verb, resource, namespace, name string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors[Action{Verb: verb, Resource: resource, Namespace: namespace, Name: name}] = err
+}
+
+// Actions returns the calls made so far, in order
+func (f *Client) Actions() []Action {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Action(nil), f.actions...)
+}
+
+// ClearActions forgets the recorded calls
+func (f *Client) ClearActions() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.actions = nil
+}
+
+// record notes a call and returns the error injected for it, if any. The caller holds f.mu.
+func (f *Client) record(verb string, resource client.Resource, namespace, name string) error {
+	action := Action{Verb: verb, Resource: resource.Plural, Namespace: namespace, Name: name}
+	f.actions = append(f.actions, action)
+	if err, ok := f.errors[action]; ok {
+		return err
+	}
+	if resource.Namespaced && namespace == "" {
+		return fmt.Errorf("namespace is required")
+	}
+	if (verb == client.VerbGet || verb == client.VerbPatch || verb == client.VerbDelete) && name == "" {
+		return fmt.Errorf("%s name is required", resource.DisplayName)
+	}
+	return nil
+}
+
+func (f *Client) ListRegions(ctx context.Context, apiVersion ...client.APIVersion) (*client.RegionList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbList, client.RegionResource, "", ""); err != nil {
+		return nil, err
+	}
+	return &client.RegionList{Items: f.regions.list("")}, nil
+}
+
+func (f *Client) GetRegion(ctx context.Context, name string, apiVersion ...client.APIVersion) (*client.Region, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbGet, client.RegionResource, "", name); err != nil {
+		return nil, err
+	}
+	return f.regions.get(client.RegionResource, "", name)
+}
+
+func (f *Client) ListServerClasses(ctx context.Context, apiVersion ...client.APIVersion) (*client.ServerClassList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbList, client.ServerClassResource, "", ""); err != nil {
+		return nil, err
+	}
+	return &client.ServerClassList{Items: f.serverClasses.list("")}, nil
+}
+
+func (f *Client) GetServerClass(ctx context.Context, name string, apiVersion ...client.APIVersion) (*client.ServerClass, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbGet, client.ServerClassResource, "", name); err != nil {
+		return nil, err
+	}
+	return f.serverClasses.get(client.ServerClassResource, "", name)
+}
+
+func (f *Client) ListOrganizations(ctx context.Context, apiVersion ...client.APIVersion) (*client.OrganizationList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbList, client.OrganizationResource, "", ""); err != nil {
+		return nil, err
+	}
+	orgs := f.organizations.list("")
+	return &client.OrganizationList{Length: len(orgs), Total: len(orgs), Organizations: orgs}, nil
+}
+
+func (f *Client) ListCloudSpaces(ctx context.Context, namespace string, apiVersion ...client.APIVersion) (*client.CloudSpaceList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbList, client.CloudSpaceResource, namespace, ""); err != nil {
+		return nil, err
+	}
+	return &client.CloudSpaceList{Items: f.cloudSpaces.list(namespace)}, nil
+}
+
+func (f *Client) GetCloudSpace(ctx context.Context, namespace, name string, apiVersion ...client.APIVersion) (*client.CloudSpace, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbGet, client.CloudSpaceResource, namespace, name); err != nil {
+		return nil, err
+	}
+	return f.cloudSpaces.get(client.CloudSpaceResource, namespace, name)
+}
+
+func (f *Client) CreateCloudSpace(ctx context.Context, namespace string, cloudSpace *client.CloudSpace, apiVersion ...client.APIVersion) (*client.CloudSpace, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbCreate, client.CloudSpaceResource, namespace, objectName(cloudSpace, func(o *client.CloudSpace) string { return o.Metadata.Name })); err != nil {
+		return nil, err
+	}
+	if cloudSpace == nil {
+		return nil, fmt.Errorf("cloudspace configuration is required")
+	}
+	created := deepCopy(*cloudSpace)
+	created.Metadata.Namespace = namespace
+	return f.cloudSpaces.create(client.CloudSpaceResource, namespace, created.Metadata.Name, created)
+}
+
+func (f *Client) EditCloudSpace(ctx context.Context, namespace, name string, patchOps []client.PatchOperation, apiVersion ...client.APIVersion) (*client.CloudSpace, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbPatch, client.CloudSpaceResource, namespace, name); err != nil {
+		return nil, err
+	}
+	return f.cloudSpaces.patch(client.CloudSpaceResource, namespace, name, patchOps)
+}
+
+func (f *Client) DeleteCloudSpace(ctx context.Context, namespace, name string, apiVersion ...client.APIVersion) (*client.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbDelete, client.CloudSpaceResource, namespace, name); err != nil {
+		return nil, err
+	}
+	return f.cloudSpaces.delete(client.CloudSpaceResource, namespace, name)
+}
+
+func (f *Client) ListSpotNodePools(ctx context.Context, namespace string, apiVersion ...client.APIVersion) (*client.SpotNodePoolList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbList, client.SpotNodePoolResource, namespace, ""); err != nil {
+		return nil, err
+	}
+	return &client.SpotNodePoolList{Items: f.spotNodePools.list(namespace)}, nil
+}
+
+func (f *Client) GetSpotNodePool(ctx context.Context, namespace, name string, apiVersion ...client.APIVersion) (*client.SpotNodePool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbGet, client.SpotNodePoolResource, namespace, name); err != nil {
+		return nil, err
+	}
+	return f.spotNodePools.get(client.SpotNodePoolResource, namespace, name)
+}
+
+func (f *Client) CreateSpotNodePool(ctx context.Context, namespace string, spotNodePool *client.SpotNodePool, apiVersion ...client.APIVersion) (*client.SpotNodePool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbCreate, client.SpotNodePoolResource, namespace, objectName(spotNodePool, func(o *client.SpotNodePool) string { return o.Metadata.Name })); err != nil {
+		return nil, err
+	}
+	if spotNodePool == nil {
+		return nil, fmt.Errorf("spot node pool configuration is required")
+	}
+	created := deepCopy(*spotNodePool)
+	created.Metadata.Namespace = namespace
+	return f.spotNodePools.create(client.SpotNodePoolResource, namespace, created.Metadata.Name, created)
+}
+
+func (f *Client) EditSpotNodePool(ctx context.Context, namespace, name string, patchOps []client.PatchOperation, apiVersion ...client.APIVersion) (*client.SpotNodePool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbPatch, client.SpotNodePoolResource, namespace, name); err != nil {
+		return nil, err
+	}
+	return f.spotNodePools.patch(client.SpotNodePoolResource, namespace, name, patchOps)
+}
+
+func (f *Client) DeleteSpotNodePool(ctx context.Context, namespace, name string, apiVersion ...client.APIVersion) (*client.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbDelete, client.SpotNodePoolResource, namespace, name); err != nil {
+		return nil, err
+	}
+	return f.spotNodePools.delete(client.SpotNodePoolResource, namespace, name)
+}
+
+func (f *Client) DeleteAllSpotNodePools(ctx context.Context, namespace string, apiVersion ...client.APIVersion) (*client.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbDeleteCollection, client.SpotNodePoolResource, namespace, ""); err != nil {
+		return nil, err
+	}
+	delete(f.spotNodePools, namespace)
+	return &client.DeleteResponse{Status: "Success"}, nil
+}
+
+func (f *Client) ListOnDemandNodePools(ctx context.Context, namespace string, apiVersion ...client.APIVersion) (*client.OnDemandNodePoolList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbList, client.OnDemandNodePoolResource, namespace, ""); err != nil {
+		return nil, err
+	}
+	return &client.OnDemandNodePoolList{Items: f.onDemandNodePools.list(namespace)}, nil
+}
+
+func (f *Client) GetOnDemandNodePool(ctx context.Context, namespace, name string, apiVersion ...client.APIVersion) (*client.OnDemandNodePool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbGet, client.OnDemandNodePoolResource, namespace, name); err != nil {
+		return nil, err
+	}
+	return f.onDemandNodePools.get(client.OnDemandNodePoolResource, namespace, name)
+}
+
+func (f *Client) GetPriceHistory(ctx context.Context, serverClass string, apiVersion ...client.APIVersion) (*client.PriceHistory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbGet, priceHistoryResource, "", serverClass); err != nil {
+		return nil, err
+	}
+	history, ok := f.priceHistory[serverClass]
+	if !ok {
+		return nil, notFound(priceHistoryResource, serverClass)
+	}
+	history = deepCopy(history)
+	return &history, nil
+}
+
+func (f *Client) GetPercentileInfo(ctx context.Context, serverClass string, apiVersion ...client.APIVersion) (*client.PercentileInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbGet, percentileInfoResource, "", serverClass); err != nil {
+		return nil, err
+	}
+	info, ok := f.percentileInfo[serverClass]
+	if !ok {
+		return nil, notFound(percentileInfoResource, serverClass)
+	}
+	info = deepCopy(info)
+	return &info, nil
+}
+
+// Price endpoints are keyed by server class rather than being resources of their own
+var (
+	priceHistoryResource   = client.Resource{Kind: "PriceHistory", Plural: "price-history", DisplayName: "price history"}
+	percentileInfoResource = client.Resource{Kind: "PercentileInfo", Plural: "percentile-info", DisplayName: "percentile info"}
+)
+
+// objects stores objects of one type by namespace and name; cluster scoped
+// objects use the empty namespace
+type objects[T any] map[string]map[string]T
+
+func (o objects[T]) put(namespace, name string, obj T) {
+	if o[namespace] == nil {
+		o[namespace] = map[string]T{}
+	}
+	o[namespace][name] = deepCopy(obj)
+}
+
+func (o objects[T]) get(resource client.Resource, namespace, name string) (*T, error) {
+	obj, ok := o[namespace][name]
+	if !ok {
+		return nil, notFound(resource, name)
+	}
+	obj = deepCopy(obj)
+	return &obj, nil
+}
+
+func (o objects[T]) list(namespace string) []T {
+	names := make([]string, 0, len(o[namespace]))
+	for name := range o[namespace] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]T, 0, len(names))
+	for _, name := range names {
+		items = append(items, deepCopy(o[namespace][name]))
+	}
+	return items
+}
+
+func (o objects[T]) create(resource client.Resource, namespace, name string, obj T) (*T, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%s name is required", resource.DisplayName)
+	}
+	if _, ok := o[namespace][name]; ok {
+		return nil, &client.APIError{Code: http.StatusConflict, Message: fmt.Sprintf("%s %q already exists", resource.Plural, name)}
+	}
+	o.put(namespace, name, obj)
+	return &obj, nil
+}
+
+func (o objects[T]) patch(resource client.Resource, namespace, name string, patchOps []client.PatchOperation) (*T, error) {
+	obj, ok := o[namespace][name]
+	if !ok {
+		return nil, notFound(resource, name)
+	}
+	patched, err := applyPatch(obj, patchOps)
+	if err != nil {
+		return nil, &client.APIError{Code: http.StatusUnprocessableEntity, Message: err.Error()}
+	}
+	o.put(namespace, name, patched)
+	return &patched, nil
+}
+
+func (o objects[T]) delete(resource client.Resource, namespace, name string) (*client.DeleteResponse, error) {
+	if _, ok := o[namespace][name]; !ok {
+		return nil, notFound(resource, name)
+	}
+	delete(o[namespace], name)
+	return &client.DeleteResponse{
+		Status:  "Success",
+		Details: &client.DeleteDetails{Kind: resource.Plural, Name: name},
+	}, nil
+}
+
+// notFound returns the error the API responds with for a missing object
+func notFound(resource client.Resource, name string) error {
+	return &client.APIError{Code: http.StatusNotFound, Message: fmt.Sprintf("%s %q not found", resource.Plural, name)}
+}
+
+// objectName returns the name of obj, or "" if it is nil
+func objectName[T any](obj *T, name func(*T) string) string {
+	if obj == nil {
+		return ""
+	}
+	return name(obj)
+}
+
+// deepCopy copies v through JSON so no maps or slices are shared
+func deepCopy[T any](v T) T {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("clientfake: failed to copy %T: %v", v, err))
+	}
+	var copied T
+	if err := json.Unmarshal(data, &copied); err != nil {
+		panic(fmt.Sprintf("clientfake: failed to copy %T: %v", v, err))
+	}
+	return copied
+}
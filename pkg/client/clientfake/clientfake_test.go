@@ -0,0 +1,161 @@
+package clientfake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+func TestClient_CloudSpaceLifecycle(t *testing.T) {
+	ctx := context.Background()
+	fake := NewClient(&client.CloudSpace{Metadata: client.ObjectMeta{Name: "existing", Namespace: "org-abc123"}})
+
+	created, err := fake.CreateCloudSpace(ctx, "org-abc123", &client.CloudSpace{
+		Metadata: client.ObjectMeta{Name: "new"},
+		Spec:     client.CloudSpaceSpec{Region: "us-central-dfw-1"},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.Metadata.Namespace != "org-abc123" {
+		t.Errorf("Expected the namespace to be set, got %q", created.Metadata.Namespace)
+	}
+
+	if _, err := fake.CreateCloudSpace(ctx, "org-abc123", created); !isStatus(err, http.StatusConflict) {
+		t.Errorf("Expected a conflict creating a duplicate, got %v", err)
+	}
+
+	edited, err := fake.EditCloudSpace(ctx, "org-abc123", "new", []client.PatchOperation{
+		{Op: "replace", Path: "/spec/region", Value: "us-east-iad-1"},
+	})
+	if err != nil {
+		t.Fatalf("Edit failed: %v", err)
+	}
+	if edited.Spec.Region != "us-east-iad-1" {
+		t.Errorf("Expected the region to be patched, got %q", edited.Spec.Region)
+	}
+
+	list, err := fake.ListCloudSpaces(ctx, "org-abc123")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list.Items) != 2 || list.Items[0].Metadata.Name != "existing" || list.Items[1].Spec.Region != "us-east-iad-1" {
+		t.Errorf("Unexpected list: %+v", list.Items)
+	}
+
+	if _, err := fake.DeleteCloudSpace(ctx, "org-abc123", "new"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := fake.GetCloudSpace(ctx, "org-abc123", "new"); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("Expected not found after delete, got %v", err)
+	}
+	if _, err := fake.ListCloudSpaces(ctx, ""); err == nil {
+		t.Error("Expected an error without a namespace")
+	}
+
+	actions := fake.Actions()
+	if len(actions) != 7 || actions[0] != (Action{Verb: client.VerbCreate, Resource: "cloudspaces", Namespace: "org-abc123", Name: "new"}) {
+		t.Errorf("Unexpected actions: %+v", actions)
+	}
+}
+
+func TestClient_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	fake := NewClient(client.Region{Metadata: client.ObjectMeta{Name: "us-central-dfw-1", Labels: map[string]string{"a": "b"}}})
+
+	region, err := fake.GetRegion(ctx, "us-central-dfw-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	region.Metadata.Labels["a"] = "changed"
+
+	region, _ = fake.GetRegion(ctx, "us-central-dfw-1")
+	if region.Metadata.Labels["a"] != "b" {
+		t.Error("Expected the stored region to be unaffected by changes to a returned copy")
+	}
+}
+
+func TestClient_FailWith(t *testing.T) {
+	ctx := context.Background()
+	injected := errors.New("boom")
+	fake := NewClient()
+	fake.FailWith(client.VerbList, "serverclasses", "", "", injected)
+
+	if _, err := fake.ListServerClasses(ctx); !errors.Is(err, injected) {
+		t.Errorf("Expected the injected error, got %v", err)
+	}
+	if _, err := fake.ListRegions(ctx); err != nil {
+		t.Errorf("Expected other calls to succeed, got %v", err)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	one := 1
+	pool := client.SpotNodePool{Spec: client.SpotNodePoolSpec{Desired: &one}}
+	patched, err := applyPatch(pool, []client.PatchOperation{
+		{Op: "replace", Path: "/spec/desired", Value: 3},
+		{Op: "add", Path: "/metadata/labels", Value: map[string]string{"team": "a"}},
+	})
+	if err != nil {
+		t.Fatalf("applyPatch failed: %v", err)
+	}
+	if patched.Spec.Desired == nil || *patched.Spec.Desired != 3 || patched.Metadata.Labels["team"] != "a" {
+		t.Errorf("Unexpected patched pool: %+v", patched)
+	}
+
+	if _, err := applyPatch(pool, []client.PatchOperation{{Op: "replace", Path: "/spec/missing/field", Value: 1}}); err == nil {
+		t.Error("Expected an error patching a missing path")
+	}
+}
+
+func TestClient_ResourceClient(t *testing.T) {
+	ctx := context.Background()
+	fake := NewClient(
+		client.SpotNodePool{Metadata: client.ObjectMeta{Name: "pool-a", Namespace: "org-abc123"}, Spec: client.SpotNodePoolSpec{BidPrice: "0.01"}},
+		client.SpotNodePool{Metadata: client.ObjectMeta{Name: "pool-b", Namespace: "org-abc123"}},
+		client.Region{Metadata: client.ObjectMeta{Name: "us-central-dfw-1"}},
+	)
+	pools := client.NewResourceClient[client.SpotNodePool, client.SpotNodePoolList](fake, client.SpotNodePoolResource)
+
+	list, err := client.Collect(pools.All(ctx, "org-abc123", client.ListOptions{Limit: 1}), 0)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("Expected both pools, got %+v, %v", list, err)
+	}
+	edited, err := pools.Edit(ctx, "org-abc123", "pool-a", []client.PatchOperation{{Op: "replace", Path: "/spec/bidPrice", Value: "0.05"}})
+	if err != nil || edited.Spec.BidPrice != "0.05" {
+		t.Fatalf("Expected the bid to be patched, got %+v, %v", edited, err)
+	}
+	if _, err := pools.Delete(ctx, "org-abc123", "pool-b"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := pools.Get(ctx, "org-abc123", "pool-b"); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("Expected not found after delete, got %v", err)
+	}
+
+	injected := errors.New("connection refused")
+	fake.FailWith(client.VerbGet, "regions", "", "us-central-dfw-1", injected)
+	regions := client.NewResourceClient[client.Region, client.RegionList](fake, client.RegionResource)
+	if _, err := regions.Get(ctx, "", "us-central-dfw-1"); !errors.Is(err, injected) {
+		t.Errorf("Expected the injected error, got %v", err)
+	}
+
+	want := []Action{
+		{Verb: client.VerbList, Resource: "spotnodepools", Namespace: "org-abc123"},
+		{Verb: client.VerbPatch, Resource: "spotnodepools", Namespace: "org-abc123", Name: "pool-a"},
+		{Verb: client.VerbDelete, Resource: "spotnodepools", Namespace: "org-abc123", Name: "pool-b"},
+		{Verb: client.VerbGet, Resource: "spotnodepools", Namespace: "org-abc123", Name: "pool-b"},
+		{Verb: client.VerbGet, Resource: "regions", Name: "us-central-dfw-1"},
+	}
+	if actions := fake.Actions(); !slices.Equal(actions, want) {
+		t.Errorf("Expected actions %+v, got %+v", want, actions)
+	}
+}
+
+func isStatus(err error, code int) bool {
+	var apiErr *client.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
@@ -0,0 +1,24 @@
+package clientfake
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// applyPatch applies JSON patch operations to a copy of obj
+func applyPatch[T any](obj T, patchOps []client.PatchOperation) (T, error) {
+	var patched T
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return patched, err
+	}
+	if data, err = client.ApplyPatch(data, patchOps); err != nil {
+		return patched, err
+	}
+	if err := json.Unmarshal(data, &patched); err != nil {
+		return patched, fmt.Errorf("patched object is invalid: %w", err)
+	}
+	return patched, nil
+}
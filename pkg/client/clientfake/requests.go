@@ -0,0 +1,167 @@
+package clientfake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// MakeRequest serves a raw API request from the fake's objects, so that
+// client.ResourceClient and the kinds built on it work against the fake. Requests
+// are routed to the typed methods and recorded the same way; list queries such as
+// limit, continue and labelSelector are ignored and every item is returned in one
+// page. An injected *client.APIError becomes an error response, any other injected
+// error is returned as is.
+func (f *Client) MakeRequest(ctx context.Context, method, endpoint string, body interface{}, apiVersion client.APIVersion, contentType ...string) (*http.Response, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	namespace, plural, name := splitPath(u.Path)
+
+	result, err := f.serve(ctx, method, namespace, plural, name, body)
+	req := &http.Request{Method: method, URL: u}
+	if apiErr, ok := err.(*client.APIError); ok {
+		return response(req, apiErr.Code, apiErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response(req, http.StatusOK, result)
+}
+
+// HandleAPIError returns the *client.APIError of an error response made by MakeRequest
+func (f *Client) HandleAPIError(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	defer resp.Body.Close()
+	apiErr := &client.APIError{}
+	if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil {
+		return fmt.Errorf("failed to decode error response: %w", err)
+	}
+	return apiErr
+}
+
+// serve calls the typed method for a request
+func (f *Client) serve(ctx context.Context, method, namespace, plural, name string, body any) (any, error) {
+	switch {
+	case plural == client.RegionResource.Plural && method == http.MethodGet && name != "":
+		return f.GetRegion(ctx, name)
+	case plural == client.RegionResource.Plural && method == http.MethodGet:
+		return f.ListRegions(ctx)
+	case plural == client.ServerClassResource.Plural && method == http.MethodGet && name != "":
+		return f.GetServerClass(ctx, name)
+	case plural == client.ServerClassResource.Plural && method == http.MethodGet:
+		return f.ListServerClasses(ctx)
+	case plural == client.OrganizationResource.Plural && method == http.MethodGet && name == "":
+		return f.ListOrganizations(ctx)
+
+	case plural == client.CloudSpaceResource.Plural && method == http.MethodGet && name != "":
+		return f.GetCloudSpace(ctx, namespace, name)
+	case plural == client.CloudSpaceResource.Plural && method == http.MethodGet:
+		return f.ListCloudSpaces(ctx, namespace)
+	case plural == client.CloudSpaceResource.Plural && method == http.MethodPost:
+		cloudSpace, err := decodeBody[client.CloudSpace](body)
+		if err != nil {
+			return nil, err
+		}
+		return f.CreateCloudSpace(ctx, namespace, cloudSpace)
+	case plural == client.CloudSpaceResource.Plural && method == http.MethodPatch:
+		patchOps, err := decodeBody[[]client.PatchOperation](body)
+		if err != nil {
+			return nil, err
+		}
+		return f.EditCloudSpace(ctx, namespace, name, *patchOps)
+	case plural == client.CloudSpaceResource.Plural && method == http.MethodDelete && name != "":
+		return f.DeleteCloudSpace(ctx, namespace, name)
+
+	case plural == client.SpotNodePoolResource.Plural && method == http.MethodGet && name != "":
+		return f.GetSpotNodePool(ctx, namespace, name)
+	case plural == client.SpotNodePoolResource.Plural && method == http.MethodGet:
+		return f.ListSpotNodePools(ctx, namespace)
+	case plural == client.SpotNodePoolResource.Plural && method == http.MethodPost:
+		spotNodePool, err := decodeBody[client.SpotNodePool](body)
+		if err != nil {
+			return nil, err
+		}
+		return f.CreateSpotNodePool(ctx, namespace, spotNodePool)
+	case plural == client.SpotNodePoolResource.Plural && method == http.MethodPatch:
+		patchOps, err := decodeBody[[]client.PatchOperation](body)
+		if err != nil {
+			return nil, err
+		}
+		return f.EditSpotNodePool(ctx, namespace, name, *patchOps)
+	case plural == client.SpotNodePoolResource.Plural && method == http.MethodDelete && name != "":
+		return f.DeleteSpotNodePool(ctx, namespace, name)
+	case plural == client.SpotNodePoolResource.Plural && method == http.MethodDelete:
+		return f.DeleteAllSpotNodePools(ctx, namespace)
+
+	case plural == client.OnDemandNodePoolResource.Plural && method == http.MethodGet && name != "":
+		return f.GetOnDemandNodePool(ctx, namespace, name)
+	case plural == client.OnDemandNodePoolResource.Plural && method == http.MethodGet:
+		return f.ListOnDemandNodePools(ctx, namespace)
+	case plural == client.OnDemandNodePoolResource.Plural && method == http.MethodDelete && name != "":
+		return f.deleteOnDemandNodePool(namespace, name)
+	}
+	return nil, &client.APIError{Code: http.StatusNotFound, Message: fmt.Sprintf("%s /%s is not served by clientfake", method, plural)}
+}
+
+// deleteOnDemandNodePool deletes an on demand node pool, which client.Interface has no method for
+func (f *Client) deleteOnDemandNodePool(namespace, name string) (*client.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(client.VerbDelete, client.OnDemandNodePoolResource, namespace, name); err != nil {
+		return nil, err
+	}
+	return f.onDemandNodePools.delete(client.OnDemandNodePoolResource, namespace, name)
+}
+
+// splitPath returns the namespace, resource and name of an API path, e.g.
+// /namespaces/org-abc123/cloudspaces/dev
+func splitPath(path string) (namespace, plural, name string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		namespace, parts = parts[1], parts[2:]
+	}
+	plural = parts[0]
+	if len(parts) > 1 {
+		name = parts[1]
+	}
+	return namespace, plural, name
+}
+
+// decodeBody converts a request body to T through JSON, as the API would receive it
+func decodeBody[T any](body any) (*T, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+	var decoded T
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, &client.APIError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return &decoded, nil
+}
+
+// response returns a JSON response to req
+func response(req *http.Request, status int, v any) (*http.Response, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeviceFlow(t *testing.T) {
+	tests := []struct {
+		name          string
+		pollResponses []string
+		wantToken     bool
+		wantErr       string
+		wantWaits     []time.Duration
+	}{
+		{
+			name:          "approved after pending and slow_down",
+			pollResponses: []string{"authorization_pending", "slow_down", "authorization_pending", ""},
+			wantToken:     true,
+			wantWaits:     []time.Duration{2 * time.Second, 2 * time.Second, 7 * time.Second, 7 * time.Second},
+		},
+		{
+			name:          "denied",
+			pollResponses: []string{"authorization_pending", "access_denied"},
+			wantErr:       "denied",
+			wantWaits:     []time.Duration{2 * time.Second, 2 * time.Second},
+		},
+		{
+			name:          "expired",
+			pollResponses: []string{"expired_token"},
+			wantErr:       "expired",
+			wantWaits:     []time.Duration{2 * time.Second},
+		},
+		{
+			name:          "unexpected error",
+			pollResponses: []string{"invalid_client"},
+			wantErr:       "invalid_client",
+			wantWaits:     []time.Duration{2 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/oauth/device/code":
+					if r.PostForm.Get("client_id") != ClientID || !strings.Contains(r.PostForm.Get("scope"), "offline_access") {
+						t.Errorf("Unexpected device authorization request %v", r.PostForm)
+					}
+					json.NewEncoder(w).Encode(DeviceAuthorization{
+						DeviceCode: "device-123", UserCode: "ABCD-EFGH", VerificationURI: "https://example.com/activate",
+						ExpiresIn: 600, Interval: 2,
+					})
+				case "/oauth/token":
+					if r.PostForm.Get("grant_type") != DeviceCodeGrantType || r.PostForm.Get("device_code") != "device-123" {
+						t.Errorf("Unexpected token request %v", r.PostForm)
+					}
+					code := tt.pollResponses[polls]
+					polls++
+					if code != "" {
+						w.WriteHeader(http.StatusBadRequest)
+						json.NewEncoder(w).Encode(map[string]string{"error": code})
+						return
+					}
+					json.NewEncoder(w).Encode(TokenResponse{IDToken: "id-token", RefreshToken: "new-refresh-token", ExpiresIn: 3600})
+				}
+			}))
+			defer server.Close()
+
+			flow := NewDeviceFlow(server.Client(), server.URL+"/oauth/device/code", server.URL+"/oauth/token")
+			var waits []time.Duration
+			flow.wait = func(ctx context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			auth, err := flow.Start(context.Background())
+			if err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			if auth.UserCode != "ABCD-EFGH" {
+				t.Errorf("Unexpected user code %q", auth.UserCode)
+			}
+
+			token, err := flow.Poll(context.Background(), auth)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil || token.RefreshToken != "new-refresh-token" {
+				t.Errorf("Expected the refresh token, got %+v, %v", token, err)
+			}
+
+			if len(waits) != len(tt.wantWaits) {
+				t.Fatalf("Expected waits %v, got %v", tt.wantWaits, waits)
+			}
+			for i := range waits {
+				if waits[i] != tt.wantWaits[i] {
+					t.Errorf("Expected waits %v, got %v", tt.wantWaits, waits)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestDeviceAuthURL(t *testing.T) {
+	tests := []struct {
+		configured, tokenURL, want string
+	}{
+		{"", OAuthURL, DeviceAuthURL},
+		{"", "http://127.0.0.1:8080/oauth/token", "http://127.0.0.1:8080/oauth/device/code"},
+		{"https://idp.example.com/device", OAuthURL, "https://idp.example.com/device"},
+		{"", "https://idp.example.com/token", DeviceAuthURL},
+	}
+	for _, tt := range tests {
+		if got := deviceAuthURL(tt.configured, tt.tokenURL); got != tt.want {
+			t.Errorf("deviceAuthURL(%q, %q) = %q, want %q", tt.configured, tt.tokenURL, got, tt.want)
+		}
+	}
+}
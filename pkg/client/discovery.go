@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// APIGroupList is the Kubernetes style discovery document listing the API groups a server serves
+type APIGroupList struct {
+	Kind       string     `json:"kind,omitempty"`
+	APIVersion string     `json:"apiVersion,omitempty"`
+	Groups     []APIGroup `json:"groups"`
+}
+
+// APIGroup is an API group and the versions of it the server serves
+type APIGroup struct {
+	Name             string                     `json:"name"`
+	Versions         []GroupVersionForDiscovery `json:"versions"`
+	PreferredVersion GroupVersionForDiscovery   `json:"preferredVersion"`
+}
+
+// GroupVersionForDiscovery names one version of an API group
+type GroupVersionForDiscovery struct {
+	// GroupVersion is group/version, e.g. ngpc.rxt.io/v1
+	GroupVersion string `json:"groupVersion"`
+	Version      string `json:"version"`
+}
+
+// APIResourceList is the discovery document listing the resources of one group version
+type APIResourceList struct {
+	Kind         string        `json:"kind,omitempty"`
+	APIVersion   string        `json:"apiVersion,omitempty"`
+	GroupVersion string        `json:"groupVersion"`
+	Resources    []APIResource `json:"resources"`
+}
+
+// APIResource describes a resource served by a group version
+type APIResource struct {
+	// Name is the plural name, or plural/subresource for subresources
+	Name         string   `json:"name"`
+	SingularName string   `json:"singularName"`
+	Namespaced   bool     `json:"namespaced"`
+	Kind         string   `json:"kind"`
+	Verbs        []string `json:"verbs"`
+	ShortNames   []string `json:"shortNames,omitempty"`
+}
+
+// KnownResources returns the resources spotctl knows about, for use when the server
+// does not support discovery
+func KnownResources() []Resource {
+	return []Resource{
+		CloudSpaceResource,
+		OnDemandNodePoolResource,
+		OrganizationResource,
+		RegionResource,
+		ServerClassResource,
+		SpotNodePoolResource,
+	}
+}
+
+// ServerGroups returns the API groups the server serves, from discovery at the root of the API
+func (c *Client) ServerGroups(ctx context.Context) (*APIGroupList, error) {
+	var groups APIGroupList
+	if err := c.discover(ctx, "", &groups); err != nil {
+		return nil, err
+	}
+	return &groups, nil
+}
+
+// ServerResources returns the resources the server serves for an API version,
+// after applying any api-version override
+func (c *Client) ServerResources(ctx context.Context, apiVersion APIVersion) (*APIResourceList, error) {
+	var resources APIResourceList
+	if err := c.discover(ctx, apiVersion, &resources); err != nil {
+		return nil, err
+	}
+	return &resources, nil
+}
+
+// discover gets a discovery document
+func (c *Client) discover(ctx context.Context, apiVersion APIVersion, result any) error {
+	resp, err := c.MakeRequest(ctx, http.MethodGet, "", nil, apiVersion)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode discovery response: %w", err)
+	}
+	return nil
+}
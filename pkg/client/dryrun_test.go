@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/config"
+)
+
+func TestClient_WithDryRun(t *testing.T) {
+	dryRuns := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dryRuns[r.Method] = r.URL.Query().Get("dryRun")
+		w.Write([]byte(`{"metadata":{"name":"my-cloudspace"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{RefreshToken: "test-token", BaseURL: server.URL, Timeout: 30})
+	client.tokenManager = &MockTokenManager{accessToken: "mock-access-token"}
+	dryRunClient := client.WithDryRun()
+
+	if client.DryRun() || !dryRunClient.DryRun() {
+		t.Fatal("Expected only the copy to be a dry run client")
+	}
+
+	ctx := context.Background()
+	cloudSpace := &CloudSpace{Metadata: ObjectMeta{Name: "my-cloudspace"}}
+	if _, err := dryRunClient.CreateCloudSpace(ctx, "org-abc123", cloudSpace); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := dryRunClient.EditCloudSpace(ctx, "org-abc123", "my-cloudspace", []PatchOperation{{Op: "replace", Path: "/spec/cni", Value: "calico"}}); err != nil {
+		t.Fatalf("Edit failed: %v", err)
+	}
+	if _, err := dryRunClient.DeleteCloudSpace(ctx, "org-abc123", "my-cloudspace"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := dryRunClient.GetCloudSpace(ctx, "org-abc123", "my-cloudspace"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodDelete} {
+		if dryRuns[method] != "All" {
+			t.Errorf("Expected dryRun=All on %s, got %q", method, dryRuns[method])
+		}
+	}
+	if dryRuns[http.MethodGet] != "" {
+		t.Errorf("Expected no dryRun on GET, got %q", dryRuns[http.MethodGet])
+	}
+
+	// The original client still persists changes
+	if _, err := client.DeleteCloudSpace(ctx, "org-abc123", "my-cloudspace"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if dryRuns[http.MethodDelete] != "" {
+		t.Errorf("Expected no dryRun from the original client, got %q", dryRuns[http.MethodDelete])
+	}
+}
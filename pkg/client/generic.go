@@ -14,6 +14,15 @@ import (
 type ListOptions struct {
 	Namespace  string
 	APIVersion APIVersion
+	// Limit is the maximum number of items to request per page; the server default if zero
+	Limit int64
+	// Continue is the continuation token of the page to request, taken from ListMeta.Continue
+	Continue string
+	// LabelSelector limits the list to objects whose labels match, e.g. env=dev,tier!=web
+	LabelSelector string
+
+	// start is the offset of the page to request from offset paged endpoints such as organizations
+	start int
 }
 
 // GetOptions contains parameters for get operations
@@ -45,33 +54,34 @@ type DeleteOptions struct {
 }
 
 // genericList performs a generic list operation
-func genericList[T any](c *Client, ctx context.Context, endpoint string, opts ListOptions) (*T, error) {
-	// Use default API version if not specified
-	apiVersion := opts.APIVersion
-	if apiVersion == "" {
-		apiVersion = APIVersionDefault
-	}
-
-	resp, err := c.MakeRequest(ctx, http.MethodGet, endpoint, nil, apiVersion)
+func genericList[T any](c Requester, ctx context.Context, endpoint string, opts ListOptions) (*T, error) {
+	result, err := genericListPage[T](c, ctx, endpoint, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if err := c.HandleAPIError(resp); err != nil {
 		return nil, err
 	}
 
-	var result T
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+	// Follow continuation so callers never see a silently truncated list
+	page := result
+	for {
+		next, more, err := followPage(page, opts)
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			return result, nil
+		}
+		opts = next
 
-	return &result, nil
+		page, err = genericListPage[T](c, ctx, endpoint, opts)
+		if err != nil {
+			return nil, err
+		}
+		any(result).(PagedList).AppendPage(page)
+	}
 }
 
 // genericGet performs a generic get operation
-func genericGet[T any](c *Client, ctx context.Context, endpoint string, opts GetOptions) (*T, error) {
+func genericGet[T any](c Requester, ctx context.Context, endpoint string, opts GetOptions) (*T, error) {
 	// Use default API version if not specified
 	apiVersion := opts.APIVersion
 	if apiVersion == "" {
@@ -97,7 +107,7 @@ func genericGet[T any](c *Client, ctx context.Context, endpoint string, opts Get
 }
 
 // genericCreate performs a create operation
-func genericCreate[T any](c *Client, ctx context.Context, endpoint string, body interface{}, opts CreateOptions) (*T, error) {
+func genericCreate[T any](c Requester, ctx context.Context, endpoint string, body interface{}, opts CreateOptions) (*T, error) {
 	// Use default API version if not specified
 	apiVersion := opts.APIVersion
 	if apiVersion == "" {
@@ -123,7 +133,7 @@ func genericCreate[T any](c *Client, ctx context.Context, endpoint string, body
 }
 
 // genericEdit performs an edit operation using JSON patch
-func genericEdit[T any](c *Client, ctx context.Context, endpoint string, patchOps []PatchOperation, opts EditOptions) (*T, error) {
+func genericEdit[T any](c Requester, ctx context.Context, endpoint string, patchOps []PatchOperation, opts EditOptions) (*T, error) {
 	// Use default API version if not specified
 	apiVersion := opts.APIVersion
 	if apiVersion == "" {
@@ -149,7 +159,7 @@ func genericEdit[T any](c *Client, ctx context.Context, endpoint string, patchOp
 }
 
 // genericDelete performs a delete operation
-func genericDelete[T any](c *Client, ctx context.Context, endpoint string, opts DeleteOptions) (*T, error) {
+func genericDelete[T any](c Requester, ctx context.Context, endpoint string, opts DeleteOptions) (*T, error) {
 	// Use default API version if not specified
 	apiVersion := opts.APIVersion
 	if apiVersion == "" {
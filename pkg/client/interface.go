@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Requester makes API requests and turns error responses into errors, which is
+// all ResourceClient needs to operate on a resource of any kind
+type Requester interface {
+	MakeRequest(ctx context.Context, method, endpoint string, body interface{}, apiVersion APIVersion, contentType ...string) (*http.Response, error)
+	HandleAPIError(resp *http.Response) error
+}
+
+// RegionsInterface reads regions
+type RegionsInterface interface {
+	ListRegions(ctx context.Context, apiVersion ...APIVersion) (*RegionList, error)
+	GetRegion(ctx context.Context, name string, apiVersion ...APIVersion) (*Region, error)
+}
+
+// ServerClassesInterface reads server classes
+type ServerClassesInterface interface {
+	ListServerClasses(ctx context.Context, apiVersion ...APIVersion) (*ServerClassList, error)
+	GetServerClass(ctx context.Context, name string, apiVersion ...APIVersion) (*ServerClass, error)
+}
+
+// OrganizationsInterface reads the organizations of the authenticated user
+type OrganizationsInterface interface {
+	ListOrganizations(ctx context.Context, apiVersion ...APIVersion) (*OrganizationList, error)
+}
+
+// CloudSpacesInterface manages cloudspaces
+type CloudSpacesInterface interface {
+	ListCloudSpaces(ctx context.Context, namespace string, apiVersion ...APIVersion) (*CloudSpaceList, error)
+	GetCloudSpace(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*CloudSpace, error)
+	CreateCloudSpace(ctx context.Context, namespace string, cloudSpace *CloudSpace, apiVersion ...APIVersion) (*CloudSpace, error)
+	EditCloudSpace(ctx context.Context, namespace, name string, patchOps []PatchOperation, apiVersion ...APIVersion) (*CloudSpace, error)
+	DeleteCloudSpace(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*DeleteResponse, error)
+}
+
+// SpotNodePoolsInterface manages spot node pools
+type SpotNodePoolsInterface interface {
+	ListSpotNodePools(ctx context.Context, namespace string, apiVersion ...APIVersion) (*SpotNodePoolList, error)
+	GetSpotNodePool(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*SpotNodePool, error)
+	CreateSpotNodePool(ctx context.Context, namespace string, spotNodePool *SpotNodePool, apiVersion ...APIVersion) (*SpotNodePool, error)
+	EditSpotNodePool(ctx context.Context, namespace, name string, patchOps []PatchOperation, apiVersion ...APIVersion) (*SpotNodePool, error)
+	DeleteSpotNodePool(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*DeleteResponse, error)
+	DeleteAllSpotNodePools(ctx context.Context, namespace string, apiVersion ...APIVersion) (*DeleteResponse, error)
+}
+
+// OnDemandNodePoolsInterface reads on demand node pools
+type OnDemandNodePoolsInterface interface {
+	ListOnDemandNodePools(ctx context.Context, namespace string, apiVersion ...APIVersion) (*OnDemandNodePoolList, error)
+	GetOnDemandNodePool(ctx context.Context, namespace, name string, apiVersion ...APIVersion) (*OnDemandNodePool, error)
+}
+
+// PricesInterface reads the auction prices of server classes
+type PricesInterface interface {
+	GetPriceHistory(ctx context.Context, serverClass string, apiVersion ...APIVersion) (*PriceHistory, error)
+	GetPercentileInfo(ctx context.Context, serverClass string, apiVersion ...APIVersion) (*PercentileInfo, error)
+}
+
+// Interface is the Rackspace Spot API as used by programs importing this package.
+// Client implements it against the API; clientfake implements it in memory for tests.
+type Interface interface {
+	Requester
+	RegionsInterface
+	ServerClassesInterface
+	OrganizationsInterface
+	CloudSpacesInterface
+	SpotNodePoolsInterface
+	OnDemandNodePoolsInterface
+	PricesInterface
+}
+
+var _ Interface = (*Client)(nil)
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ApplyPatch applies JSON patch (RFC 6902) add, replace and remove operations to a
+// JSON document and returns the patched document
+func ApplyPatch(document []byte, patchOps []PatchOperation) ([]byte, error) {
+	var doc any
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON document: %w", err)
+	}
+
+	for _, op := range patchOps {
+		path, err := splitPointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = applyOperation(doc, op, path); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+// splitPointer splits a JSON pointer such as /spec/taints/0 into unescaped tokens
+func splitPointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// applyOperation applies op at path within node and returns the updated node
+func applyOperation(node any, op PatchOperation, path []string) (any, error) {
+	if len(path) == 0 {
+		switch op.Op {
+		case "add", "replace":
+			return normalize(op.Value)
+		default:
+			return nil, fmt.Errorf("unsupported operation on the whole object")
+		}
+	}
+
+	token, rest := path[0], path[1:]
+	switch n := node.(type) {
+	case map[string]any:
+		if len(rest) > 0 {
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("path does not exist")
+			}
+			updated, err := applyOperation(child, op, rest)
+			if err != nil {
+				return nil, err
+			}
+			n[token] = updated
+			return n, nil
+		}
+		switch op.Op {
+		case "add":
+		case "replace", "remove":
+			if _, ok := n[token]; !ok {
+				return nil, fmt.Errorf("path does not exist")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported operation")
+		}
+		if op.Op == "remove" {
+			delete(n, token)
+			return n, nil
+		}
+		value, err := normalize(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = value
+		return n, nil
+
+	case []any:
+		if len(rest) == 0 && op.Op == "add" && token == "-" {
+			value, err := normalize(op.Value)
+			if err != nil {
+				return nil, err
+			}
+			return append(n, value), nil
+		}
+		index, err := strconv.Atoi(token)
+		limit := len(n)
+		if len(rest) == 0 && op.Op == "add" {
+			limit++
+		}
+		if err != nil || index < 0 || index >= limit {
+			return nil, fmt.Errorf("invalid array index %q", token)
+		}
+		if len(rest) > 0 {
+			updated, err := applyOperation(n[index], op, rest)
+			if err != nil {
+				return nil, err
+			}
+			n[index] = updated
+			return n, nil
+		}
+		switch op.Op {
+		case "remove":
+			return append(n[:index], n[index+1:]...), nil
+		case "replace", "add":
+			value, err := normalize(op.Value)
+			if err != nil {
+				return nil, err
+			}
+			if op.Op == "replace" {
+				n[index] = value
+				return n, nil
+			}
+			n = append(n, nil)
+			copy(n[index+1:], n[index:])
+			n[index] = value
+			return n, nil
+		default:
+			return nil, fmt.Errorf("unsupported operation")
+		}
+
+	default:
+		return nil, fmt.Errorf("path does not exist")
+	}
+}
+
+// normalize converts a patch value to the generic JSON form of the document
+func normalize(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized any
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
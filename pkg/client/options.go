@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// TokenSource supplies bearer tokens for API requests
+type TokenSource = TokenManagerInterface
+
+// staticTokenSource always returns the same token
+type staticTokenSource string
+
+func (s staticTokenSource) GetValidAccessToken(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// StaticTokenSource returns a token source for an already issued bearer token
+func StaticTokenSource(token string) TokenSource {
+	return staticTokenSource(token)
+}
+
+// Option configures a Client created by NewClient
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for API and token requests, e.g. to
+// supply a custom transport. The configured timeout, TLS and proxy settings are
+// not applied to it.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTokenSource sets where bearer tokens come from instead of exchanging the
+// configured refresh token
+func WithTokenSource(tokenSource TokenSource) Option {
+	return func(c *Client) {
+		c.tokenManager = tokenSource
+	}
+}
+
+// WithBaseURL sets the API base URL, overriding the configured one
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with API requests
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader sets a header on every API request, replacing any value the client
+// would send itself, e.g. Accept or Content-Type
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		c.headers.Add(key, value)
+	}
+}
+
+// WithAPIVersion sends requests for the API group of version with that version
+// instead of the one they were made with, overriding the configured api-version.
+// See ParseAPIVersion for the accepted forms.
+func WithAPIVersion(version string) Option {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// Backoff is the wait before the first retry; it doubles with every retry
+	Backoff time.Duration
+}
+
+// WithRetry retries idempotent requests (GET, PUT and DELETE) that fail with a
+// connection error, 429 or 5xx status up to maxRetries times, waiting backoff
+// before the first retry and doubling it after each. A Retry-After header from
+// the server takes precedence over the backoff.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retry = RetryPolicy{MaxRetries: maxRetries, Backoff: backoff}
+	}
+}
+
+// WithLogger sets the logger for request, retry and token refresh diagnostics
+// instead of slog.Default()
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
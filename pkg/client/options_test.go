@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/config"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewClient_Options(t *testing.T) {
+	var gotAuth, gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotUserAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	var transportCalls int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		transportCalls++
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	client := NewClient(&config.Config{BaseURL: "http://configured.invalid", Timeout: 30},
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithTokenSource(StaticTokenSource("static-token")),
+		WithUserAgent("my-tool/1.0"),
+	)
+
+	if _, err := client.ListRegions(context.Background()); err != nil {
+		t.Fatalf("ListRegions failed: %v", err)
+	}
+	if gotAuth != "Bearer static-token" {
+		t.Errorf("Expected the static token, got %q", gotAuth)
+	}
+	if gotUserAgent != "my-tool/1.0" {
+		t.Errorf("Expected the custom user agent, got %q", gotUserAgent)
+	}
+	if transportCalls != 1 {
+		t.Errorf("Expected the custom transport to be used once, got %d", transportCalls)
+	}
+}
+
+func TestNewClient_WithRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		failures      int32
+		status        int
+		expectedCalls int32
+		expectError   bool
+	}{
+		{
+			name:          "GET succeeds after transient failures",
+			method:        http.MethodGet,
+			failures:      2,
+			status:        http.StatusServiceUnavailable,
+			expectedCalls: 3,
+		},
+		{
+			name:          "GET gives up after max retries",
+			method:        http.MethodGet,
+			failures:      5,
+			status:        http.StatusTooManyRequests,
+			expectedCalls: 4,
+			expectError:   true,
+		},
+		{
+			name:          "client errors are not retried",
+			method:        http.MethodGet,
+			failures:      1,
+			status:        http.StatusNotFound,
+			expectedCalls: 1,
+			expectError:   true,
+		},
+		{
+			name:          "POST is not retried",
+			method:        http.MethodPost,
+			failures:      1,
+			status:        http.StatusServiceUnavailable,
+			expectedCalls: 1,
+			expectError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) <= tt.failures {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(`{"message":"try again"}`))
+					return
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(&config.Config{BaseURL: server.URL, Timeout: 30},
+				WithTokenSource(StaticTokenSource("static-token")),
+				WithRetry(3, time.Millisecond),
+			)
+
+			resp, err := client.MakeRequest(context.Background(), tt.method, "/regions", map[string]string{"a": "b"}, APIVersionDefault)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if tt.expectError != (err != nil) {
+				t.Errorf("Expected error: %v, got %v", tt.expectError, err)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("2"); !ok || wait != 2*time.Second {
+		t.Errorf("Expected 2s, got %v (%v)", wait, ok)
+	}
+	if _, ok := parseRetryAfter("Wed, 21 Oct 2015 07:28:00 GMT"); ok {
+		t.Error("Expected dates to be ignored")
+	}
+}
+
+func TestParseAPIVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    APIVersion
+		wantErr bool
+	}{
+		{input: "ngpc.rxt.io/v2beta1", want: "ngpc.rxt.io/v2beta1"},
+		{input: "v2beta1", want: "ngpc.rxt.io/v2beta1"},
+		{input: "auth.ngpc.rxt.io/v1", want: APIVersionAuth},
+		{input: "ngpc.rxt.io/beta", wantErr: true},
+		{input: "/v1", wantErr: true},
+		{input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAPIVersion(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAPIVersion(%q) = %q, %v, want %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewClient_WithAPIVersion(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(&config.Config{BaseURL: server.URL, Timeout: 30, APIVersion: "ngpc.rxt.io/v1"},
+		WithTokenSource(StaticTokenSource("token")), WithAPIVersion("v2beta1"))
+	ctx := context.Background()
+	c.ListRegions(ctx)
+	c.ListOrganizations(ctx)
+	if len(paths) != 2 || paths[0] != "/ngpc.rxt.io/v2beta1/regions" || paths[1] != "/auth.ngpc.rxt.io/v1/organizations" {
+		t.Errorf("Expected only the ngpc.rxt.io group to use the override, got %v", paths)
+	}
+
+	c = NewClient(&config.Config{BaseURL: server.URL, Timeout: 30, APIVersion: "not a version"}, WithTokenSource(StaticTokenSource("token")))
+	if _, err := c.ListRegions(ctx); err == nil {
+		t.Error("Expected an invalid api-version to fail requests")
+	}
+}
@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PagedList is implemented by list responses that can span several pages. List
+// types that do not implement it are read as a single page.
+type PagedList interface {
+	// NextPage returns the options requesting the page after this one, or false if this is the last page
+	NextPage(opts ListOptions) (ListOptions, bool)
+	// AppendPage appends the items of the following page and takes over its paging state
+	AppendPage(next any)
+}
+
+// pageQuery returns the query string selecting the page described by opts
+func pageQuery(opts ListOptions) string {
+	query := url.Values{}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.FormatInt(opts.Limit, 10))
+	}
+	if opts.Continue != "" {
+		query.Set("continue", opts.Continue)
+	}
+	if opts.LabelSelector != "" {
+		query.Set("labelSelector", opts.LabelSelector)
+	}
+	if opts.start > 0 {
+		query.Set("start", strconv.Itoa(opts.start))
+	}
+	return query.Encode()
+}
+
+// withQuery appends an encoded query string to an endpoint that may already have one
+func withQuery(endpoint, query string) string {
+	if query == "" {
+		return endpoint
+	}
+	if strings.Contains(endpoint, "?") {
+		return endpoint + "&" + query
+	}
+	return endpoint + "?" + query
+}
+
+// genericListPage fetches a single page of a list
+func genericListPage[T any](c Requester, ctx context.Context, endpoint string, opts ListOptions) (*T, error) {
+	apiVersion := opts.APIVersion
+	if apiVersion == "" {
+		apiVersion = APIVersionDefault
+	}
+
+	resp, err := c.MakeRequest(ctx, http.MethodGet, withQuery(endpoint, pageQuery(opts)), nil, apiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := c.HandleAPIError(resp); err != nil {
+		return nil, err
+	}
+
+	var result T
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// followPage returns the options for the page after page, checking that paging makes progress
+func followPage(page any, opts ListOptions) (ListOptions, bool, error) {
+	paged, ok := page.(PagedList)
+	if !ok {
+		return opts, false, nil
+	}
+	next, more := paged.NextPage(opts)
+	if !more {
+		return opts, false, nil
+	}
+	if next.Continue == opts.Continue && next.start == opts.start {
+		return opts, false, fmt.Errorf("pagination did not advance past continue token %q", opts.Continue)
+	}
+	return next, true, nil
+}
+
+// genericListAll returns an iterator over the items of every page of a list, fetching
+// pages lazily as the iteration proceeds. Iteration stops after the first error.
+func genericListAll[T any, I any](c Requester, ctx context.Context, endpoint string, opts ListOptions, items func(*T) []I) iter.Seq2[I, error] {
+	return func(yield func(I, error) bool) {
+		var zero I
+		for {
+			page, err := genericListPage[T](c, ctx, endpoint, opts)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items(page) {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			next, more, err := followPage(page, opts)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !more {
+				return
+			}
+			opts = next
+		}
+	}
+}
+
+// Continuation for Kubernetes style lists
+
+func (l *RegionList) NextPage(opts ListOptions) (ListOptions, bool) {
+	return l.Metadata.NextPage(opts)
+}
+
+func (l *RegionList) AppendPage(next any) {
+	page := next.(*RegionList)
+	l.Items = append(l.Items, page.Items...)
+	l.Metadata = page.Metadata
+}
+
+func (l *ServerClassList) NextPage(opts ListOptions) (ListOptions, bool) {
+	return l.Metadata.NextPage(opts)
+}
+
+func (l *ServerClassList) AppendPage(next any) {
+	page := next.(*ServerClassList)
+	l.Items = append(l.Items, page.Items...)
+	l.Metadata = page.Metadata
+}
+
+func (l *CloudSpaceList) NextPage(opts ListOptions) (ListOptions, bool) {
+	return l.Metadata.NextPage(opts)
+}
+
+func (l *CloudSpaceList) AppendPage(next any) {
+	page := next.(*CloudSpaceList)
+	l.Items = append(l.Items, page.Items...)
+	l.Metadata = page.Metadata
+}
+
+func (l *SpotNodePoolList) NextPage(opts ListOptions) (ListOptions, bool) {
+	return l.Metadata.NextPage(opts)
+}
+
+func (l *SpotNodePoolList) AppendPage(next any) {
+	page := next.(*SpotNodePoolList)
+	l.Items = append(l.Items, page.Items...)
+	l.Metadata = page.Metadata
+}
+
+func (l *OnDemandNodePoolList) NextPage(opts ListOptions) (ListOptions, bool) {
+	return l.Metadata.NextPage(opts)
+}
+
+func (l *OnDemandNodePoolList) AppendPage(next any) {
+	page := next.(*OnDemandNodePoolList)
+	l.Items = append(l.Items, page.Items...)
+	l.Metadata = page.Metadata
+}
+
+// NextPage requests the page after a Kubernetes style list page using its continue
+// token, so list types with ListMeta metadata can implement PagedList with it
+func (meta ListMeta) NextPage(opts ListOptions) (ListOptions, bool) {
+	if meta.Continue == "" {
+		return opts, false
+	}
+	opts.Continue = meta.Continue
+	return opts, true
+}
+
+// Organizations are paged by offset rather than by continue token
+
+func (l *OrganizationList) NextPage(opts ListOptions) (ListOptions, bool) {
+	next := l.Start + l.Length
+	if l.Length == 0 || (l.Next == "" && next >= l.Total) {
+		return opts, false
+	}
+	opts.start = next
+	return opts, true
+}
+
+func (l *OrganizationList) AppendPage(next any) {
+	page := next.(*OrganizationList)
+	l.Organizations = append(l.Organizations, page.Organizations...)
+	l.Length += page.Length
+	l.Next = page.Next
+	l.Total = page.Total
+}
+
+// AllRegions returns an iterator over all regions, following continuation across pages
+func (c *Client) AllRegions(ctx context.Context, opts ListOptions) iter.Seq2[Region, error] {
+	return c.Regions(opts.APIVersion).All(ctx, "", opts)
+}
+
+// AllServerClasses returns an iterator over all server classes, following continuation across pages
+func (c *Client) AllServerClasses(ctx context.Context, opts ListOptions) iter.Seq2[ServerClass, error] {
+	return c.ServerClasses(opts.APIVersion).All(ctx, "", opts)
+}
+
+// AllOrganizations returns an iterator over all organizations, following continuation across pages
+func (c *Client) AllOrganizations(ctx context.Context, opts ListOptions) iter.Seq2[Organization, error] {
+	return c.Organizations(opts.APIVersion).All(ctx, "", opts)
+}
+
+// AllCloudSpaces returns an iterator over all cloudspaces in a namespace, following continuation across pages
+func (c *Client) AllCloudSpaces(ctx context.Context, namespace string, opts ListOptions) iter.Seq2[CloudSpace, error] {
+	return c.CloudSpaces(opts.APIVersion).All(ctx, namespace, opts)
+}
+
+// AllSpotNodePools returns an iterator over all spot node pools in a namespace, following continuation across pages
+func (c *Client) AllSpotNodePools(ctx context.Context, namespace string, opts ListOptions) iter.Seq2[SpotNodePool, error] {
+	return c.SpotNodePools(opts.APIVersion).All(ctx, namespace, opts)
+}
+
+// AllOnDemandNodePools returns an iterator over all on-demand node pools in a namespace, following continuation across pages
+func (c *Client) AllOnDemandNodePools(ctx context.Context, namespace string, opts ListOptions) iter.Seq2[OnDemandNodePool, error] {
+	return c.OnDemandNodePools(opts.APIVersion).All(ctx, namespace, opts)
+}
+
+// failedSeq returns an iterator yielding only err
+func failedSeq[I any](err error) iter.Seq2[I, error] {
+	return func(yield func(I, error) bool) {
+		var zero I
+		yield(zero, err)
+	}
+}
+
+// Collect gathers the items of an iterator into a slice, stopping after limit items
+// if limit is positive. It returns the items gathered before the first error.
+func Collect[I any](seq iter.Seq2[I, error], limit int) ([]I, error) {
+	items := []I{}
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+		if limit > 0 && len(items) >= limit {
+			break
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/config"
+)
+
+// newPagedTestClient serves three pages of cloudspaces chained by continue tokens
+// and records the query of every request made
+func newPagedTestClient(t *testing.T) (*Client, *[]string) {
+	var queries []string
+	pages := map[string]string{
+		"":       `{"items":[{"metadata":{"name":"cs-1"}},{"metadata":{"name":"cs-2"}}],"metadata":{"continue":"page-2","remainingItemCount":3}}`,
+		"page-2": `{"items":[{"metadata":{"name":"cs-3"}},{"metadata":{"name":"cs-4"}}],"metadata":{"continue":"page-3","remainingItemCount":1}}`,
+		"page-3": `{"items":[{"metadata":{"name":"cs-5"}}],"metadata":{}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ngpc.rxt.io/v1/namespaces/org-abc123/cloudspaces" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		page, ok := pages[r.URL.Query().Get("continue")]
+		if !ok {
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"message":"continue token expired"}`))
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(&config.Config{
+		RefreshToken: "test-token",
+		BaseURL:      server.URL,
+		Timeout:      30,
+	})
+	client.tokenManager = &MockTokenManager{accessToken: "mock-access-token"}
+
+	return client, &queries
+}
+
+func cloudSpaceNames(items []CloudSpace) string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Metadata.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestClient_ListCloudSpacesFollowsContinue(t *testing.T) {
+	client, queries := newPagedTestClient(t)
+
+	list, err := client.ListCloudSpaces(context.Background(), "org-abc123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := cloudSpaceNames(list.Items); got != "cs-1,cs-2,cs-3,cs-4,cs-5" {
+		t.Errorf("Expected all pages to be merged, got %s", got)
+	}
+	if list.Metadata.Continue != "" {
+		t.Errorf("Expected no continue token on the merged list, got %q", list.Metadata.Continue)
+	}
+	if len(*queries) != 3 {
+		t.Errorf("Expected 3 requests, got %d", len(*queries))
+	}
+}
+
+func TestClient_AllCloudSpaces(t *testing.T) {
+	t.Run("iterates every page with the page size", func(t *testing.T) {
+		client, queries := newPagedTestClient(t)
+
+		items, err := Collect(client.AllCloudSpaces(context.Background(), "org-abc123", ListOptions{Limit: 2}), 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := cloudSpaceNames(items); got != "cs-1,cs-2,cs-3,cs-4,cs-5" {
+			t.Errorf("Unexpected items: %s", got)
+		}
+		expected := []string{"limit=2", "continue=page-2&limit=2", "continue=page-3&limit=2"}
+		if fmt.Sprint(*queries) != fmt.Sprint(expected) {
+			t.Errorf("Expected queries %v, got %v", expected, *queries)
+		}
+	})
+
+	t.Run("stops fetching once enough items are collected", func(t *testing.T) {
+		client, queries := newPagedTestClient(t)
+
+		items, err := Collect(client.AllCloudSpaces(context.Background(), "org-abc123", ListOptions{}), 3)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := cloudSpaceNames(items); got != "cs-1,cs-2,cs-3" {
+			t.Errorf("Unexpected items: %s", got)
+		}
+		if len(*queries) != 2 {
+			t.Errorf("Expected 2 requests, got %d", len(*queries))
+		}
+	})
+
+	t.Run("starts from a continue token", func(t *testing.T) {
+		client, _ := newPagedTestClient(t)
+
+		items, err := Collect(client.AllCloudSpaces(context.Background(), "org-abc123", ListOptions{Continue: "page-3"}), 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := cloudSpaceNames(items); got != "cs-5" {
+			t.Errorf("Unexpected items: %s", got)
+		}
+	})
+
+	t.Run("returns the API error", func(t *testing.T) {
+		client, _ := newPagedTestClient(t)
+
+		_, err := Collect(client.AllCloudSpaces(context.Background(), "org-abc123", ListOptions{Continue: "expired"}), 0)
+		if err == nil || !strings.Contains(err.Error(), "continue token expired") {
+			t.Errorf("Expected continue token error, got %v", err)
+		}
+	})
+
+	t.Run("requires a namespace", func(t *testing.T) {
+		client, queries := newPagedTestClient(t)
+
+		_, err := Collect(client.AllCloudSpaces(context.Background(), "", ListOptions{}), 0)
+		if err == nil || !strings.Contains(err.Error(), "namespace is required") {
+			t.Errorf("Expected namespace error, got %v", err)
+		}
+		if len(*queries) != 0 {
+			t.Errorf("Expected no requests, got %d", len(*queries))
+		}
+	})
+}
+
+func TestClient_ListStopsOnRepeatedContinueToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"items":[{"metadata":{"name":"loop"}}],"metadata":{"continue":"same"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{RefreshToken: "test-token", BaseURL: server.URL, Timeout: 30})
+	client.tokenManager = &MockTokenManager{accessToken: "mock-access-token"}
+
+	_, err := client.ListRegions(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "did not advance") {
+		t.Errorf("Expected pagination error, got %v", err)
+	}
+}
+
+func TestClient_ListOrganizationsFollowsOffset(t *testing.T) {
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := r.URL.Query().Get("start")
+		starts = append(starts, start)
+		switch start {
+		case "":
+			w.Write([]byte(`{"start":0,"limit":2,"length":2,"total":3,"organizations":[{"name":"a"},{"name":"b"}]}`))
+		case "2":
+			w.Write([]byte(`{"start":2,"limit":2,"length":1,"total":3,"organizations":[{"name":"c"}]}`))
+		default:
+			t.Errorf("Unexpected start %q", start)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{RefreshToken: "test-token", BaseURL: server.URL, Timeout: 30})
+	client.tokenManager = &MockTokenManager{accessToken: "mock-access-token"}
+
+	list, err := client.ListOrganizations(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list.Organizations) != 3 || list.Organizations[2].Name != "c" {
+		t.Errorf("Expected 3 organizations across pages, got %+v", list.Organizations)
+	}
+	if list.Length != 3 {
+		t.Errorf("Expected merged length 3, got %d", list.Length)
+	}
+	if len(starts) != 2 {
+		t.Errorf("Expected 2 requests, got %v", starts)
+	}
+}
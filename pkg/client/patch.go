@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -30,37 +31,48 @@ func LoadPatchOperations(filePath string) ([]PatchOperation, error) {
 
 // DisplayPatchOperations shows the patch operations that will be applied
 func DisplayPatchOperations(patchOps []PatchOperation) {
-	fmt.Printf("Applying %d patch operation(s):\n", len(patchOps))
+	WritePatchOperations(os.Stdout, patchOps)
+}
+
+// WritePatchOperations writes the patch operations that will be applied to w
+func WritePatchOperations(w io.Writer, patchOps []PatchOperation) {
+	fmt.Fprintf(w, "Applying %d patch operation(s):\n", len(patchOps))
 	for i, op := range patchOps {
-		fmt.Printf("  %d. %s %s", i+1, op.Op, op.Path)
+		fmt.Fprintf(w, "  %d. %s %s", i+1, op.Op, op.Path)
 		if op.Value != nil {
 			// Try to format the value nicely
 			switch v := op.Value.(type) {
 			case string:
-				fmt.Printf(" = %q", v)
+				fmt.Fprintf(w, " = %q", v)
 			case bool:
-				fmt.Printf(" = %t", v)
+				fmt.Fprintf(w, " = %t", v)
 			case float64:
 				// Check if it's actually an integer
 				if v == float64(int(v)) {
-					fmt.Printf(" = %d", int(v))
+					fmt.Fprintf(w, " = %d", int(v))
 				} else {
-					fmt.Printf(" = %g", v)
+					fmt.Fprintf(w, " = %g", v)
 				}
 			default:
 				// For complex values, show as JSON
 				valueJson, _ := json.Marshal(v)
-				fmt.Printf(" = %s", valueJson)
+				fmt.Fprintf(w, " = %s", valueJson)
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
 // PromptForConfirmation asks the user to confirm the patch operation
 func PromptForConfirmation(resourceName string) (bool, error) {
-	fmt.Printf("\nDo you want to apply these patches to '%s'? (y/N): ", resourceName)
+	return PromptForConfirmationTo(os.Stdout, resourceName)
+}
+
+// PromptForConfirmationTo asks the user to confirm the patch operation, writing
+// the question to w, e.g. stderr when stdout carries machine-readable output
+func PromptForConfirmationTo(w io.Writer, resourceName string) (bool, error) {
+	fmt.Fprintf(w, "\nDo you want to apply these patches to '%s'? (y/N): ", resourceName)
 	var response string
 	fmt.Scanln(&response)
 	return response == "y" || response == "Y" || response == "yes" || response == "Yes", nil
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/config"
+)
+
+func newPriceTestClient(t *testing.T, expectedPath, response string) (*Client, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != expectedPath {
+			t.Errorf("Expected path '%s', got '%s'", expectedPath, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("serverClass"); got != "gp.vs1.large-lon" {
+			t.Errorf("Expected serverClass query 'gp.vs1.large-lon', got '%s'", got)
+		}
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected method GET, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(response))
+	}))
+
+	client := NewClient(&config.Config{
+		RefreshToken: "test-token",
+		BaseURL:      server.URL,
+		Timeout:      30,
+	})
+	client.tokenManager = &MockTokenManager{accessToken: "mock-access-token"}
+
+	return client, server.Close
+}
+
+func TestClient_GetPriceHistory(t *testing.T) {
+	client, cleanup := newPriceTestClient(t, "/ngpc.rxt.io/v1/price-history", `{
+		"serverClass": "gp.vs1.large-lon",
+		"history": [
+			{"timestamp": "2024-06-01T10:00:00Z", "price": "0.012"},
+			{"timestamp": "2024-06-01T11:00:00Z", "price": "0.015"}
+		]
+	}`)
+	defer cleanup()
+
+	history, err := client.GetPriceHistory(context.Background(), "gp.vs1.large-lon")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(history.History) != 2 || history.History[1].Price != "0.015" {
+		t.Errorf("Unexpected history: %+v", history.History)
+	}
+	if history.History[0].Timestamp == nil {
+		t.Error("Expected timestamp to be decoded")
+	}
+
+	if _, err := client.GetPriceHistory(context.Background(), ""); err == nil {
+		t.Error("Expected error for empty server class")
+	}
+}
+
+func TestClient_GetPercentileInfo(t *testing.T) {
+	client, cleanup := newPriceTestClient(t, "/ngpc.rxt.io/v1/percentile-info", `{
+		"serverClass": "gp.vs1.large-lon",
+		"percentiles": {"50": "0.012", "90": "0.02"}
+	}`)
+	defer cleanup()
+
+	info, err := client.GetPercentileInfo(context.Background(), "gp.vs1.large-lon")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.Percentiles["90"] != "0.02" {
+		t.Errorf("Unexpected percentiles: %v", info.Percentiles)
+	}
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// Verbs supported by API resources
+const (
+	VerbGet              = "get"
+	VerbList             = "list"
+	VerbCreate           = "create"
+	VerbPatch            = "patch"
+	VerbDelete           = "delete"
+	VerbDeleteCollection = "deletecollection"
+)
+
+// Resource describes an API resource kind: where it lives and what can be done with it
+type Resource struct {
+	// Kind is the kind of a single object, e.g. CloudSpace
+	Kind string
+	// Plural is the path segment of the resource, e.g. cloudspaces
+	Plural string
+	// Singular is the lower case singular name, e.g. cloudspace
+	Singular string
+	// ShortNames are abbreviations accepted on the command line
+	ShortNames []string
+	// DisplayName is the human readable name used in messages, e.g. "spot node pool"
+	DisplayName string
+	// Namespaced is true for resources that live in an organization namespace
+	Namespaced bool
+	// APIVersion is the API group and version serving the resource
+	APIVersion APIVersion
+	// Verbs lists the operations the API supports for the resource
+	Verbs []string
+}
+
+// Supports reports whether the resource supports verb
+func (r Resource) Supports(verb string) bool {
+	return slices.Contains(r.Verbs, verb)
+}
+
+// Names returns every name the resource can be referred to by on the command line
+func (r Resource) Names() []string {
+	return append([]string{r.Plural, r.Singular}, r.ShortNames...)
+}
+
+// Resource kinds served by the Rackspace Spot API
+var (
+	RegionResource = Resource{
+		Kind:        "Region",
+		Plural:      "regions",
+		Singular:    "region",
+		DisplayName: "region",
+		APIVersion:  APIVersionDefault,
+		Verbs:       []string{VerbGet, VerbList},
+	}
+	ServerClassResource = Resource{
+		Kind:        "ServerClass",
+		Plural:      "serverclasses",
+		Singular:    "serverclass",
+		ShortNames:  []string{"sc"},
+		DisplayName: "server class",
+		APIVersion:  APIVersionDefault,
+		Verbs:       []string{VerbGet, VerbList},
+	}
+	OrganizationResource = Resource{
+		Kind:        "Organization",
+		Plural:      "organizations",
+		Singular:    "organization",
+		ShortNames:  []string{"org", "orgs"},
+		DisplayName: "organization",
+		APIVersion:  APIVersionAuth,
+		Verbs:       []string{VerbList},
+	}
+	CloudSpaceResource = Resource{
+		Kind:        "CloudSpace",
+		Plural:      "cloudspaces",
+		Singular:    "cloudspace",
+		ShortNames:  []string{"cs"},
+		DisplayName: "cloudspace",
+		Namespaced:  true,
+		APIVersion:  APIVersionDefault,
+		Verbs:       []string{VerbGet, VerbList, VerbCreate, VerbPatch, VerbDelete},
+	}
+	SpotNodePoolResource = Resource{
+		Kind:        "SpotNodePool",
+		Plural:      "spotnodepools",
+		Singular:    "spotnodepool",
+		ShortNames:  []string{"snp"},
+		DisplayName: "spot node pool",
+		Namespaced:  true,
+		APIVersion:  APIVersionDefault,
+		Verbs:       []string{VerbGet, VerbList, VerbCreate, VerbPatch, VerbDelete, VerbDeleteCollection},
+	}
+	OnDemandNodePoolResource = Resource{
+		Kind:        "OnDemandNodePool",
+		Plural:      "ondemandnodepools",
+		Singular:    "ondemandnodepool",
+		ShortNames:  []string{"odnp"},
+		DisplayName: "on demand node pool",
+		Namespaced:  true,
+		APIVersion:  APIVersionDefault,
+		Verbs:       []string{VerbGet, VerbList, VerbDelete},
+	}
+)
+
+// ItemList constrains a list type L, through its pointer, to expose its items of
+// type T. List types that the API pages should implement PagedList too.
+type ItemList[T any, L any] interface {
+	*L
+	ListItems() []T
+}
+
+func (l *RegionList) ListItems() []Region                     { return l.Items }
+func (l *ServerClassList) ListItems() []ServerClass           { return l.Items }
+func (l *OrganizationList) ListItems() []Organization         { return l.Organizations }
+func (l *CloudSpaceList) ListItems() []CloudSpace             { return l.Items }
+func (l *SpotNodePoolList) ListItems() []SpotNodePool         { return l.Items }
+func (l *OnDemandNodePoolList) ListItems() []OnDemandNodePool { return l.Items }
+
+// ResourceClient performs typed operations on one resource kind, where T is the
+// object type and L the list type
+type ResourceClient[T any, L any] struct {
+	client     Requester
+	resource   Resource
+	apiVersion APIVersion
+	items      func(*L) []T
+}
+
+// NewResourceClient creates a client for a resource, optionally overriding its API
+// version. PL is inferred from L, e.g. NewResourceClient[Region, RegionList](...).
+func NewResourceClient[T any, L any, PL ItemList[T, L]](c Requester, resource Resource, apiVersion ...APIVersion) *ResourceClient[T, L] {
+	version := resource.APIVersion
+	if len(apiVersion) > 0 && apiVersion[0] != "" {
+		version = apiVersion[0]
+	}
+	items := func(l *L) []T { return PL(l).ListItems() }
+	return &ResourceClient[T, L]{client: c, resource: resource, apiVersion: version, items: items}
+}
+
+// Resource returns the description of the resource
+func (r *ResourceClient[T, L]) Resource() Resource {
+	return r.resource
+}
+
+// Get retrieves an object by name; namespace is ignored for cluster scoped resources
+func (r *ResourceClient[T, L]) Get(ctx context.Context, namespace, name string) (*T, error) {
+	endpoint, err := r.objectEndpoint(VerbGet, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return genericGet[T](r.client, ctx, endpoint, GetOptions{Namespace: namespace, Name: name, APIVersion: r.apiVersion})
+}
+
+// List retrieves every object, following continuation across pages
+func (r *ResourceClient[T, L]) List(ctx context.Context, namespace string) (*L, error) {
+	endpoint, err := r.collectionEndpoint(VerbList, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return genericList[L](r.client, ctx, endpoint, ListOptions{Namespace: namespace, APIVersion: r.apiVersion})
+}
+
+// All returns an iterator over every object, fetching pages of opts.Limit items lazily
+func (r *ResourceClient[T, L]) All(ctx context.Context, namespace string, opts ListOptions) iter.Seq2[T, error] {
+	endpoint, err := r.collectionEndpoint(VerbList, namespace)
+	if err != nil {
+		return failedSeq[T](err)
+	}
+	opts.Namespace = namespace
+	if opts.APIVersion == "" {
+		opts.APIVersion = r.apiVersion
+	}
+	return genericListAll(r.client, ctx, endpoint, opts, r.items)
+}
+
+// Create creates an object
+func (r *ResourceClient[T, L]) Create(ctx context.Context, namespace string, object *T) (*T, error) {
+	endpoint, err := r.collectionEndpoint(VerbCreate, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCreateInput(object); err != nil {
+		return nil, fmt.Errorf("%s configuration is required", r.resource.DisplayName)
+	}
+	return genericCreate[T](r.client, ctx, endpoint, object, CreateOptions{Namespace: namespace, APIVersion: r.apiVersion})
+}
+
+// Edit applies JSON patch operations to an object
+func (r *ResourceClient[T, L]) Edit(ctx context.Context, namespace, name string, patchOps []PatchOperation) (*T, error) {
+	endpoint, err := r.objectEndpoint(VerbPatch, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePatchOperations(patchOps); err != nil {
+		return nil, err
+	}
+	return genericEdit[T](r.client, ctx, endpoint, patchOps, EditOptions{Namespace: namespace, Name: name, APIVersion: r.apiVersion})
+}
+
+// Delete deletes an object by name
+func (r *ResourceClient[T, L]) Delete(ctx context.Context, namespace, name string) (*DeleteResponse, error) {
+	endpoint, err := r.objectEndpoint(VerbDelete, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return genericDelete[DeleteResponse](r.client, ctx, endpoint, DeleteOptions{
+		Namespace:    namespace,
+		Name:         name,
+		ResourceType: r.resource.Kind,
+		APIVersion:   r.apiVersion,
+	})
+}
+
+// DeleteCollection deletes every object in a namespace
+func (r *ResourceClient[T, L]) DeleteCollection(ctx context.Context, namespace string) (*DeleteResponse, error) {
+	endpoint, err := r.collectionEndpoint(VerbDeleteCollection, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return genericDelete[DeleteResponse](r.client, ctx, endpoint, DeleteOptions{
+		Namespace:    namespace,
+		ResourceType: r.resource.Kind + "s",
+		APIVersion:   r.apiVersion,
+	})
+}
+
+// collectionEndpoint returns the path of the resource collection after checking verb is supported
+func (r *ResourceClient[T, L]) collectionEndpoint(verb, namespace string) (string, error) {
+	if !r.resource.Supports(verb) {
+		return "", fmt.Errorf("%s does not support %s", r.resource.Plural, verb)
+	}
+	if !r.resource.Namespaced {
+		return "/" + r.resource.Plural, nil
+	}
+	if err := validateNamespace(namespace); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/namespaces/%s/%s", namespace, r.resource.Plural), nil
+}
+
+// objectEndpoint returns the path of a single object after checking verb is supported
+func (r *ResourceClient[T, L]) objectEndpoint(verb, namespace, name string) (string, error) {
+	endpoint, err := r.collectionEndpoint(verb, namespace)
+	if err != nil {
+		return "", err
+	}
+	if err := validateName(name); err != nil {
+		return "", fmt.Errorf("%s name is required", r.resource.DisplayName)
+	}
+	return endpoint + "/" + name, nil
+}
+
+// Regions returns a client for regions
+func (c *Client) Regions(apiVersion ...APIVersion) *ResourceClient[Region, RegionList] {
+	return NewResourceClient[Region, RegionList](c, RegionResource, apiVersion...)
+}
+
+// ServerClasses returns a client for server classes
+func (c *Client) ServerClasses(apiVersion ...APIVersion) *ResourceClient[ServerClass, ServerClassList] {
+	return NewResourceClient[ServerClass, ServerClassList](c, ServerClassResource, apiVersion...)
+}
+
+// Organizations returns a client for organizations
+func (c *Client) Organizations(apiVersion ...APIVersion) *ResourceClient[Organization, OrganizationList] {
+	return NewResourceClient[Organization, OrganizationList](c, OrganizationResource, apiVersion...)
+}
+
+// CloudSpaces returns a client for cloudspaces
+func (c *Client) CloudSpaces(apiVersion ...APIVersion) *ResourceClient[CloudSpace, CloudSpaceList] {
+	return NewResourceClient[CloudSpace, CloudSpaceList](c, CloudSpaceResource, apiVersion...)
+}
+
+// SpotNodePools returns a client for spot node pools
+func (c *Client) SpotNodePools(apiVersion ...APIVersion) *ResourceClient[SpotNodePool, SpotNodePoolList] {
+	return NewResourceClient[SpotNodePool, SpotNodePoolList](c, SpotNodePoolResource, apiVersion...)
+}
+
+// OnDemandNodePools returns a client for on demand node pools
+func (c *Client) OnDemandNodePools(apiVersion ...APIVersion) *ResourceClient[OnDemandNodePool, OnDemandNodePoolList] {
+	return NewResourceClient[OnDemandNodePool, OnDemandNodePoolList](c, OnDemandNodePoolResource, apiVersion...)
+}
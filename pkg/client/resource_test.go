@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/config"
+)
+
+func TestResourceClient_Endpoints(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "s") {
+			w.Write([]byte(`{"items":[]}`))
+			return
+		}
+		w.Write([]byte(`{"metadata":{"name":"test"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{RefreshToken: "test-token", BaseURL: server.URL, Timeout: 30})
+	client.tokenManager = &MockTokenManager{accessToken: "mock-access-token"}
+	ctx := context.Background()
+
+	if _, err := client.ServerClasses().Get(ctx, "ignored", "gp.vs1.medium-dfw"); err != nil {
+		t.Fatalf("Get server class failed: %v", err)
+	}
+	if _, err := client.SpotNodePools().List(ctx, "org-abc123"); err != nil {
+		t.Fatalf("List spot node pools failed: %v", err)
+	}
+	if _, err := client.CloudSpaces().Delete(ctx, "org-abc123", "my-cloudspace"); err != nil {
+		t.Fatalf("Delete cloudspace failed: %v", err)
+	}
+
+	expected := []string{
+		"GET /ngpc.rxt.io/v1/serverclasses/gp.vs1.medium-dfw",
+		"GET /ngpc.rxt.io/v1/namespaces/org-abc123/spotnodepools",
+		"DELETE /ngpc.rxt.io/v1/namespaces/org-abc123/cloudspaces/my-cloudspace",
+	}
+	if strings.Join(paths, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected requests:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(paths, "\n"))
+	}
+}
+
+func TestResourceClient_Validation(t *testing.T) {
+	client := NewClient(&config.Config{RefreshToken: "test-token", BaseURL: "http://127.0.0.1:0", Timeout: 30})
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr string
+	}{
+		{
+			name: "unsupported verb",
+			call: func() error {
+				_, err := client.Organizations().Get(ctx, "", "org-abc123")
+				return err
+			},
+			wantErr: "organizations does not support get",
+		},
+		{
+			name: "missing namespace",
+			call: func() error {
+				_, err := client.CloudSpaces().List(ctx, "")
+				return err
+			},
+			wantErr: "namespace",
+		},
+		{
+			name: "missing name",
+			call: func() error {
+				_, err := client.SpotNodePools().Get(ctx, "org-abc123", "")
+				return err
+			},
+			wantErr: "spot node pool name is required",
+		},
+		{
+			name: "missing object",
+			call: func() error {
+				_, err := client.CloudSpaces().Create(ctx, "org-abc123", nil)
+				return err
+			},
+			wantErr: "cloudspace configuration is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestResourceClient_APIVersionOverride(t *testing.T) {
+	rc := NewResourceClient[Region, RegionList](nil, RegionResource, "")
+	if rc.apiVersion != RegionResource.APIVersion {
+		t.Errorf("Expected empty override to keep %s, got %s", RegionResource.APIVersion, rc.apiVersion)
+	}
+	rc = NewResourceClient[Region, RegionList](nil, RegionResource, APIVersionAuth)
+	if rc.apiVersion != APIVersionAuth {
+		t.Errorf("Expected override %s, got %s", APIVersionAuth, rc.apiVersion)
+	}
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// doWithRetry sends req, resending idempotent requests that fail with a connection
+// error or a retryable status while the retry policy allows
+func (c *Client) doWithRetry(req *http.Request) (*http.Response, error) {
+	backoff := c.retry.Backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.httpClient.Do(req.WithContext(withAttempt(req.Context(), attempt+1)))
+		if attempt >= c.retry.MaxRetries || !isIdempotent(req.Method) || !shouldRetry(resp, err) {
+			return resp, err
+		}
+		// A canceled or expired context fails every attempt, so stop at once
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait := backoff
+		attrs := []any{"method", req.Method, "url", req.URL.String(), "attempt", attempt + 1}
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			attrs = append(attrs, "status", resp.StatusCode)
+		} else {
+			attrs = append(attrs, "error", err)
+		}
+		c.log().Info("Retrying request", append(attrs, "wait", wait)...)
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// isIdempotent reports whether a request with method can safely be sent again
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether a response or error is worth retrying
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds
+func parseRetryAfter(value string) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
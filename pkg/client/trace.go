@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/cassette"
+)
+
+// maxTraceBody is the number of body bytes logged by --http-trace
+const maxTraceBody = 4096
+
+// attemptKey carries the attempt number of a request through its context
+type attemptKey struct{}
+
+// withAttempt records that ctx belongs to the given attempt (1 for the first) of a request
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// traceTransport logs every request and response at debug level, with
+// credentials redacted
+type traceTransport struct {
+	next   http.RoundTripper
+	logger func() *slog.Logger
+}
+
+func (t traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := t.logger()
+	attrs := []any{"method", req.Method, "url", req.URL.String()}
+	if attempt, ok := req.Context().Value(attemptKey{}).(int); ok {
+		attrs = append(attrs, "attempt", attempt)
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		attrs = append(attrs, "request_body", traceBody(body, req.Header.Get("Content-Type")))
+	}
+	attrs = append(attrs, "request_headers", cassette.RedactHeaders(req.Header))
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	attrs = append(attrs, "latency", time.Since(start))
+	if err != nil {
+		logger.Debug("HTTP request failed", append(attrs, "error", err)...)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		logger.Debug("HTTP response unreadable", append(attrs, "status", resp.StatusCode, "error", err)...)
+		return resp, nil
+	}
+	attrs = append(attrs, "status", resp.StatusCode, "response_body", traceBody(body, resp.Header.Get("Content-Type")))
+	logger.Debug("HTTP request", attrs...)
+	return resp, nil
+}
+
+// traceBody redacts and truncates a body for logging
+func traceBody(body []byte, contentType string) string {
+	redacted := string(cassette.RedactBody(body, contentType))
+	if len(redacted) > maxTraceBody {
+		return redacted[:maxTraceBody] + "...(truncated)"
+	}
+	return redacted
+}
+
+// withTrace returns a copy of httpClient whose requests are logged by --http-trace
+func withTrace(httpClient *http.Client, logger func() *slog.Logger) *http.Client {
+	wrapped := *httpClient
+	next := httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	wrapped.Transport = traceTransport{next: next, logger: logger}
+	return &wrapped
+}
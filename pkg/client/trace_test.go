@@ -0,0 +1,78 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/config"
+)
+
+func TestNewClient_HTTPTrace(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[],"id_token":"leaked-token"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(&config.Config{BaseURL: server.URL, Timeout: 30, HTTPTrace: true},
+		WithTokenSource(StaticTokenSource("secret-token")),
+		WithRetry(1, 0),
+		WithLogger(logger),
+	)
+
+	if _, err := client.ListRegions(context.Background()); err != nil {
+		t.Fatalf("ListRegions failed: %v", err)
+	}
+
+	if strings.Contains(logs.String(), "secret-token") || strings.Contains(logs.String(), "leaked-token") {
+		t.Errorf("Logs contain a credential:\n%s", logs.String())
+	}
+
+	var traces, retries []map[string]any
+	scanner := bufio.NewScanner(&logs)
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Invalid log record %q: %v", scanner.Text(), err)
+		}
+		switch record["msg"] {
+		case "HTTP request":
+			traces = append(traces, record)
+		case "Retrying request":
+			retries = append(retries, record)
+		}
+	}
+
+	if len(traces) != 2 {
+		t.Fatalf("Expected 2 traced requests, got %d", len(traces))
+	}
+	for i, status := range []float64{http.StatusServiceUnavailable, http.StatusOK} {
+		if traces[i]["status"] != status || traces[i]["attempt"] != float64(i+1) || traces[i]["method"] != http.MethodGet {
+			t.Errorf("Unexpected trace %d: %v", i, traces[i])
+		}
+		if _, ok := traces[i]["latency"]; !ok {
+			t.Errorf("Trace %d has no latency", i)
+		}
+	}
+	if !strings.Contains(traces[1]["response_body"].(string), `"id_token":"REDACTED"`) {
+		t.Errorf("Expected a redacted response body, got %v", traces[1]["response_body"])
+	}
+	if len(retries) != 1 || retries[0]["status"] != float64(http.StatusServiceUnavailable) {
+		t.Errorf("Expected one logged retry after a 503, got %v", retries)
+	}
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/config"
+)
+
+func TestNewClient_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	writePEM(t, caFile, "CERTIFICATE", server.Certificate().Raw)
+
+	tests := []struct {
+		name    string
+		config  config.Config
+		wantErr bool
+	}{
+		{name: "untrusted server certificate", wantErr: true},
+		{name: "CA file", config: config.Config{CAFile: caFile}},
+		{name: "insecure skip verify", config: config.Config{InsecureSkipTLSVerify: true}},
+		{name: "missing CA file", config: config.Config{CAFile: filepath.Join(t.TempDir(), "missing.pem")}, wantErr: true},
+		{name: "client certificate without key", config: config.Config{InsecureSkipTLSVerify: true, ClientCert: caFile}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.BaseURL, cfg.Timeout = server.URL, 30
+			client := NewClient(&cfg, WithTokenSource(StaticTokenSource("token")))
+			_, err := client.ListRegions(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ListRegions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClient_ClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "spotctl-test" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	writeClientCertificate(t, certFile, keyFile)
+
+	cfg := &config.Config{BaseURL: server.URL, Timeout: 30, InsecureSkipTLSVerify: true, ClientCert: certFile, ClientKey: keyFile}
+	if _, err := NewClient(cfg, WithTokenSource(StaticTokenSource("token"))).ListRegions(context.Background()); err != nil {
+		t.Errorf("Expected the client certificate to be presented: %v", err)
+	}
+
+	cfg.ClientCert, cfg.ClientKey = "", ""
+	if _, err := NewClient(cfg, WithTokenSource(StaticTokenSource("token"))).ListRegions(context.Background()); err == nil {
+		t.Error("Expected the handshake to fail without a client certificate")
+	}
+}
+
+func TestNewClient_ProxyURL(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer proxy.Close()
+
+	cfg := &config.Config{BaseURL: "http://spot.example.invalid/apis", Timeout: 30, ProxyURL: proxy.URL}
+	if _, err := NewClient(cfg, WithTokenSource(StaticTokenSource("token"))).ListRegions(context.Background()); err != nil {
+		t.Fatalf("ListRegions failed: %v", err)
+	}
+	if proxied != "http://spot.example.invalid/apis/ngpc.rxt.io/v1/regions" {
+		t.Errorf("Expected the request to go through the proxy, got %q", proxied)
+	}
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeClientCertificate writes a self-signed client certificate and its key
+func writeClientCertificate(t *testing.T, certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "spotctl-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+}
@@ -359,3 +359,22 @@ type SpotNodePoolList struct {
 	Kind       string         `json:"kind,omitempty"`
 	Metadata   ListMeta       `json:"metadata,omitempty"`
 }
+
+// PriceHistory represents the auction price history of a server class
+type PriceHistory struct {
+	ServerClass string       `json:"serverClass,omitempty"`
+	History     []PricePoint `json:"history"`
+}
+
+// PricePoint is the hammer price of a single auction
+type PricePoint struct {
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Price     string     `json:"price"`
+}
+
+// PercentileInfo represents the distribution of recent hammer prices of a server class.
+// Percentiles maps a percentile such as "50" or "90" to the hourly price at that percentile.
+type PercentileInfo struct {
+	ServerClass string            `json:"serverClass,omitempty"`
+	Percentiles map[string]string `json:"percentiles"`
+}
@@ -0,0 +1,196 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/spf13/cobra"
+)
+
+// AllNamespacesParallelism is how many namespaces --all-namespaces lists at once
+const AllNamespacesParallelism = 4
+
+// AddAllNamespacesFlag adds the -A/--all-namespaces flag to a list command
+func AddAllNamespacesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP("all-namespaces", "A", false, "List across the namespaces of all your organizations")
+}
+
+// AllNamespaces reports whether --all-namespaces was given, rejecting it together
+// with --namespace or --org
+func AllNamespaces(cmd *cobra.Command) (bool, error) {
+	allNamespaces, _ := cmd.Flags().GetBool("all-namespaces")
+	if !allNamespaces {
+		return false, nil
+	}
+	for _, name := range []string{"namespace", "org"} {
+		if flag := cmd.Flag(name); flag != nil && flag.Changed {
+			return false, fmt.Errorf("--all-namespaces cannot be used with --%s", name)
+		}
+	}
+	return true, nil
+}
+
+// NamespaceItems is what was listed in the namespace of one organization
+type NamespaceItems[T any] struct {
+	Org       string
+	Namespace string
+	Items     []T
+	Err       error
+}
+
+// ListAllNamespaces calls list for the namespace of every organization, at most
+// AllNamespacesParallelism at once. Results are in organization order; a failed
+// namespace is returned with its error rather than failing the whole listing.
+func ListAllNamespaces[T any](ctx context.Context, cfg *config.Config, list func(ctx context.Context, namespace string) ([]T, error)) ([]NamespaceItems[T], error) {
+	orgs, err := client.NewClient(cfg).ListOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	var results []NamespaceItems[T]
+	seen := map[string]bool{}
+	for _, org := range orgs.Organizations {
+		namespace := org.Metadata.Namespace
+		if namespace == "" || seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		results = append(results, NamespaceItems[T]{Org: org.Name, Namespace: namespace})
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, AllNamespacesParallelism)
+	for i := range results {
+		wg.Add(1)
+		go func(r *NamespaceItems[T]) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			r.Items, r.Err = list(ctx, r.Namespace)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// ReportNamespaceErrors writes the namespaces that failed to list to w, returning
+// an error if any did so the command exits non-zero after printing the rest
+func ReportNamespaceErrors[T any](w io.Writer, results []NamespaceItems[T], what string) error {
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(w, "Error: failed to list %s in namespace %s (organization %s): %v\n", what, r.Namespace, r.Org, r.Err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to list %s in %d of %d namespaces", what, failed, len(results))
+	}
+	return nil
+}
+
+// ListKindAllNamespaces lists objects of a kind in the namespace of every
+// organization and writes up to limit of them in format. Tables gain an ORG
+// column and -o name prefixes each name with its namespace, so that delete -f
+// finds it again; other formats print the objects unchanged. Namespaces that failed to
+// list are reported after the rest.
+func ListKindAllNamespaces(cmd *cobra.Command, kind registry.Kind, opts client.ListOptions, limit int, format string) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	apiClient := client.NewClient(cfg)
+
+	results, err := ListAllNamespaces(cmd.Context(), cfg, func(ctx context.Context, namespace string) ([]any, error) {
+		list, err := kind.List(ctx, apiClient, namespace, opts, limit)
+		return sliceItems(list), err
+	})
+	if err != nil {
+		return err
+	}
+
+	objects := []any{}
+	var rows []orgRow
+	for _, result := range results {
+		for _, item := range result.Items {
+			objects = append(objects, item)
+			rows = append(rows, orgRow{Org: result.Org, Object: item})
+		}
+	}
+	if limit > 0 && len(objects) > limit {
+		objects, rows = objects[:limit], rows[:limit]
+	}
+
+	if err := outputAllNamespaces(kind, objects, rows, format); err != nil {
+		return err
+	}
+	return ReportNamespaceErrors(cmd.ErrOrStderr(), results, registry.DisplayPlural(kind))
+}
+
+// orgRow is an object listed with --all-namespaces, with the organization it
+// belongs to for the ORG column
+type orgRow struct {
+	Org    string
+	Object any
+}
+
+// outputAllNamespaces writes objects listed across namespaces, as rows with an
+// ORG column in tables
+func outputAllNamespaces(kind registry.Kind, objects []any, rows []orgRow, format string) error {
+	switch output.OutputFormat(format) {
+	case output.TableFormat, output.WideFormat:
+		if len(rows) == 0 {
+			fmt.Printf("No %s found in any namespace\n", registry.DisplayPlural(kind))
+			return nil
+		}
+		fallthrough
+	case output.CSVFormat:
+		return registry.NewFormatter(kind, output.OutputOptions{Format: output.OutputFormat(format)}).Output(rows, withOrgColumn(kind.TableConfig()))
+	default:
+		options := output.OutputOptions{Format: output.OutputFormat(format), Namespaced: true}
+		return registry.NewFormatter(kind, options).Output(objects, kind.TableConfig())
+	}
+}
+
+// withOrgColumn returns a copy of a table configuration for orgRows, with an ORG
+// column after NAMESPACE
+func withOrgColumn(config *output.TableConfig) *output.TableConfig {
+	withOrg := &output.TableConfig{}
+	for _, col := range config.Columns {
+		withOrg.Columns = append(withOrg.Columns, objectColumn(col))
+		if col.Header == "NAMESPACE" {
+			withOrg.Columns = append(withOrg.Columns, output.TableColumn{Header: "ORG", Field: "Org"})
+		}
+	}
+	for _, col := range config.DetailCols {
+		withOrg.DetailCols = append(withOrg.DetailCols, objectColumn(col))
+	}
+	return withOrg
+}
+
+// objectColumn reads a column from the object of an orgRow
+func objectColumn(col output.TableColumn) output.TableColumn {
+	col.Field = "Object." + col.Field
+	return col
+}
+
+// sliceItems returns the elements of a slice returned by Kind.List
+func sliceItems(list any) []any {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	items := make([]any, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items
+}
@@ -0,0 +1,169 @@
+package cmdutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+	"github.com/spf13/viper"
+)
+
+func TestAllNamespaces(t *testing.T) {
+	cmd := newTestCommand()
+	AddAllNamespacesFlag(cmd)
+	cmd.ParseFlags([]string{"-A"})
+	if all, err := AllNamespaces(cmd); !all || err != nil {
+		t.Errorf("Expected -A to be accepted, got %v, %v", all, err)
+	}
+
+	cmd = newTestCommand()
+	AddAllNamespacesFlag(cmd)
+	cmd.ParseFlags([]string{"-A", "--org", "acme"})
+	if _, err := AllNamespaces(cmd); err == nil {
+		t.Error("Expected -A to be rejected with --org")
+	}
+}
+
+func TestListAllNamespaces(t *testing.T) {
+	setupStandIn(t)
+	cfg, err := config.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ListAllNamespaces(context.Background(), cfg, func(ctx context.Context, namespace string) ([]string, error) {
+		if namespace == "org-def456" {
+			return nil, errors.New("forbidden")
+		}
+		return []string{namespace + "/pool-a", namespace + "/pool-b"}, nil
+	})
+	if err != nil {
+		t.Fatalf("ListAllNamespaces failed: %v", err)
+	}
+	// The fake API lists its own organization as well as those seeded
+	if len(results) != 3 || results[0].Org != "acme" || len(results[0].Items) != 2 || results[1].Org != "globex" || results[1].Err == nil {
+		t.Fatalf("Unexpected results %+v", results)
+	}
+
+	var stderr bytes.Buffer
+	err = ReportNamespaceErrors(&stderr, results, "pools")
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 namespaces") {
+		t.Errorf("Expected an error for the failed namespace, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "namespace org-def456 (organization globex): forbidden") {
+		t.Errorf("Expected the failure to be reported, got %q", stderr.String())
+	}
+}
+
+func TestWithOrgColumn(t *testing.T) {
+	config := &output.TableConfig{Columns: []output.TableColumn{{Header: "NAME"}, {Header: "NAMESPACE"}, {Header: "PHASE"}}}
+	var headers []string
+	for _, col := range withOrgColumn(config).Columns {
+		headers = append(headers, col.Header)
+	}
+	if got := strings.Join(headers, " "); got != "NAME NAMESPACE ORG PHASE" {
+		t.Errorf("Unexpected columns %s", got)
+	}
+	if len(config.Columns) != 3 {
+		t.Error("Expected the original configuration to be unchanged")
+	}
+}
+
+func TestListKindAllNamespaces(t *testing.T) {
+	server := spottest.NewTestServer(t)
+	server.Configure(t)
+	viper.Set("no-pager", true)
+
+	pool := func(namespace, name string) client.SpotNodePool {
+		return client.SpotNodePool{Metadata: client.ObjectMeta{Name: name, Namespace: namespace}, Spec: client.SpotNodePoolSpec{ServerClass: "gp.vs1.medium-dfw"}}
+	}
+	if err := server.Seed(
+		client.Organization{ID: "org_acme", Name: "acme", Metadata: client.OrganizationMetadata{Namespace: "org-acme"}},
+		client.Organization{ID: "org_globex", Name: "globex", Metadata: client.OrganizationMetadata{Namespace: "org-globex"}},
+		pool("org-acme", "pool-a"),
+		pool(spottest.DefaultNamespace, "pool-b"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	server.InjectFault(spottest.Fault{Path: "/ngpc.rxt.io/v1/namespaces/org-globex/", Status: http.StatusForbidden})
+
+	list := func(format string, limit int) (string, string, error) {
+		cmd := newTestCommand()
+		cmd.SetContext(context.Background())
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+		var err error
+		stdout := captureStdout(t, func() {
+			err = ListKindAllNamespaces(cmd, registry.SpotNodePools, client.ListOptions{}, limit, format)
+		})
+		return stdout, stderr.String(), err
+	}
+
+	stdout, stderr, err := list("table", 0)
+	if err == nil || !strings.Contains(err.Error(), "failed to list spot node pools in 1 of 3 namespaces") {
+		t.Errorf("Expected the failed namespace to fail the command, got %v", err)
+	}
+	if !strings.Contains(stderr, "namespace org-globex (organization globex)") {
+		t.Errorf("Expected the failed namespace to be reported, got %q", stderr)
+	}
+	for _, row := range []string{"pool-a +org-acme +acme +gp.vs1.medium-dfw", "pool-b +org-spottest +spottest +gp.vs1.medium-dfw"} {
+		if !regexp.MustCompile(row).MatchString(stdout) {
+			t.Errorf("Expected a row matching %q, got:\n%s", row, stdout)
+		}
+	}
+	if !strings.Contains(stdout, "ORG") {
+		t.Errorf("Expected an ORG column, got:\n%s", stdout)
+	}
+
+	// Other formats print the objects themselves, up to the limit in all
+	stdout, _, _ = list("json", 1)
+	var pools []client.SpotNodePool
+	if err := json.Unmarshal([]byte(stdout), &pools); err != nil {
+		t.Fatalf("Expected a JSON list, got %q: %v", stdout, err)
+	}
+	if len(pools) != 1 || pools[0].Metadata.Name != "pool-a" {
+		t.Errorf("Expected only pool-a, got %+v", pools)
+	}
+
+	// Names carry their namespace, which delete -f reads back
+	stdout, _, _ = list("name", 0)
+	if expected := "org-acme/spotnodepool/pool-a\norg-spottest/spotnodepool/pool-b\n"; stdout != expected {
+		t.Errorf("Expected namespaced names %q, got %q", expected, stdout)
+	}
+	targets, err := ReadTargets(strings.NewReader(stdout))
+	if err != nil || targetStrings(targets) != "spotnodepool/pool-a@org-acme spotnodepool/pool-b@org-spottest" {
+		t.Errorf("Expected the names to be read back with their namespaces, got %q, %v", targetStrings(targets), err)
+	}
+}
+
+// captureStdout returns what fn writes to standard output
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	fn()
+	os.Stdout = stdout
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
@@ -0,0 +1,174 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/spf13/cobra"
+)
+
+// What deleting a cloudspace does with its node pools
+const (
+	// CascadeBackground deletes the node pools, then the cloudspaces, without waiting
+	CascadeBackground = "background"
+	// CascadeForeground deletes the node pools and waits for them to be removed,
+	// then deletes the cloudspaces and waits for them too
+	CascadeForeground = "foreground"
+	// CascadeOrphan deletes only the cloudspaces, leaving their node pools running
+	CascadeOrphan = "orphan"
+)
+
+// cascadeModes lists the --cascade values, for completion
+var cascadeModes = []string{CascadeBackground, CascadeForeground, CascadeOrphan}
+
+// AddCascadeFlag adds the --cascade flag to a command that deletes cloudspaces.
+// It has no default: deleting a cloudspace that has node pools requires it, so
+// that scripts written before it existed do not start deleting node pools.
+func AddCascadeFlag(cmd *cobra.Command) {
+	cmd.Flags().String("cascade", "", `What to do with the node pools of deleted cloudspaces: "background", "foreground" or "orphan"; required if they have any`)
+	_ = cmd.RegisterFlagCompletionFunc("cascade", cobra.FixedCompletions(cascadeModes, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// CascadeMode returns the --cascade value, or "" if it was not given, rejecting
+// unknown ones
+func CascadeMode(cmd *cobra.Command) (string, error) {
+	value, _ := cmd.Flags().GetString("cascade")
+	switch value {
+	case "", CascadeBackground, CascadeForeground, CascadeOrphan:
+		return value, nil
+	default:
+		return "", fmt.Errorf("invalid --cascade value %q: must be \"background\", \"foreground\" or \"orphan\"", value)
+	}
+}
+
+// Cascade deletes targets after the node pools of any cloudspaces among them, so
+// that no node pool is left without its cloudspace
+type Cascade struct {
+	Mode string
+	// Pools are deleted first, including any pools named in the targets
+	Pools []Target
+	// Targets are deleted once the pools are
+	Targets []Target
+}
+
+// PlanCascade finds the spot and on-demand node pools of the cloudspaces among
+// targets. With CascadeOrphan the pools are only warned about on errOut, and
+// failing to find them does not stop the cloudspaces being deleted. Without a
+// mode, finding any pools is an error naming them.
+func PlanCascade(ctx context.Context, apiClient client.Interface, targets []Target, mode string, errOut io.Writer) (*Cascade, error) {
+	var cloudSpaces []Target
+	for _, t := range targets {
+		if t.Kind == registry.CloudSpaces {
+			cloudSpaces = append(cloudSpaces, t)
+		}
+	}
+	if len(cloudSpaces) == 0 {
+		return &Cascade{Mode: mode, Targets: targets}, nil
+	}
+
+	pools, err := findNodePools(ctx, apiClient, cloudSpaces)
+	if mode == CascadeOrphan {
+		if len(pools) > 0 {
+			fmt.Fprintf(errOut, "Warning: %d node pools will be left without their cloudspace and keep running: %s\n", len(pools), DescribeTargets(pools))
+		}
+		return &Cascade{Mode: mode, Targets: targets}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the node pools of the cloudspaces: %w (use --cascade=orphan to delete only the cloudspaces)", err)
+	}
+	if mode == "" {
+		if len(pools) > 0 {
+			return nil, fmt.Errorf("the cloudspaces have %d node pools: %s; use --cascade=background or --cascade=foreground to delete them too, or --cascade=orphan to leave them running", len(pools), DescribeTargets(pools))
+		}
+		return &Cascade{Mode: mode, Targets: targets}, nil
+	}
+
+	// Pools that were also named are deleted with the others, ahead of the cloudspaces
+	pooled := map[string]bool{}
+	for _, pool := range pools {
+		pooled[targetKey(pool)] = true
+	}
+	cascade := &Cascade{Mode: mode, Pools: pools}
+	for _, t := range targets {
+		if !pooled[targetKey(t)] {
+			cascade.Targets = append(cascade.Targets, t)
+		}
+	}
+	return cascade, nil
+}
+
+// All returns everything the cascade deletes, in order, e.g. for confirmation
+func (c *Cascade) All() []Target {
+	return append(c.Pools[:len(c.Pools):len(c.Pools)], c.Targets...)
+}
+
+// Delete deletes the pools, then the targets, reporting each as DeleteTargets
+// does. The targets are kept if any pool could not be deleted. In the foreground
+// mode it waits for each group to be removed before going on.
+func (c *Cascade) Delete(ctx context.Context, apiClient client.Interface, out, errOut io.Writer, dryRun bool) error {
+	wait := c.Mode == CascadeForeground && !dryRun
+	if len(c.Pools) > 0 {
+		if err := DeleteTargets(ctx, apiClient, c.Pools, out, errOut, dryRun); err != nil {
+			return fmt.Errorf("%w; the cloudspaces were not deleted so that no node pools are left without one", err)
+		}
+		if wait {
+			if err := WaitForDeletion(ctx, apiClient, c.Pools, out); err != nil {
+				return err
+			}
+		}
+	}
+	if len(c.Targets) == 0 {
+		return nil
+	}
+
+	if err := DeleteTargets(ctx, apiClient, c.Targets, out, errOut, dryRun); err != nil {
+		return err
+	}
+	if wait {
+		return WaitForDeletion(ctx, apiClient, c.Targets, out)
+	}
+	return nil
+}
+
+// findNodePools returns the spot and on-demand node pools whose spec.cloudSpace
+// names one of the cloudspaces, spot node pools first
+func findNodePools(ctx context.Context, apiClient client.Interface, cloudSpaces []Target) ([]Target, error) {
+	byNamespace := map[string]map[string]bool{}
+	var namespaces []string
+	for _, t := range cloudSpaces {
+		if byNamespace[t.Namespace] == nil {
+			byNamespace[t.Namespace] = map[string]bool{}
+			namespaces = append(namespaces, t.Namespace)
+		}
+		byNamespace[t.Namespace][t.Name] = true
+	}
+
+	var spotPools, onDemandPools []Target
+	for _, namespace := range namespaces {
+		names := byNamespace[namespace]
+
+		spotNodePools, err := apiClient.ListSpotNodePools(ctx, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list spot node pools in namespace '%s': %w", namespace, err)
+		}
+		for _, pool := range spotNodePools.Items {
+			if names[pool.Spec.CloudSpace] {
+				spotPools = append(spotPools, Target{Kind: registry.SpotNodePools, Namespace: namespace, Name: pool.Metadata.Name})
+			}
+		}
+
+		onDemandNodePools, err := apiClient.ListOnDemandNodePools(ctx, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list on-demand node pools in namespace '%s': %w", namespace, err)
+		}
+		for _, pool := range onDemandNodePools.Items {
+			if names[pool.Spec.CloudSpace] {
+				onDemandPools = append(onDemandPools, Target{Kind: registry.OnDemandNodePools, Namespace: namespace, Name: pool.Metadata.Name})
+			}
+		}
+	}
+	return append(spotPools, onDemandPools...), nil
+}
@@ -0,0 +1,103 @@
+package cmdutil
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+)
+
+func TestCascade(t *testing.T) {
+	setup := func(t *testing.T) *client.Client {
+		t.Helper()
+		server := spottest.NewTestServer(t)
+		meta := func(name string) client.ObjectMeta {
+			return client.ObjectMeta{Name: name, Namespace: spottest.DefaultNamespace}
+		}
+		if err := server.Seed(
+			client.CloudSpace{Metadata: meta("web")},
+			client.CloudSpace{Metadata: meta("idle")},
+			client.SpotNodePool{Metadata: meta("web-spot"), Spec: client.SpotNodePoolSpec{CloudSpace: "web"}},
+			client.SpotNodePool{Metadata: meta("api-spot"), Spec: client.SpotNodePoolSpec{CloudSpace: "api"}},
+			client.OnDemandNodePool{Metadata: meta("web-ondemand"), Spec: client.OnDemandNodePoolSpec{CloudSpace: "web"}},
+		); err != nil {
+			t.Fatal(err)
+		}
+		return client.NewClient(server.Config())
+	}
+	target := func(kind registry.Kind, name string) Target {
+		return Target{Kind: kind, Namespace: spottest.DefaultNamespace, Name: name}
+	}
+	// A pool named alongside its cloudspace is deleted once, with the other pools
+	targets := []Target{target(registry.SpotNodePools, "web-spot"), target(registry.CloudSpaces, "web")}
+
+	t.Run("background", func(t *testing.T) {
+		apiClient := setup(t)
+		cascade, err := PlanCascade(context.Background(), apiClient, targets, CascadeBackground, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("PlanCascade failed: %v", err)
+		}
+		if got := DescribeTargets(cascade.All()); got != "spotnodepool/web-spot, ondemandnodepool/web-ondemand, cloudspace/web" {
+			t.Errorf("Expected the pools before the cloudspace, got %s", got)
+		}
+
+		var out bytes.Buffer
+		if err := cascade.Delete(context.Background(), apiClient, &out, &out, false); err != nil {
+			t.Fatalf("Delete failed: %v\n%s", err, out.String())
+		}
+		for _, tgt := range cascade.All() {
+			if _, err := tgt.Kind.Get(context.Background(), apiClient, tgt.Namespace, tgt.Name); !client.IsNotFound(err) {
+				t.Errorf("Expected %s to be deleted, got %v", tgt, err)
+			}
+		}
+		if _, err := apiClient.GetSpotNodePool(context.Background(), spottest.DefaultNamespace, "api-spot"); err != nil {
+			t.Errorf("Expected the pool of another cloudspace to be kept, got %v", err)
+		}
+	})
+
+	t.Run("orphan", func(t *testing.T) {
+		apiClient := setup(t)
+		var errOut bytes.Buffer
+		cascade, err := PlanCascade(context.Background(), apiClient, targets[1:], CascadeOrphan, &errOut)
+		if err != nil {
+			t.Fatalf("PlanCascade failed: %v", err)
+		}
+		if got := DescribeTargets(cascade.All()); got != "cloudspace/web" {
+			t.Errorf("Expected only the cloudspace, got %s", got)
+		}
+		if !strings.Contains(errOut.String(), "2 node pools will be left without their cloudspace") {
+			t.Errorf("Expected a warning about the pools, got %q", errOut.String())
+		}
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		apiClient := setup(t)
+		if _, err := PlanCascade(context.Background(), apiClient, targets[1:], "", &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "spotnodepool/web-spot, ondemandnodepool/web-ondemand; use --cascade") {
+			t.Errorf("Expected an error naming the pools, got %v", err)
+		}
+
+		// A cloudspace without node pools is deleted as before
+		cascade, err := PlanCascade(context.Background(), apiClient, []Target{target(registry.CloudSpaces, "idle")}, "", &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("PlanCascade failed: %v", err)
+		}
+		if got := DescribeTargets(cascade.All()); got != "cloudspace/idle" {
+			t.Errorf("Expected only the cloudspace, got %s", got)
+		}
+	})
+
+	t.Run("no cloudspaces", func(t *testing.T) {
+		apiClient := setup(t)
+		cascade, err := PlanCascade(context.Background(), apiClient, targets[:1], CascadeBackground, &bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("PlanCascade failed: %v", err)
+		}
+		if len(cascade.Pools) != 0 || DescribeTargets(cascade.Targets) != "spotnodepool/web-spot" {
+			t.Errorf("Expected the targets unchanged, got %+v", cascade)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package cmdutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Confirm asks message as a yes/no question on the command's stderr, so that
+// output such as -o json on stdout stays parseable, and reads the answer from its
+// stdin. Anything but y or yes declines.
+func Confirm(cmd *cobra.Command, message string) bool {
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s (y/N): ", message)
+	var response string
+	fmt.Fscanln(cmd.InOrStdin(), &response)
+	response = strings.ToLower(response)
+	return response == "y" || response == "yes"
+}
+
+// RequireConfirmForStdin fails unless --confirm or a dry run is given when
+// --filename reads stdin, whose targets cannot be confirmed interactively as the
+// prompt reads stdin too
+func RequireConfirmForStdin(cmd *cobra.Command, confirm, dryRun bool) error {
+	if !confirm && !dryRun && ReadsStdin(cmd) {
+		return fmt.Errorf("--confirm is required when reading objects from stdin, as the confirmation prompt reads stdin too")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package cmdutil
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+)
+
+// Context returns the command's context, which is canceled on Ctrl-C or when
+// --timeout expires, or context.Background() for a command that was not executed
+func Context(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
@@ -0,0 +1,97 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// DeleteParallelism is how many objects DeleteTargets deletes at once
+const DeleteParallelism = 4
+
+// ConfirmDeleteMessage returns the question to ask before deleting targets
+func ConfirmDeleteMessage(targets []Target) string {
+	if len(targets) == 1 {
+		t := targets[0]
+		return fmt.Sprintf("Are you sure you want to delete %s '%s'%s?", t.Kind.Resource().DisplayName, t.Name, inNamespace(t))
+	}
+	return fmt.Sprintf("Are you sure you want to delete %d objects (%s)?", len(targets), DescribeTargets(targets))
+}
+
+// DeleteTargets deletes targets, at most DeleteParallelism at once, and reports
+// each to out, or to errOut if it failed, as soon as it is done. Deleting several
+// targets ends with a summary and fails if any of them could not be deleted.
+func DeleteTargets(ctx context.Context, apiClient client.Interface, targets []Target, out, errOut io.Writer, dryRun bool) error {
+	// A single target fails like any other command
+	if len(targets) == 1 {
+		t := targets[0]
+		response, err := t.Kind.Delete(ctx, apiClient, t.Namespace, t.Name)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s '%s': %w", t.Kind.Resource().DisplayName, t.Name, err)
+		}
+		reportDeleted(out, t, response, dryRun)
+		return nil
+	}
+
+	var mu sync.Mutex
+	failed := 0
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, DeleteParallelism)
+	for _, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			response, err := t.Kind.Delete(ctx, apiClient, t.Namespace, t.Name)
+
+			// Reports are written one at a time so their lines do not interleave
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(errOut, "Error: failed to delete %s '%s'%s: %v\n", t.Kind.Resource().DisplayName, t.Name, inNamespace(t), err)
+				failed++
+				return
+			}
+			reportDeleted(out, t, response, dryRun)
+		}()
+	}
+	wg.Wait()
+
+	fmt.Fprintf(out, "%d deleted, %d failed%s\n", len(targets)-failed, failed, DryRunSuffix(dryRun))
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d objects", failed, len(targets))
+	}
+	return nil
+}
+
+// reportDeleted writes the outcome of deleting a target
+func reportDeleted(out io.Writer, t Target, response *client.DeleteResponse, dryRun bool) {
+	if status := response.Status; status == "Success" || status == "" {
+		fmt.Fprintf(out, "%s '%s' deleted successfully%s%s\n", t.Kind.Resource().Kind, t.Name, fromNamespace(t), DryRunSuffix(dryRun))
+		return
+	}
+	fmt.Fprintf(out, "%s '%s': delete operation completed with status: %s%s\n", t.Kind.Resource().Kind, t.Name, response.Status, DryRunSuffix(dryRun))
+	if response.Message != "" {
+		fmt.Fprintf(out, "Message: %s\n", response.Message)
+	}
+}
+
+// inNamespace describes the namespace of a target in a question or error
+func inNamespace(t Target) string {
+	if t.Namespace == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in namespace '%s'", t.Namespace)
+}
+
+// fromNamespace describes the namespace of a deleted target
+func fromNamespace(t Target) string {
+	if t.Namespace == "" {
+		return ""
+	}
+	return fmt.Sprintf(" from namespace '%s'", t.Namespace)
+}
@@ -0,0 +1,35 @@
+package cmdutil
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// AddDryRunFlag adds the --dry-run flag to commands that create, change or delete resources
+func AddDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().String("dry-run", "none", `Must be "none" or "server". With "server" the API validates the request without persisting it`)
+}
+
+// DryRun reports whether --dry-run=server was given
+func DryRun(cmd *cobra.Command) (bool, error) {
+	value, _ := cmd.Flags().GetString("dry-run")
+	switch value {
+	case "", "none":
+		return false, nil
+	case "server":
+		return true, nil
+	case "client":
+		return false, fmt.Errorf("--dry-run=client is not supported, use --dry-run=server")
+	default:
+		return false, fmt.Errorf("invalid --dry-run value %q: must be \"none\" or \"server\"", value)
+	}
+}
+
+// DryRunSuffix returns the note appended to result messages of a server-side dry run
+func DryRunSuffix(dryRun bool) string {
+	if dryRun {
+		return " (server dry run)"
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package cmdutil
+
+import (
+	"fmt"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// AddListFlags adds the --limit, --chunk-size and --selector flags shared by list commands
+func AddListFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("limit", 0, "Maximum number of items to list (0 for all)")
+	cmd.Flags().Int64("chunk-size", 0, "Number of items to request per page (0 for the server default)")
+	cmd.Flags().StringP("selector", "l", "", "Label selector to filter on, e.g. env=dev,tier!=web")
+}
+
+// ListFlags returns the list options for --chunk-size and --selector and the maximum number of items from --limit
+func ListFlags(cmd *cobra.Command) (client.ListOptions, int, error) {
+	limit, _ := cmd.Flags().GetInt("limit")
+	chunkSize, _ := cmd.Flags().GetInt64("chunk-size")
+	selector, _ := cmd.Flags().GetString("selector")
+
+	if limit < 0 {
+		return client.ListOptions{}, 0, fmt.Errorf("--limit must not be negative")
+	}
+	if chunkSize < 0 {
+		return client.ListOptions{}, 0, fmt.Errorf("--chunk-size must not be negative")
+	}
+
+	// No point in fetching pages larger than the number of items wanted
+	if limit > 0 && (chunkSize == 0 || chunkSize > int64(limit)) {
+		chunkSize = int64(limit)
+	}
+
+	return client.ListOptions{Limit: chunkSize, LabelSelector: selector}, limit, nil
+}
@@ -0,0 +1,57 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// EachName calls fn for each distinct name in turn, reporting failures to errOut
+// as failing to verb the given kind of object, e.g. "get" a "spot node pool".
+// The objects returned are in the order named, together with an error counting
+// the failures, so commands can show what succeeded before failing.
+func EachName[T any](ctx context.Context, names []string, errOut io.Writer, verb, what string, fn func(ctx context.Context, name string) (*T, error)) ([]T, error) {
+	var unique []string
+	for _, name := range names {
+		if !slices.Contains(unique, name) {
+			unique = append(unique, name)
+		}
+	}
+
+	items := make([]T, 0, len(unique))
+	failed := 0
+	for _, name := range unique {
+		item, err := fn(ctx, name)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: failed to %s %s '%s': %v\n", verb, what, name, err)
+			failed++
+			continue
+		}
+		items = append(items, *item)
+	}
+	if failed > 0 {
+		return items, fmt.Errorf("failed to %s %d of %d objects", verb, failed, len(unique))
+	}
+	return items, nil
+}
+
+// EachTarget calls fn for each target in turn like EachName, for objects that
+// may be in different namespaces, e.g. when named with --filename
+func EachTarget[T any](ctx context.Context, targets []Target, errOut io.Writer, verb string, fn func(ctx context.Context, t Target) (*T, error)) ([]T, error) {
+	items := make([]T, 0, len(targets))
+	failed := 0
+	for _, t := range targets {
+		item, err := fn(ctx, t)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: failed to %s %s '%s'%s: %v\n", verb, t.Kind.Resource().DisplayName, t.Name, inNamespace(t), err)
+			failed++
+			continue
+		}
+		items = append(items, *item)
+	}
+	if failed > 0 {
+		return items, fmt.Errorf("failed to %s %d of %d objects", verb, failed, len(targets))
+	}
+	return items, nil
+}
@@ -0,0 +1,147 @@
+package cmdutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/cache"
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// OrgCacheTTL is how long an organization to namespace mapping is reused.
+// Namespaces never change for an organization, so the cache mostly saves
+// a round trip on every command run with --org.
+const OrgCacheTTL = 24 * time.Hour
+
+// cacheDir returns the directory organization lookups are cached in
+func cacheDir() (string, error) {
+	return cache.DefaultDir("organizations")
+}
+
+// ResolveNamespace resolves the namespace a command operates on, in order of precedence:
+//   - the --namespace flag
+//   - the --org flag, resolved through the organizations API
+//   - the 'namespace' config value (or SPOTCTL_NAMESPACE)
+//   - the 'org' config value (or SPOTCTL_ORG), resolved through the organizations API
+func ResolveNamespace(cmd *cobra.Command) (string, error) {
+	// A namespace given explicitly needs no config to resolve
+	if namespace := flagValue(cmd, "namespace"); namespace != "" {
+		return namespace, nil
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return NamespaceFromConfig(Context(cmd), cmd, cfg)
+}
+
+// NamespaceFromConfig resolves the namespace like ResolveNamespace using an already loaded config
+func NamespaceFromConfig(ctx context.Context, cmd *cobra.Command, cfg *config.Config) (string, error) {
+	if namespace := flagValue(cmd, "namespace"); namespace != "" {
+		return namespace, nil
+	}
+	if org := flagValue(cmd, "org"); org != "" {
+		return OrgNamespace(ctx, cfg, org)
+	}
+	if cfg.Namespace != "" {
+		return cfg.Namespace, nil
+	}
+	if cfg.Org != "" {
+		return OrgNamespace(ctx, cfg, cfg.Org)
+	}
+
+	return "", fmt.Errorf("namespace is required: set it via --namespace or --org flag, config file, or SPOTCTL_NAMESPACE environment variable")
+}
+
+// OrgNamespace returns the namespace of the organization identified by its name,
+// display name or ID. Lookups are cached per API endpoint and account for OrgCacheTTL.
+func OrgNamespace(ctx context.Context, cfg *config.Config, org string) (string, error) {
+	key := strings.Join([]string{cfg.BaseURL, Account(cfg), "organization", org}, "|")
+
+	var store *cache.Cache
+	if dir, err := cacheDir(); err == nil {
+		store = cache.New(dir, OrgCacheTTL)
+	}
+
+	var namespace string
+	if store != nil && store.Get(key, &namespace) && namespace != "" {
+		return namespace, nil
+	}
+
+	list, err := client.NewClient(cfg).ListOrganizations(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	match, err := FindOrganization(list.Organizations, org)
+	if err != nil {
+		return "", err
+	}
+	if match.Metadata.Namespace == "" {
+		return "", fmt.Errorf("organization '%s' has no namespace", org)
+	}
+
+	if store != nil {
+		// Caching is an optimisation only, a failed write just means looking it up again next time
+		_ = store.Set(key, match.Metadata.Namespace)
+	}
+	return match.Metadata.Namespace, nil
+}
+
+// Account identifies the account cfg signs in as, for keying cached lookups whose
+// results differ between accounts, such as which organizations there are. It is
+// a fingerprint of the refresh token, so a rotated token starts a new cache
+// rather than the token itself being written anywhere.
+func Account(cfg *config.Config) string {
+	sum := sha256.Sum256([]byte(cfg.RefreshToken))
+	return hex.EncodeToString(sum[:8])
+}
+
+// FindOrganization returns the organization whose ID or name equals org, or whose
+// display name matches org ignoring case. An ID or name match wins over a display
+// name match; several organizations matching equally well is an error.
+func FindOrganization(organizations []client.Organization, org string) (*client.Organization, error) {
+	var exact, byDisplayName []client.Organization
+	for _, o := range organizations {
+		switch {
+		case o.ID == org || o.Name == org:
+			exact = append(exact, o)
+		case o.DisplayName != "" && strings.EqualFold(o.DisplayName, org):
+			byDisplayName = append(byDisplayName, o)
+		}
+	}
+
+	matches := exact
+	if len(matches) == 0 {
+		matches = byDisplayName
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("organization '%s' not found: run 'spotctl organizations list' to see available organizations", org)
+	case 1:
+		return &matches[0], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, m := range matches {
+			names = append(names, m.Name)
+		}
+		return nil, fmt.Errorf("organization '%s' is ambiguous, it matches: %s", org, strings.Join(names, ", "))
+	}
+}
+
+// flagValue returns the value of a flag if the command has it, including inherited persistent flags
+func flagValue(cmd *cobra.Command, name string) string {
+	if flag := cmd.Flag(name); flag != nil {
+		return flag.Value.String()
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package cmdutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// setupStandIn points the config at a fake API with the organizations acme and globex
+func setupStandIn(t *testing.T) *spottest.TestServer {
+	t.Helper()
+	server := spottest.NewTestServer(t)
+	if err := server.Seed(
+		client.Organization{ID: "org_1", Name: "acme", DisplayName: "Acme Ltd", Metadata: client.OrganizationMetadata{Namespace: "org-abc123"}},
+		client.Organization{ID: "org_2", Name: "globex", DisplayName: "Globex", Metadata: client.OrganizationMetadata{Namespace: "org-def456"}},
+	); err != nil {
+		t.Fatal(err)
+	}
+	server.Configure(t)
+	return server
+}
+
+func newTestCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "list"}
+	cmd.Flags().StringP("namespace", "n", "", "")
+	cmd.Flags().String("org", "", "")
+	return cmd
+}
+
+func TestResolveNamespace(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		config      map[string]string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "namespace flag wins",
+			flags:    map[string]string{"namespace": "org-flag", "org": "acme"},
+			config:   map[string]string{"namespace": "org-config"},
+			expected: "org-flag",
+		},
+		{
+			name:     "org flag wins over config namespace",
+			flags:    map[string]string{"org": "acme"},
+			config:   map[string]string{"namespace": "org-config"},
+			expected: "org-abc123",
+		},
+		{
+			name:     "config namespace",
+			config:   map[string]string{"namespace": "org-config", "org": "globex"},
+			expected: "org-config",
+		},
+		{
+			name:     "config org",
+			config:   map[string]string{"org": "globex"},
+			expected: "org-def456",
+		},
+		{
+			name:        "nothing set",
+			expectedErr: "namespace is required",
+		},
+		{
+			name:        "unknown org",
+			flags:       map[string]string{"org": "initech"},
+			expectedErr: "organization 'initech' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupStandIn(t)
+			for key, value := range tt.config {
+				viper.Set(key, value)
+			}
+			cmd := newTestCommand()
+			for name, value := range tt.flags {
+				cmd.Flags().Set(name, value)
+			}
+
+			namespace, err := ResolveNamespace(cmd)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if namespace != tt.expected {
+				t.Errorf("Expected namespace %q, got %q", tt.expected, namespace)
+			}
+		})
+	}
+}
+
+func TestResolveNamespaceCachesOrgLookups(t *testing.T) {
+	server := setupStandIn(t)
+
+	for i := 0; i < 2; i++ {
+		cmd := newTestCommand()
+		cmd.Flags().Set("org", "Acme Ltd")
+		namespace, err := ResolveNamespace(cmd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if namespace != "org-abc123" {
+			t.Errorf("Expected namespace org-abc123, got %q", namespace)
+		}
+	}
+
+	if got := server.Requests(); got != 1 {
+		t.Errorf("Expected 1 API request thanks to the cache, got %d", got)
+	}
+
+	// Another account may see other organizations, so it does not share the cache;
+	// the server rejects its token, which shows the lookup was not served from it
+	cfg := server.Config()
+	cfg.RefreshToken = "another-refresh-token"
+	if namespace, err := OrgNamespace(context.Background(), cfg, "Acme Ltd"); err == nil {
+		t.Errorf("Expected another account's lookup to miss the cache, got %q", namespace)
+	}
+}
+
+func TestFindOrganization(t *testing.T) {
+	orgs := []client.Organization{
+		{ID: "org_1", Name: "acme", DisplayName: "Acme"},
+		{ID: "org_2", Name: "acme-eu", DisplayName: "Acme"},
+		{ID: "org_3", Name: "Globex", DisplayName: "acme"},
+	}
+
+	tests := []struct {
+		org         string
+		expected    string
+		expectedErr string
+	}{
+		{org: "org_2", expected: "acme-eu"},
+		{org: "acme-eu", expected: "acme-eu"},
+		// A name match wins over a display name match of another organization
+		{org: "acme", expected: "acme"},
+		{org: "ACME", expectedErr: "ambiguous"},
+		{org: "initech", expectedErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.org, func(t *testing.T) {
+			org, err := FindOrganization(orgs, tt.org)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if org.Name != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, org.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package cmdutil
+
+import (
+	"fmt"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// AddPatchFlags adds the required --patch/-p flag naming the JSON patch file to a
+// per-type edit command, with --file kept as a deprecated alias from when -f named
+// the patch there; -f now names the objects to edit, as for 'spotctl edit'
+func AddPatchFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("patch", "p", "", "Path to the JSON file containing patch operations (required)")
+	cmd.Flags().String("file", "", "Path to the JSON file containing patch operations")
+	_ = cmd.Flags().MarkDeprecated("file", "use --patch/-p instead; -f now names the objects to edit")
+	cmd.MarkFlagsOneRequired("patch", "file")
+	cmd.MarkFlagsMutuallyExclusive("patch", "file")
+}
+
+// LoadPatch loads the patch operations in the file given with --patch, or with
+// the deprecated --file
+func LoadPatch(cmd *cobra.Command) ([]client.PatchOperation, error) {
+	file, _ := cmd.Flags().GetString("patch")
+	if file == "" {
+		file, _ = cmd.Flags().GetString("file")
+	}
+	patchOps, err := client.LoadPatchOperations(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load patch operations: %w", err)
+	}
+	return patchOps, nil
+}
@@ -0,0 +1,347 @@
+package cmdutil
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Target is an object a command operates on, named on the command line, in a
+// manifest or by a resource/name reference as printed by -o name
+type Target struct {
+	Kind registry.Kind
+	// Namespace is empty for cluster scoped kinds, and for namespaced ones until resolved
+	Namespace string
+	Name      string
+}
+
+// String returns the target as a resource/name reference, e.g. spotnodepool/my-pool
+func (t Target) String() string {
+	return t.Kind.Resource().Singular + "/" + t.Name
+}
+
+// DescribeTargets names targets as comma separated resource/name references
+func DescribeTargets(targets []Target) string {
+	refs := make([]string, 0, len(targets))
+	for _, t := range targets {
+		refs = append(refs, t.String())
+	}
+	return strings.Join(refs, ", ")
+}
+
+// referencePattern matches a resource/name or namespace/resource/name reference line
+var referencePattern = regexp.MustCompile(`^([a-z0-9][a-z0-9-]*/)?[A-Za-z][A-Za-z0-9.]*/[^/\s:]+$`)
+
+// AddFilenameFlag adds the -f/--filename flag naming the objects to operate on
+func AddFilenameFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("filename", "f", nil, "Manifest, or resource/name references one per line, naming the objects to operate on (- for stdin)")
+}
+
+// ReadsStdin reports whether --filename reads from stdin
+func ReadsStdin(cmd *cobra.Command) bool {
+	filenames, _ := cmd.Flags().GetStringArray("filename")
+	for _, filename := range filenames {
+		if filename == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// Targets returns the objects named by args, either a resource type followed by
+// names or resource/name references, or by the files given with --filename.
+// Namespaced targets without a namespace of their own get the namespace from
+// --namespace, --org or the config.
+func Targets(cmd *cobra.Command, args []string) ([]Target, error) {
+	filenames, _ := cmd.Flags().GetStringArray("filename")
+
+	var targets []Target
+	switch {
+	case len(filenames) > 0 && len(args) > 0:
+		return nil, fmt.Errorf("names cannot be given together with --filename")
+	case len(filenames) > 0:
+		for _, filename := range filenames {
+			fileTargets, err := readTargetsFile(cmd, filename)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, fileTargets...)
+		}
+	default:
+		var err error
+		if targets, err = ArgTargets(args); err != nil {
+			return nil, err
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no objects given: name them as arguments or with --filename")
+	}
+
+	if err := ResolveNamespaces(cmd, targets); err != nil {
+		return nil, err
+	}
+	return uniqueTargets(targets), nil
+}
+
+// ArgTargets returns the objects named by arguments: a resource type followed by
+// names, e.g. spotnodepool a b, or resource/name references, e.g. snp/a cs/b,
+// optionally prefixed by a namespace, e.g. org-abc123/snp/a
+func ArgTargets(args []string) ([]Target, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	if !strings.Contains(args[0], "/") {
+		kind, err := registry.Lookup(args[0])
+		if err != nil {
+			return nil, err
+		}
+		targets := make([]Target, 0, len(args)-1)
+		for _, name := range args[1:] {
+			if strings.Contains(name, "/") {
+				return nil, fmt.Errorf("%q: names after a resource type cannot be resource/name references", name)
+			}
+			targets = append(targets, Target{Kind: kind, Name: name})
+		}
+		return targets, nil
+	}
+
+	targets := make([]Target, 0, len(args))
+	for _, arg := range args {
+		target, err := parseReference(arg)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// NameTargets returns targets of one kind, for commands of a single resource type
+func NameTargets(kind registry.Kind, names []string) []Target {
+	targets := make([]Target, 0, len(names))
+	for _, name := range names {
+		targets = append(targets, Target{Kind: kind, Name: name})
+	}
+	return uniqueTargets(targets)
+}
+
+// KindTargets returns the objects of kind named by args, or by the files given
+// with --filename, for commands of a single resource type. Namespaces are
+// resolved as by Targets; objects of another kind in the files are an error.
+func KindTargets(cmd *cobra.Command, kind registry.Kind, args []string) ([]Target, error) {
+	if filenames, _ := cmd.Flags().GetStringArray("filename"); len(filenames) > 0 {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("names cannot be given together with --filename")
+		}
+		targets, err := Targets(cmd, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range targets {
+			if t.Kind.Resource().Plural != kind.Resource().Plural {
+				return nil, fmt.Errorf("%s is not a %s: use 'spotctl %s' for objects of several types", t, kind.Resource().DisplayName, cmd.Name())
+			}
+		}
+		return targets, nil
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no %s given: name them as arguments or with --filename", registry.DisplayPlural(kind))
+	}
+	targets := NameTargets(kind, args)
+	if err := ResolveNamespaces(cmd, targets); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// readTargetsFile reads the targets in a file, or stdin for -
+func readTargetsFile(cmd *cobra.Command, filename string) ([]Target, error) {
+	var data []byte
+	var err error
+	if filename == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	targets, err := ReadTargets(bytes.NewReader(data))
+	if err != nil {
+		if filename == "-" {
+			filename = "stdin"
+		}
+		return nil, fmt.Errorf("failed to read objects from %s: %w", filename, err)
+	}
+	return targets, nil
+}
+
+// ReadTargets reads the objects named by resource/name references one per line,
+// as printed by -o name and prefixed by their namespace with -A, or by manifests: YAML or JSON objects with a kind and
+// metadata.name, including lists and arrays of them as printed by -o json or -o yaml
+func ReadTargets(r io.Reader) ([]Target, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if lines, ok := referenceLines(data); ok {
+		targets := make([]Target, 0, len(lines))
+		for _, line := range lines {
+			target, err := parseReference(line)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, target)
+		}
+		return targets, nil
+	}
+
+	var targets []Target
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc any
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("invalid manifest: %w", err)
+		}
+		docTargets, err := manifestTargets(doc)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, docTargets...)
+	}
+	return targets, nil
+}
+
+// referenceLines returns the non-blank lines of data if every one is a
+// resource/name reference; lines starting with # are ignored
+func referenceLines(data []byte) ([]string, bool) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !referencePattern.MatchString(line) {
+			return nil, false
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err() == nil
+}
+
+// parseReference parses a resource/name reference such as spotnodepool/my-pool,
+// or a namespace/resource/name reference such as org-abc123/spotnodepool/my-pool
+func parseReference(reference string) (Target, error) {
+	parts := strings.Split(reference, "/")
+	if len(parts) < 2 || len(parts) > 3 || slices.Contains(parts, "") {
+		return Target{}, fmt.Errorf("invalid reference %q: expected resource/name or namespace/resource/name, e.g. spotnodepool/my-pool", reference)
+	}
+	var namespace string
+	if len(parts) == 3 {
+		namespace, parts = parts[0], parts[1:]
+	}
+	kind, err := registry.Lookup(parts[0])
+	if err != nil {
+		return Target{}, err
+	}
+	if namespace != "" && !kind.Resource().Namespaced {
+		return Target{}, fmt.Errorf("invalid reference %q: %s are not namespaced", reference, kind.Resource().Plural)
+	}
+	return Target{Kind: kind, Namespace: namespace, Name: parts[1]}, nil
+}
+
+// manifestTargets returns the objects in a decoded manifest document
+func manifestTargets(doc any) ([]Target, error) {
+	switch doc := doc.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		var targets []Target
+		for _, item := range doc {
+			itemTargets, err := manifestTargets(item)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, itemTargets...)
+		}
+		return targets, nil
+	case map[string]any:
+		kindName, _ := doc["kind"].(string)
+		if items, ok := doc["items"].([]any); ok && strings.HasSuffix(kindName, "List") {
+			return manifestTargets(items)
+		}
+		if kindName == "" {
+			return nil, fmt.Errorf("invalid manifest: object has no kind")
+		}
+		kind, err := registry.Lookup(kindName)
+		if err != nil {
+			return nil, err
+		}
+		metadata, _ := doc["metadata"].(map[string]any)
+		name, _ := metadata["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("invalid manifest: %s has no metadata.name", kindName)
+		}
+		namespace, _ := metadata["namespace"].(string)
+		if !kind.Resource().Namespaced {
+			namespace = ""
+		}
+		return []Target{{Kind: kind, Namespace: namespace, Name: name}}, nil
+	default:
+		return nil, fmt.Errorf("invalid manifest: expected an object, got %v", doc)
+	}
+}
+
+// uniqueTargets drops repeated targets, keeping the first of each
+func uniqueTargets(targets []Target) []Target {
+	seen := map[string]bool{}
+	unique := targets[:0:0]
+	for _, t := range targets {
+		if key := targetKey(t); !seen[key] {
+			seen[key] = true
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+// targetKey identifies the object a target names
+func targetKey(t Target) string {
+	return t.Kind.Resource().Plural + "|" + t.Namespace + "|" + t.Name
+}
+
+// ResolveNamespaces sets the namespace of namespaced targets that have none from
+// --namespace, --org or the config, resolving it only if one needs it
+func ResolveNamespaces(cmd *cobra.Command, targets []Target) error {
+	var namespace string
+	for i := range targets {
+		if !targets[i].Kind.Resource().Namespaced || targets[i].Namespace != "" {
+			continue
+		}
+		if namespace == "" {
+			var err error
+			if namespace, err = ResolveNamespace(cmd); err != nil {
+				return err
+			}
+		}
+		targets[i].Namespace = namespace
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmdutil
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+)
+
+func targetStrings(targets []Target) string {
+	var refs []string
+	for _, t := range targets {
+		refs = append(refs, t.String()+"@"+t.Namespace)
+	}
+	return strings.Join(refs, " ")
+}
+
+func TestReadTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "references",
+			input:    "# from -o name\nspotnodepool/pool-a\n\nsnp/pool-b\ncloudspace/cs-a\n",
+			expected: "spotnodepool/pool-a@ spotnodepool/pool-b@ cloudspace/cs-a@",
+		},
+		{
+			name: "yaml documents",
+			input: `apiVersion: ngpc.rxt.io/v1
+kind: SpotNodePool
+metadata:
+  name: pool-a
+  namespace: org-abc123
+---
+apiVersion: ngpc.rxt.io/v1
+kind: Region
+metadata:
+  name: uk-lon-1
+  namespace: ignored
+`,
+			expected: "spotnodepool/pool-a@org-abc123 region/uk-lon-1@",
+		},
+		{
+			name:     "json array",
+			input:    `[{"kind":"CloudSpace","metadata":{"name":"cs-a"}},{"kind":"CloudSpace","metadata":{"name":"cs-b"}}]`,
+			expected: "cloudspace/cs-a@ cloudspace/cs-b@",
+		},
+		{
+			name:     "namespaced references",
+			input:    "org-abc123/spotnodepool/pool-a\nsnp/pool-b\n",
+			expected: "spotnodepool/pool-a@org-abc123 spotnodepool/pool-b@",
+		},
+		{
+			name:     "list",
+			input:    `{"kind":"SpotNodePoolList","items":[{"kind":"SpotNodePool","metadata":{"name":"pool-a"}}]}`,
+			expected: "spotnodepool/pool-a@",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := ReadTargets(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadTargets failed: %v", err)
+			}
+			if got := targetStrings(targets); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	for _, input := range []string{"widgets/a\n", "org-abc123/region/uk-lon-1\n", "metadata:\n  name: a\n", "kind: SpotNodePool\n"} {
+		if _, err := ReadTargets(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected ReadTargets(%q) to fail", input)
+		}
+	}
+}
+
+func TestArgTargets(t *testing.T) {
+	targets, err := ArgTargets([]string{"snp", "pool-a", "pool-b"})
+	if err != nil {
+		t.Fatalf("ArgTargets failed: %v", err)
+	}
+	if got := targetStrings(targets); got != "spotnodepool/pool-a@ spotnodepool/pool-b@" {
+		t.Errorf("Unexpected targets %q", got)
+	}
+
+	targets, err = ArgTargets([]string{"snp/pool-a", "cs/cs-a"})
+	if err != nil {
+		t.Fatalf("ArgTargets failed: %v", err)
+	}
+	if got := targetStrings(targets); got != "spotnodepool/pool-a@ cloudspace/cs-a@" {
+		t.Errorf("Unexpected targets %q", got)
+	}
+
+	if _, err := ArgTargets([]string{"snp", "cs/cs-a"}); err == nil {
+		t.Error("Expected a reference after a resource type to be rejected")
+	}
+}
+
+func TestTargets(t *testing.T) {
+	cmd := newTestCommand()
+	AddFilenameFlag(cmd)
+	cmd.ParseFlags([]string{"-f", "-", "--namespace", "org-flag"})
+	cmd.SetIn(strings.NewReader("snp/pool-a\nregion/uk-lon-1\nsnp/pool-a\n"))
+	if !ReadsStdin(cmd) {
+		t.Error("Expected -f - to read stdin")
+	}
+
+	targets, err := Targets(cmd, nil)
+	if err != nil {
+		t.Fatalf("Targets failed: %v", err)
+	}
+	if got := targetStrings(targets); got != "spotnodepool/pool-a@org-flag region/uk-lon-1@" {
+		t.Errorf("Expected namespaced targets resolved and repeats dropped, got %q", got)
+	}
+
+	if _, err := Targets(cmd, []string{"snp/pool-b"}); err == nil {
+		t.Error("Expected names to be rejected with --filename")
+	}
+}
+
+func TestDeleteTargets(t *testing.T) {
+	server := spottest.NewTestServer(t)
+	pool := func(name string) client.SpotNodePool {
+		return client.SpotNodePool{Metadata: client.ObjectMeta{Name: name, Namespace: spottest.DefaultNamespace}}
+	}
+	if err := server.Seed(pool("pool-a"), pool("pool-b")); err != nil {
+		t.Fatal(err)
+	}
+	targets := NameTargets(registry.SpotNodePools, []string{"pool-a", "missing", "pool-b", "pool-a"})
+	for i := range targets {
+		targets[i].Namespace = spottest.DefaultNamespace
+	}
+
+	var out, errOut bytes.Buffer
+	err := DeleteTargets(context.Background(), client.NewClient(server.Config()), targets, &out, &errOut, false)
+	if err == nil || err.Error() != "failed to delete 1 of 3 objects" {
+		t.Errorf("Expected one failure, got %v", err)
+	}
+	// Each is reported as it finishes, in no particular order, then the summary
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 || lines[2] != "2 deleted, 1 failed" {
+		t.Fatalf("Expected two reports and a summary, got %q", out.String())
+	}
+	sort.Strings(lines[:2])
+	if lines[0] != "SpotNodePool 'pool-a' deleted successfully from namespace 'org-spottest'" ||
+		lines[1] != "SpotNodePool 'pool-b' deleted successfully from namespace 'org-spottest'" {
+		t.Errorf("Unexpected reports %q", lines[:2])
+	}
+	if !strings.Contains(errOut.String(), "failed to delete spot node pool 'missing' in namespace 'org-spottest'") {
+		t.Errorf("Expected the missing pool to be reported, got %q", errOut.String())
+	}
+
+	err = DeleteTargets(context.Background(), client.NewClient(server.Config()), targets[1:2], &out, &errOut, false)
+	if err == nil || !strings.Contains(err.Error(), "failed to delete spot node pool 'missing'") {
+		t.Errorf("Expected a single failure to be returned, got %v", err)
+	}
+}
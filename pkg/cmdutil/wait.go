@@ -0,0 +1,49 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// WaitPollInterval is how often WaitForDeletion checks whether objects are gone
+var WaitPollInterval = 2 * time.Second
+
+// WaitForDeletion polls targets until none of them exist any more, reporting to
+// out as each disappears. It gives up when ctx ends, e.g. on --timeout or Ctrl-C,
+// naming the objects still waited for.
+func WaitForDeletion(ctx context.Context, apiClient client.Interface, targets []Target, out io.Writer) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	fmt.Fprintf(out, "Waiting for %s to be removed...\n", DescribeTargets(targets))
+
+	remaining := targets
+	for {
+		var still []Target
+		for _, t := range remaining {
+			_, err := t.Kind.Get(ctx, apiClient, t.Namespace, t.Name)
+			switch {
+			case client.IsNotFound(err):
+				fmt.Fprintf(out, "%s '%s' removed\n", t.Kind.Resource().Kind, t.Name)
+			case err != nil && ctx.Err() == nil:
+				return fmt.Errorf("failed to check whether %s was removed: %w", t, err)
+			default:
+				still = append(still, t)
+			}
+		}
+		if len(still) == 0 {
+			return nil
+		}
+		remaining = still
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up waiting for %s to be removed: %w", DescribeTargets(remaining), ctx.Err())
+		case <-time.After(WaitPollInterval):
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package cmdutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+)
+
+func TestWaitForDeletion(t *testing.T) {
+	originalInterval := WaitPollInterval
+	WaitPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { WaitPollInterval = originalInterval })
+
+	server := spottest.NewTestServer(t, spottest.WithDeletionDelay(time.Hour))
+	pool := func(name string) client.SpotNodePool {
+		return client.SpotNodePool{Metadata: client.ObjectMeta{Name: name, Namespace: spottest.DefaultNamespace}}
+	}
+	if err := server.Seed(pool("pool-a"), pool("pool-b")); err != nil {
+		t.Fatal(err)
+	}
+	apiClient := client.NewClient(server.Config())
+	if _, err := apiClient.DeleteSpotNodePool(context.Background(), spottest.DefaultNamespace, "pool-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	targets := []Target{
+		{Kind: registry.SpotNodePools, Namespace: spottest.DefaultNamespace, Name: "pool-a"},
+		{Kind: registry.SpotNodePools, Namespace: spottest.DefaultNamespace, Name: "missing"},
+	}
+
+	// pool-a is still being deleted when the context ends
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var out bytes.Buffer
+	err := WaitForDeletion(ctx, apiClient, targets, &out)
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "gave up waiting for spotnodepool/pool-a to be removed") {
+		t.Errorf("Expected to give up waiting for pool-a, got %v", err)
+	}
+	expected := "Waiting for spotnodepool/pool-a, spotnodepool/missing to be removed...\nSpotNodePool 'missing' removed\n"
+	if out.String() != expected {
+		t.Errorf("Expected output %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,299 @@
+package completion
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/cache"
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/cmdutil"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// CacheTTL is how long fetched resource names are reused between completions.
+// Completion runs on every <TAB>, so a short cache keeps it responsive without
+// suggesting names that were deleted long ago.
+const CacheTTL = 30 * time.Second
+
+// requestTimeout bounds how long a completion waits for the API
+const requestTimeout = 5 * time.Second
+
+// lister fetches completion candidates, optionally with a "\t"-separated description
+type lister func(ctx context.Context, apiClient *client.Client, namespace string) ([]string, error)
+
+// cacheDir returns the directory completion results are cached in
+func cacheDir() (string, error) {
+	return cache.DefaultDir("completion")
+}
+
+// Each completes every positional argument with complete, for commands taking several names
+func Each(complete cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return complete(cmd, nil, toComplete)
+	}
+}
+
+// CloudSpaceNames completes the name of a cloudspace in the current namespace
+func CloudSpaceNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return complete(cmd, toComplete, "cloudspaces", true, func(ctx context.Context, apiClient *client.Client, namespace string) ([]string, error) {
+		list, err := apiClient.ListCloudSpaces(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, withDescription(item.Metadata.Name, item.Spec.Region))
+		}
+		return names, nil
+	})
+}
+
+// SpotNodePoolNames completes the name of a spot node pool in the current namespace
+func SpotNodePoolNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return SpotNodePoolFlagValues(cmd, args, toComplete)
+}
+
+// SpotNodePoolFlagValues completes a spot node pool name for a flag, regardless of positional arguments
+func SpotNodePoolFlagValues(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return complete(cmd, toComplete, "spotnodepools", true, func(ctx context.Context, apiClient *client.Client, namespace string) ([]string, error) {
+		list, err := apiClient.ListSpotNodePools(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, withDescription(item.Metadata.Name, item.Spec.ServerClass))
+		}
+		return names, nil
+	})
+}
+
+// OnDemandNodePoolNames completes the name of an on-demand node pool in the current namespace
+func OnDemandNodePoolNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return complete(cmd, toComplete, "ondemandnodepools", true, func(ctx context.Context, apiClient *client.Client, namespace string) ([]string, error) {
+		list, err := apiClient.ListOnDemandNodePools(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, withDescription(item.Metadata.Name, item.Spec.ServerClass))
+		}
+		return names, nil
+	})
+}
+
+// RegionNames completes a region name
+func RegionNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return RegionFlagValues(cmd, args, toComplete)
+}
+
+// RegionFlagValues completes a region name for a flag, regardless of positional arguments
+func RegionFlagValues(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return complete(cmd, toComplete, "regions", false, func(ctx context.Context, apiClient *client.Client, _ string) ([]string, error) {
+		list, err := apiClient.ListRegions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, withDescription(item.Metadata.Name, item.Spec.Description))
+		}
+		return names, nil
+	})
+}
+
+// ServerClassNames completes a server class name
+func ServerClassNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return ServerClassFlagValues(cmd, args, toComplete)
+}
+
+// ServerClassFlagValues completes a server class name for a flag or for
+// commands that take several server classes, skipping those already given
+func ServerClassFlagValues(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	candidates, directive := complete(cmd, toComplete, "serverclasses", false, func(ctx context.Context, apiClient *client.Client, _ string) ([]string, error) {
+		list, err := apiClient.ListServerClasses(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, withDescription(item.Metadata.Name, item.Spec.Region))
+		}
+		return names, nil
+	})
+	return exclude(candidates, args), directive
+}
+
+// Namespaces completes an organization namespace
+func Namespaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return complete(cmd, toComplete, "namespaces", false, func(ctx context.Context, apiClient *client.Client, _ string) ([]string, error) {
+		list, err := apiClient.ListOrganizations(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Organizations))
+		for _, org := range list.Organizations {
+			if org.Metadata.Namespace == "" {
+				continue
+			}
+			description := org.DisplayName
+			if description == "" {
+				description = org.Name
+			}
+			names = append(names, withDescription(org.Metadata.Namespace, description))
+		}
+		return names, nil
+	})
+}
+
+// Organizations completes an organization name, as accepted by --org
+func Organizations(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return complete(cmd, toComplete, "organizations", false, func(ctx context.Context, apiClient *client.Client, _ string) ([]string, error) {
+		list, err := apiClient.ListOrganizations(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Organizations))
+		for _, org := range list.Organizations {
+			names = append(names, withDescription(org.Name, org.DisplayName))
+		}
+		return names, nil
+	})
+}
+
+// OrganizationNames completes the organization name positional argument
+func OrganizationNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return Organizations(cmd, args, toComplete)
+}
+
+// complete returns the cached or freshly listed candidates matching toComplete.
+// Any failure results in no suggestions rather than an error, since there is no
+// good way to surface errors from inside a shell completion.
+func complete(cmd *cobra.Command, toComplete, kind string, namespaced bool, list lister) ([]string, cobra.ShellCompDirective) {
+	directive := cobra.ShellCompDirectiveNoFileComp
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		cobra.CompDebugln("completion: "+err.Error(), false)
+		return nil, directive
+	}
+
+	namespace := ""
+	if namespaced {
+		ctx, cancel := context.WithTimeout(cmdutil.Context(cmd), requestTimeout)
+		defer cancel()
+
+		namespace, err = cmdutil.NamespaceFromConfig(ctx, cmd, cfg)
+		if err != nil {
+			cobra.CompDebugln("completion: "+err.Error(), false)
+			return nil, directive
+		}
+	}
+
+	key := strings.Join([]string{cfg.BaseURL, cmdutil.Account(cfg), kind, namespace}, "|")
+	var candidates []string
+
+	var store *cache.Cache
+	if dir, err := cacheDir(); err == nil {
+		store = cache.New(dir, CacheTTL)
+	}
+
+	if store == nil || !store.Get(key, &candidates) {
+		ctx, cancel := context.WithTimeout(cmdutil.Context(cmd), requestTimeout)
+		defer cancel()
+
+		candidates, err = list(ctx, client.NewClient(cfg), namespace)
+		if err != nil {
+			cobra.CompDebugln("completion: "+err.Error(), false)
+			return nil, directive
+		}
+		if store != nil {
+			if err := store.Set(key, candidates); err != nil {
+				cobra.CompDebugln("completion: "+err.Error(), false)
+			}
+		}
+	}
+
+	return filterPrefix(candidates, toComplete), directive
+}
+
+// withDescription appends a description shown by shells that support it
+func withDescription(name, description string) string {
+	if description == "" {
+		return name
+	}
+	return name + "\t" + description
+}
+
+// filterPrefix returns the candidates whose name starts with prefix
+func filterPrefix(candidates []string, prefix string) []string {
+	matches := []string{}
+	for _, candidate := range candidates {
+		name, _, _ := strings.Cut(candidate, "\t")
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
+
+// exclude removes candidates whose name is in names
+func exclude(candidates []string, names []string) []string {
+	if len(names) == 0 {
+		return candidates
+	}
+	given := make(map[string]bool, len(names))
+	for _, name := range names {
+		given[name] = true
+	}
+	remaining := []string{}
+	for _, candidate := range candidates {
+		name, _, _ := strings.Cut(candidate, "\t")
+		if !given[name] {
+			remaining = append(remaining, candidate)
+		}
+	}
+	return remaining
+}
+
+// RegisterFlagCompletions registers completion for well-known flags on cmd and all of
+// its subcommands: --namespace, --org, --region, --server-class and --apply (a spot node pool)
+func RegisterFlagCompletions(cmd *cobra.Command) {
+	flags := map[string]cobra.CompletionFunc{
+		"namespace":    Namespaces,
+		"org":          Organizations,
+		"region":       RegionFlagValues,
+		"server-class": ServerClassFlagValues,
+		"apply":        SpotNodePoolFlagValues,
+	}
+	for name, fn := range flags {
+		if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+			// Registering a flag that is already registered (e.g. an inherited persistent flag) is harmless
+			_ = cmd.RegisterFlagCompletionFunc(name, fn)
+		}
+	}
+	for _, child := range cmd.Commands() {
+		RegisterFlagCompletions(child)
+	}
+}
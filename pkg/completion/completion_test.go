@@ -0,0 +1,119 @@
+package completion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/spottest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// setupStandIn points the config at a fake API with cloudspaces in two namespaces
+func setupStandIn(t *testing.T) *spottest.TestServer {
+	t.Helper()
+	server := spottest.NewTestServer(t)
+	if err := server.Seed(
+		client.CloudSpace{Metadata: client.ObjectMeta{Name: "prod", Namespace: spottest.DefaultNamespace}, Spec: client.CloudSpaceSpec{Region: "uk-lon-1"}},
+		client.CloudSpace{Metadata: client.ObjectMeta{Name: "staging", Namespace: spottest.DefaultNamespace}},
+		client.CloudSpace{Metadata: client.ObjectMeta{Name: "other", Namespace: "org-other"}},
+	); err != nil {
+		t.Fatal(err)
+	}
+	server.Configure(t)
+	viper.Set("namespace", spottest.DefaultNamespace)
+	return server
+}
+
+func newTestCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "get"}
+	cmd.Flags().StringP("namespace", "n", "", "")
+	return cmd
+}
+
+func TestCloudSpaceNames(t *testing.T) {
+	server := setupStandIn(t)
+	cmd := newTestCommand()
+
+	names, directive := CloudSpaceNames(cmd, nil, "")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected NoFileComp directive, got %v", directive)
+	}
+	if strings.Join(names, ",") != "prod\tuk-lon-1,staging" {
+		t.Errorf("Unexpected completions: %q", names)
+	}
+
+	// A second completion is served from the cache and filtered by prefix
+	names, _ = CloudSpaceNames(cmd, nil, "st")
+	if strings.Join(names, ",") != "staging" {
+		t.Errorf("Unexpected filtered completions: %q", names)
+	}
+	if got := server.Requests(); got != 1 {
+		t.Errorf("Expected 1 API request thanks to the cache, got %d", got)
+	}
+
+	// The namespace flag takes precedence and is cached separately
+	cmd.Flags().Set("namespace", "org-other")
+	names, _ = CloudSpaceNames(cmd, nil, "")
+	if strings.Join(names, ",") != "other" {
+		t.Errorf("Unexpected completions for --namespace: %q", names)
+	}
+
+	// Nothing is suggested once the name has been given
+	if names, _ := CloudSpaceNames(cmd, []string{"prod"}, ""); len(names) != 0 {
+		t.Errorf("Expected no completions after the first argument, got %q", names)
+	}
+}
+
+func TestServerClassFlagValuesExcludesGivenArgs(t *testing.T) {
+	setupStandIn(t)
+
+	names, _ := ServerClassFlagValues(newTestCommand(), []string{"gp.vs1.medium-dfw"}, "gp")
+	if strings.Join(names, ",") != "gp.vs1.large-dfw\tus-central-dfw-1" {
+		t.Errorf("Unexpected completions: %q", names)
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	setupStandIn(t)
+
+	names, _ := Namespaces(newTestCommand(), nil, "")
+	if strings.Join(names, ",") != "org-spottest\tSpot Test" {
+		t.Errorf("Unexpected completions: %q", names)
+	}
+}
+
+func TestCompletionWithoutConfig(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	names, directive := CloudSpaceNames(newTestCommand(), nil, "")
+	if len(names) != 0 || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected no completions without config, got %q (%v)", names, directive)
+	}
+}
+
+func TestRegisterFlagCompletions(t *testing.T) {
+	root := &cobra.Command{Use: "spotctl"}
+	root.PersistentFlags().StringP("namespace", "n", "", "")
+	child := &cobra.Command{Use: "create", Run: func(*cobra.Command, []string) {}}
+	child.Flags().String("server-class", "", "")
+	child.Flags().StringP("namespace", "n", "", "")
+	root.AddCommand(child)
+
+	RegisterFlagCompletions(root)
+
+	for _, tc := range []struct {
+		cmd  *cobra.Command
+		flag string
+	}{
+		{root, "namespace"},
+		{child, "server-class"},
+		{child, "namespace"},
+	} {
+		if _, ok := tc.cmd.GetFlagCompletionFunc(tc.flag); !ok {
+			t.Errorf("Expected completion for --%s on %s", tc.flag, tc.cmd.Name())
+		}
+	}
+}
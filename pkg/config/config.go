@@ -2,22 +2,27 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/georgetaylor/spotctl/pkg/errors"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 // Default configuration values
 var Defaults = struct {
 	BaseURL      string
+	OAuthURL     string
 	Timeout      int
 	Debug        bool
 	OutputFormat string
 	Namespace    string
 }{
 	BaseURL:      "https://spot.rackspace.com/apis",
+	OAuthURL:     "https://login.spot.rackspace.com/oauth/token",
 	Timeout:      30,
 	Debug:        false,
 	OutputFormat: "table",
@@ -28,15 +33,45 @@ var Defaults = struct {
 type Config struct {
 	RefreshToken string `mapstructure:"refresh-token"`
 	Namespace    string `mapstructure:"namespace"`
+	Org          string `mapstructure:"org"`
 	BaseURL      string `mapstructure:"base-url"`
-	Debug        bool   `mapstructure:"debug"`
-	Timeout      int    `mapstructure:"timeout"`
-	OutputFormat string `mapstructure:"output-format"`
+	OAuthURL     string `mapstructure:"oauth-url"`
+	// DeviceAuthURL is the device authorization endpoint for 'auth login --device';
+	// by default it is derived from OAuthURL
+	DeviceAuthURL string `mapstructure:"device-auth-url"`
+	Debug         bool   `mapstructure:"debug"`
+	Timeout       int    `mapstructure:"timeout"`
+	OutputFormat  string `mapstructure:"output-format"`
+	// APIVersion overrides the API version of requests to its group, e.g. to try a
+	// preview version (--api-version); a bare version such as v2beta1 is in ngpc.rxt.io
+	APIVersion string `mapstructure:"api-version"`
+	// RequestTimeout bounds each API request, overriding Timeout (in seconds) when set
+	RequestTimeout time.Duration `mapstructure:"request-timeout"`
+	// CAFile is a PEM bundle trusted in addition to the system roots, e.g. for a TLS-inspecting proxy
+	CAFile string `mapstructure:"ca-file"`
+	// InsecureSkipTLSVerify disables server certificate verification
+	InsecureSkipTLSVerify bool `mapstructure:"insecure-skip-tls-verify"`
+	// ClientCert and ClientKey are a PEM certificate and key presented for mutual TLS
+	ClientCert string `mapstructure:"client-cert"`
+	ClientKey  string `mapstructure:"client-key"`
+	// ProxyURL is the proxy for API and OAuth requests, overriding HTTPS_PROXY and friends
+	ProxyURL string `mapstructure:"proxy-url"`
+	// HTTPTrace logs every HTTP request and response, with credentials redacted (--http-trace)
+	HTTPTrace bool `mapstructure:"http-trace"`
+	// Record is a directory to record API interactions to (--record)
+	Record string `mapstructure:"record"`
+	// Replay is a directory to replay recorded API interactions from (--replay)
+	Replay string `mapstructure:"replay"`
+
+	// StoreRefreshToken, when set, saves a refresh token the authorization server
+	// rotated. Load sets it to save the token to the config file.
+	StoreRefreshToken func(refreshToken string) error `mapstructure:"-"`
 }
 
 // ValidateConfig validates the configuration
 func ValidateConfig(cfg *Config) error {
-	if cfg.RefreshToken == "" {
+	// Replayed sessions answer the token exchange from the cassette, so no refresh token is needed
+	if cfg.RefreshToken == "" && cfg.Replay == "" {
 		return errors.NewValidationError(
 			"refresh token is required. Set it via --refresh-token flag, config file, or SPOTCTL_REFRESH_TOKEN environment variable",
 			nil,
@@ -50,15 +85,58 @@ func ValidateConfig(cfg *Config) error {
 		)
 	}
 
+	if cfg.ProxyURL != "" {
+		if u, err := url.Parse(cfg.ProxyURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.NewValidationError(fmt.Sprintf("invalid proxy-url %q: expected a URL such as http://proxy.example.com:3128", cfg.ProxyURL), err)
+		}
+	}
+
+	if cfg.Record != "" && cfg.Replay != "" {
+		return errors.NewValidationError("--record and --replay cannot be used together", nil)
+	}
+
+	if (cfg.ClientCert == "") != (cfg.ClientKey == "") {
+		return errors.NewValidationError("client-cert and client-key must be set together", nil)
+	}
+	for _, file := range []struct{ flag, path string }{
+		{"ca-file", cfg.CAFile},
+		{"client-cert", cfg.ClientCert},
+		{"client-key", cfg.ClientKey},
+	} {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			return errors.NewValidationError(fmt.Sprintf("cannot read %s %q", file.flag, file.path), err)
+		}
+	}
+
 	return nil
 }
 
 // GetConfig returns the current configuration
 func GetConfig() (*Config, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate required fields with descriptive messages
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Load returns the current configuration without validating it, for commands
+// such as 'auth login' that run before any credentials exist
+func Load() (*Config, error) {
 	var cfg Config
 
 	// Set defaults
 	viper.SetDefault("base-url", Defaults.BaseURL)
+	viper.SetDefault("oauth-url", Defaults.OAuthURL)
 	viper.SetDefault("timeout", Defaults.Timeout)
 	viper.SetDefault("debug", Defaults.Debug)
 	viper.SetDefault("output-format", Defaults.OutputFormat)
@@ -67,11 +145,7 @@ func GetConfig() (*Config, error) {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, errors.NewConfigError("failed to unmarshal config", err)
 	}
-
-	// Validate required fields with descriptive messages
-	if err := ValidateConfig(&cfg); err != nil {
-		return nil, err
-	}
+	cfg.StoreRefreshToken = refreshTokenStore(cfg.RefreshToken)
 
 	return &cfg, nil
 }
@@ -86,20 +160,157 @@ func SaveConfig(cfg *Config) error {
 	// Set values in viper
 	viper.Set("refresh-token", cfg.RefreshToken)
 	viper.Set("namespace", cfg.Namespace)
+	viper.Set("org", cfg.Org)
 	viper.Set("base-url", cfg.BaseURL)
+	viper.Set("oauth-url", cfg.OAuthURL)
 	viper.Set("debug", cfg.Debug)
 	viper.Set("timeout", cfg.Timeout)
 	viper.Set("output-format", cfg.OutputFormat)
+	viper.Set("ca-file", cfg.CAFile)
+	viper.Set("insecure-skip-tls-verify", cfg.InsecureSkipTLSVerify)
+	viper.Set("client-cert", cfg.ClientCert)
+	viper.Set("client-key", cfg.ClientKey)
+	viper.Set("proxy-url", cfg.ProxyURL)
+
+	return writeConfig()
+}
+
+// refreshTokenStore returns how to save a rotated refresh token: to the config
+// file if the token in use was read from it. A token given with --refresh-token or
+// SPOTCTL_REFRESH_TOKEN is never written to the file; the user is told to update it.
+func refreshTokenStore(current string) func(refreshToken string) error {
+	settings, err := readConfigFile()
+	if err == nil && current != "" && settings["refresh-token"] == current {
+		return SaveRefreshToken
+	}
+	return func(refreshToken string) error {
+		viper.Set("refresh-token", refreshToken)
+		return errors.NewConfigError("the refresh token was not read from the config file, so the rotated one was not saved; update --refresh-token or SPOTCTL_REFRESH_TOKEN", nil)
+	}
+}
+
+// SaveRefreshToken saves refreshToken to the config file, leaving the other
+// settings in the file as they are
+func SaveRefreshToken(refreshToken string) error {
+	viper.Set("refresh-token", refreshToken)
+	return updateConfigFile(func(settings map[string]any) {
+		settings["refresh-token"] = refreshToken
+	})
+}
+
+// ClearRefreshToken removes the refresh token from the config file, leaving the
+// other settings in place. It does nothing if there is no config file.
+func ClearRefreshToken() error {
+	configPath, err := FilePath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil
+	}
+	viper.Set("refresh-token", "")
+	return updateConfigFile(func(settings map[string]any) {
+		delete(settings, "refresh-token")
+	})
+}
+
+// readConfigFile returns the settings in the config file alone, without the
+// flags, environment variables and defaults viper layers over them; none if
+// there is no config file
+func readConfigFile() (map[string]any, error) {
+	configPath, err := FilePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return map[string]any{}, nil
+	}
+	if err != nil {
+		return nil, errors.NewConfigError(fmt.Sprintf("failed to read config file %s", configPath), err)
+	}
+	settings := map[string]any{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, errors.NewConfigError(fmt.Sprintf("failed to parse config file %s", configPath), err)
+	}
+	if settings == nil {
+		settings = map[string]any{}
+	}
+	return settings, nil
+}
+
+// updateConfigFile applies update to the settings in the config file and
+// replaces the file atomically, so a failed write never leaves it truncated
+func updateConfigFile(update func(settings map[string]any)) error {
+	configPath, err := FilePath()
+	if err != nil {
+		return err
+	}
+	settings, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	update(settings)
+
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return errors.NewConfigError("failed to encode config", err)
+	}
+
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(configPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	dir := filepath.Dir(configPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.NewConfigError(fmt.Sprintf("failed to create config directory at %s", dir), err)
+	}
+	tmp, err := os.CreateTemp(dir, ".config-*.yaml")
+	if err != nil {
+		return errors.NewConfigError(fmt.Sprintf("failed to write config file to %s", configPath), err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.NewConfigError(fmt.Sprintf("failed to write config file to %s", configPath), err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return errors.NewConfigError(fmt.Sprintf("failed to write config file to %s", configPath), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.NewConfigError(fmt.Sprintf("failed to write config file to %s", configPath), err)
+	}
+	if err := os.Rename(tmp.Name(), configPath); err != nil {
+		return errors.NewConfigError(fmt.Sprintf("failed to write config file to %s", configPath), err)
+	}
+	return nil
+}
+
+// FilePath returns the path of the config file settings are saved to: the file
+// they were read from, if any
+func FilePath() (string, error) {
+	if used := viper.ConfigFileUsed(); used != "" {
+		return used, nil
+	}
 
 	// Get config path - use same logic as root.go
 	configPath := os.Getenv("SPOTCTL_CONFIG")
 	if configPath == "" {
 		home, err := os.UserHomeDir()
 		if err != nil {
-			return errors.NewConfigError("failed to get user home directory", err)
+			return "", errors.NewConfigError("failed to get user home directory", err)
 		}
 		configPath = filepath.Join(home, ".spot", "config.yaml") // Changed from .config/spotctl to .spot
+	}
+	return configPath, nil
+}
 
+// writeConfig writes the settings held by viper to the config file
+func writeConfig() error {
+	configPath, err := FilePath()
+	if err != nil {
+		return err
 	}
 
 	// Ensure directory exists
@@ -133,10 +344,20 @@ func InitConfig() error {
 	// Bind environment variables
 	viper.BindEnv("refresh-token", "SPOTCTL_REFRESH_TOKEN")
 	viper.BindEnv("namespace", "SPOTCTL_NAMESPACE")
+	viper.BindEnv("org", "SPOTCTL_ORG")
 	viper.BindEnv("base-url", "SPOTCTL_BASE_URL")
+	viper.BindEnv("oauth-url", "SPOTCTL_OAUTH_URL")
+	viper.BindEnv("device-auth-url", "SPOTCTL_DEVICE_AUTH_URL")
 	viper.BindEnv("debug", "SPOTCTL_DEBUG")
 	viper.BindEnv("timeout", "SPOTCTL_TIMEOUT")
 	viper.BindEnv("output-format", "SPOTCTL_OUTPUT_FORMAT")
+	viper.BindEnv("ca-file", "SPOTCTL_CA_FILE")
+	viper.BindEnv("insecure-skip-tls-verify", "SPOTCTL_INSECURE_SKIP_TLS_VERIFY")
+	viper.BindEnv("client-cert", "SPOTCTL_CLIENT_CERT")
+	viper.BindEnv("client-key", "SPOTCTL_CLIENT_KEY")
+	viper.BindEnv("proxy-url", "SPOTCTL_PROXY_URL")
+	viper.BindEnv("request-timeout", "SPOTCTL_REQUEST_TIMEOUT")
+	viper.BindEnv("api-version", "SPOTCTL_API_VERSION")
 
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
@@ -1,10 +1,23 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 func TestValidateConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	for _, path := range []string{certFile, keyFile} {
+		if err := os.WriteFile(path, []byte("pem"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
 	tests := []struct {
 		name    string
 		config  *Config
@@ -32,6 +45,52 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "client certificate and key",
+			config: &Config{
+				RefreshToken: "test-token",
+				BaseURL:      "https://api.test.com",
+				ClientCert:   certFile,
+				ClientKey:    keyFile,
+			},
+			wantErr: false,
+		},
+		{
+			name: "client certificate without key",
+			config: &Config{
+				RefreshToken: "test-token",
+				BaseURL:      "https://api.test.com",
+				ClientCert:   certFile,
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing CA file",
+			config: &Config{
+				RefreshToken: "test-token",
+				BaseURL:      "https://api.test.com",
+				CAFile:       filepath.Join(dir, "missing.pem"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid proxy URL",
+			config: &Config{
+				RefreshToken: "test-token",
+				BaseURL:      "https://api.test.com",
+				ProxyURL:     "http://proxy.example.com:3128",
+			},
+			wantErr: false,
+		},
+		{
+			name: "proxy URL without scheme",
+			config: &Config{
+				RefreshToken: "test-token",
+				BaseURL:      "https://api.test.com",
+				ProxyURL:     "proxy.example.com:3128",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -43,3 +102,61 @@ func TestValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestStoreRefreshToken(t *testing.T) {
+	setup := func(t *testing.T) string {
+		t.Helper()
+		configPath := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configPath, []byte("refresh-token: old-token\nnamespace: org-abc123\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("SPOTCTL_CONFIG", configPath)
+		viper.Reset()
+		t.Cleanup(viper.Reset)
+		viper.SetConfigFile(configPath)
+		if err := viper.ReadInConfig(); err != nil {
+			t.Fatal(err)
+		}
+		// One-off flags must not end up in the file
+		viper.Set("insecure-skip-tls-verify", true)
+		viper.Set("api-version", "v2beta1")
+		return configPath
+	}
+
+	t.Run("token from the config file", func(t *testing.T) {
+		configPath := setup(t)
+		cfg, err := Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cfg.StoreRefreshToken("new-token"); err != nil {
+			t.Fatalf("StoreRefreshToken failed: %v", err)
+		}
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "namespace: org-abc123\nrefresh-token: new-token\n"; string(data) != expected {
+			t.Errorf("Expected only the refresh token to change, got:\n%s", data)
+		}
+		if info, _ := os.Stat(configPath); info.Mode().Perm() != 0600 {
+			t.Errorf("Expected the file mode to be kept, got %v", info.Mode().Perm())
+		}
+	})
+
+	t.Run("token from a flag", func(t *testing.T) {
+		configPath := setup(t)
+		viper.Set("refresh-token", "flag-token")
+		cfg, err := Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cfg.StoreRefreshToken("new-token"); err == nil {
+			t.Error("Expected an error explaining the token was not saved")
+		}
+		data, _ := os.ReadFile(configPath)
+		if string(data) != "refresh-token: old-token\nnamespace: org-abc123\n" {
+			t.Errorf("Expected the config file to be left alone, got:\n%s", data)
+		}
+	})
+}
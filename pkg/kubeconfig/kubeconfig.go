@@ -0,0 +1,387 @@
+package kubeconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// Config represents a kubeconfig file.
+// Unknown top-level keys and entry fields are preserved so that merging into an
+// existing kubeconfig never drops settings written by other tools.
+type Config struct {
+	APIVersion     string                 `yaml:"apiVersion,omitempty"`
+	Kind           string                 `yaml:"kind,omitempty"`
+	Clusters       []NamedEntry           `yaml:"clusters"`
+	Contexts       []NamedEntry           `yaml:"contexts"`
+	Users          []NamedEntry           `yaml:"users"`
+	CurrentContext string                 `yaml:"current-context"`
+	Preferences    map[string]interface{} `yaml:"preferences,omitempty"`
+	Extra          map[string]interface{} `yaml:",inline"`
+}
+
+// NamedEntry is a named cluster, context or user entry.
+// Exactly one of Cluster, Context or User is set depending on the list it belongs to.
+type NamedEntry struct {
+	Name    string                 `yaml:"name"`
+	Cluster map[string]interface{} `yaml:"cluster,omitempty"`
+	Context map[string]interface{} `yaml:"context,omitempty"`
+	User    map[string]interface{} `yaml:"user,omitempty"`
+}
+
+// ConflictError is returned when merging would overwrite a cluster, context or
+// user that belongs to something else
+type ConflictError struct {
+	// Kind is "cluster", "context" or "user"
+	Kind string
+	Name string
+	// Existing and Wanted describe the entry already there and the one being merged
+	Existing string
+	Wanted   string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %q already exists %s (wanted %s); choose another --context-name or use --force",
+		e.Kind, e.Name, e.Existing, e.Wanted)
+}
+
+// New returns an empty kubeconfig
+func New() *Config {
+	return &Config{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters:   []NamedEntry{},
+		Contexts:   []NamedEntry{},
+		Users:      []NamedEntry{},
+	}
+}
+
+// ExecAPIVersion is the client.authentication.k8s.io version of the ExecCredential
+// that exec users print and kubectl reads
+const ExecAPIVersion = "client.authentication.k8s.io/v1"
+
+// TokenUser returns a user that authenticates with a fixed bearer token, which
+// stops working when the token expires
+func TokenUser(token string) map[string]interface{} {
+	return map[string]interface{}{"token": token}
+}
+
+// ExecUser returns a user for which kubectl runs command with args to get a token
+// whenever it needs one. The command prints an ExecCredential of ExecAPIVersion.
+func ExecUser(command string, args ...string) map[string]interface{} {
+	exec := map[string]interface{}{
+		"apiVersion":      ExecAPIVersion,
+		"command":         command,
+		"interactiveMode": "Never",
+		"installHint":     "Install spotctl to authenticate to this cloudspace: go install github.com/georgetaylor/spotctl@latest",
+	}
+	if len(args) > 0 {
+		exec["args"] = args
+	}
+	return map[string]interface{}{"exec": exec}
+}
+
+// ForCloudSpace builds a kubeconfig for the given cloudspace that authenticates
+// as user, as made by TokenUser or ExecUser
+func ForCloudSpace(cloudSpace *client.CloudSpace, user map[string]interface{}, contextName string, insecure bool) (*Config, error) {
+	if cloudSpace == nil {
+		return nil, fmt.Errorf("cloudspace is required")
+	}
+	endpoint := cloudSpace.Status.APIServerEndpoint
+	if endpoint == "" {
+		phase := cloudSpace.Status.Phase
+		if phase == "" {
+			phase = "unknown"
+		}
+		return nil, fmt.Errorf("cloudspace '%s' has no API server endpoint yet (phase: %s)", cloudSpace.Metadata.Name, phase)
+	}
+	if token, ok := user["token"]; len(user) == 0 || (ok && token == "") {
+		return nil, fmt.Errorf("user credentials are required")
+	}
+	if contextName == "" {
+		contextName = cloudSpace.Metadata.Name
+	}
+
+	server := endpoint
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+
+	cluster := map[string]interface{}{"server": server}
+	if insecure {
+		cluster["insecure-skip-tls-verify"] = true
+	}
+
+	context := map[string]interface{}{
+		"cluster": contextName,
+		"user":    contextName,
+	}
+
+	cfg := New()
+	cfg.Clusters = append(cfg.Clusters, NamedEntry{Name: contextName, Cluster: cluster})
+	cfg.Contexts = append(cfg.Contexts, NamedEntry{Name: contextName, Context: context})
+	cfg.Users = append(cfg.Users, NamedEntry{Name: contextName, User: user})
+	cfg.CurrentContext = contextName
+
+	return cfg, nil
+}
+
+// DefaultPath returns the kubeconfig path kubectl would use: the first entry of
+// $KUBECONFIG if set, otherwise ~/.kube/config
+func DefaultPath() (string, error) {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		for _, path := range filepath.SplitList(env) {
+			if path != "" {
+				return path, nil
+			}
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".kube", "config"), nil
+}
+
+// Load reads a kubeconfig from disk. A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return New(), nil
+		}
+		return nil, fmt.Errorf("failed to read kubeconfig %s: %w", path, err)
+	}
+
+	cfg := New()
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return cfg, nil
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+	if cfg.APIVersion == "" {
+		cfg.APIVersion = "v1"
+	}
+	if cfg.Kind == "" {
+		cfg.Kind = "Config"
+	}
+
+	return cfg, nil
+}
+
+// Marshal encodes the kubeconfig as YAML
+func (c *Config) Marshal() ([]byte, error) {
+	var sb strings.Builder
+	encoder := yaml.NewEncoder(&sb)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return nil, fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+	return []byte(sb.String()), nil
+}
+
+// Write saves the kubeconfig to path with owner-only permissions.
+// The file is written to a temporary file first and renamed into place so a
+// failed write never leaves a truncated kubeconfig behind.
+func Write(path string, cfg *Config) error {
+	data, err := cfg.Marshal()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file in %s: %w", dir, err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set kubeconfig permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to write kubeconfig to %s: %w", path, err)
+	}
+	return nil
+}
+
+// Merge adds or updates the clusters, contexts and users from other.
+// Entries with other names are left untouched. Unless force is set, replacing an
+// existing entry of the same name is reported as a ConflictError when it belongs
+// to something else: a cluster with a different server, a context with a
+// different cluster or user, or a user that other contexts use for other clusters.
+// Refreshing the credentials of a user merged before is not a conflict.
+func (c *Config) Merge(other *Config, force bool) error {
+	if !force {
+		if err := c.conflict(other); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range other.Clusters {
+		c.Clusters = upsertEntry(c.Clusters, entry)
+	}
+	for _, entry := range other.Contexts {
+		c.Contexts = upsertEntry(c.Contexts, entry)
+	}
+	for _, entry := range other.Users {
+		c.Users = upsertEntry(c.Users, entry)
+	}
+
+	if c.CurrentContext == "" {
+		c.CurrentContext = other.CurrentContext
+	}
+
+	return nil
+}
+
+// conflict returns a ConflictError for the first entry of other that would
+// replace an unrelated entry of c
+func (c *Config) conflict(other *Config) error {
+	for _, cluster := range other.Clusters {
+		existing := findEntry(c.Clusters, cluster.Name)
+		if existing == nil {
+			continue
+		}
+		existingServer, _ := existing.Cluster["server"].(string)
+		server, _ := cluster.Cluster["server"].(string)
+		if existingServer != "" && existingServer != server {
+			return &ConflictError{Kind: "cluster", Name: cluster.Name, Existing: "with server " + existingServer, Wanted: "server " + server}
+		}
+	}
+
+	for _, context := range other.Contexts {
+		existing := findEntry(c.Contexts, context.Name)
+		if existing == nil {
+			continue
+		}
+		if existing.Context["cluster"] != context.Context["cluster"] || existing.Context["user"] != context.Context["user"] {
+			return &ConflictError{Kind: "context", Name: context.Name, Existing: "with " + describeContext(existing), Wanted: describeContext(&context)}
+		}
+	}
+
+	// A user is only replaced for the clusters other uses it with
+	for _, user := range other.Users {
+		if findEntry(c.Users, user.Name) == nil {
+			continue
+		}
+		clusters := map[interface{}]bool{}
+		var wanted []string
+		for _, context := range other.Contexts {
+			if context.Context["user"] == user.Name {
+				clusters[context.Context["cluster"]] = true
+				wanted = append(wanted, fmt.Sprintf("cluster %q", context.Context["cluster"]))
+			}
+		}
+		for _, context := range c.Contexts {
+			if context.Context["user"] == user.Name && !clusters[context.Context["cluster"]] {
+				return &ConflictError{
+					Kind:     "user",
+					Name:     user.Name,
+					Existing: fmt.Sprintf("for cluster %q in context %q", context.Context["cluster"], context.Name),
+					Wanted:   strings.Join(wanted, ", "),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// describeContext names the cluster and user of a context
+func describeContext(entry *NamedEntry) string {
+	return fmt.Sprintf("cluster %q and user %q", entry.Context["cluster"], entry.Context["user"])
+}
+
+// RemoveContext deletes the named context together with its cluster and user,
+// unless another context still references them. It reports whether the context existed.
+func (c *Config) RemoveContext(name string) bool {
+	ctx := findEntry(c.Contexts, name)
+	if ctx == nil {
+		return false
+	}
+	clusterName, _ := ctx.Context["cluster"].(string)
+	userName, _ := ctx.Context["user"].(string)
+
+	c.Contexts = removeEntry(c.Contexts, name)
+
+	clusterInUse, userInUse := false, false
+	for _, other := range c.Contexts {
+		if other.Context["cluster"] == clusterName {
+			clusterInUse = true
+		}
+		if other.Context["user"] == userName {
+			userInUse = true
+		}
+	}
+	if clusterName != "" && !clusterInUse {
+		c.Clusters = removeEntry(c.Clusters, clusterName)
+	}
+	if userName != "" && !userInUse {
+		c.Users = removeEntry(c.Users, userName)
+	}
+
+	if c.CurrentContext == name {
+		c.CurrentContext = ""
+	}
+
+	return true
+}
+
+// HasContext reports whether a context with the given name exists
+func (c *Config) HasContext(name string) bool {
+	return findEntry(c.Contexts, name) != nil
+}
+
+func findEntry(entries []NamedEntry, name string) *NamedEntry {
+	for i := range entries {
+		if entries[i].Name == name {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+func upsertEntry(entries []NamedEntry, entry NamedEntry) []NamedEntry {
+	for i := range entries {
+		if entries[i].Name == entry.Name {
+			entries[i] = entry
+			return entries
+		}
+	}
+	return append(entries, entry)
+}
+
+func removeEntry(entries []NamedEntry, name string) []NamedEntry {
+	result := entries[:0]
+	for _, entry := range entries {
+		if entry.Name != name {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
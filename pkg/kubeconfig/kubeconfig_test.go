@@ -0,0 +1,337 @@
+package kubeconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+func testCloudSpace(endpoint string) *client.CloudSpace {
+	return &client.CloudSpace{
+		Metadata: client.ObjectMeta{Name: "my-cloudspace", Namespace: "org-abc123"},
+		Status:   client.CloudSpaceStatus{APIServerEndpoint: endpoint, Phase: "Ready"},
+	}
+}
+
+func TestForCloudSpace(t *testing.T) {
+	tests := []struct {
+		name           string
+		cloudSpace     *client.CloudSpace
+		token          string
+		contextName    string
+		expectError    bool
+		expectedServer string
+		expectedName   string
+	}{
+		{
+			name:           "endpoint without scheme",
+			cloudSpace:     testCloudSpace("hcp-123.spot.rackspace.com"),
+			token:          "id-token",
+			expectedServer: "https://hcp-123.spot.rackspace.com",
+			expectedName:   "my-cloudspace",
+		},
+		{
+			name:           "endpoint with scheme and custom context",
+			cloudSpace:     testCloudSpace("https://10.0.0.1:6443"),
+			token:          "id-token",
+			contextName:    "spot-prod",
+			expectedServer: "https://10.0.0.1:6443",
+			expectedName:   "spot-prod",
+		},
+		{
+			name:        "missing endpoint",
+			cloudSpace:  testCloudSpace(""),
+			token:       "id-token",
+			expectError: true,
+		},
+		{
+			name:        "missing token",
+			cloudSpace:  testCloudSpace("hcp-123.spot.rackspace.com"),
+			expectError: true,
+		},
+		{
+			name:        "nil cloudspace",
+			token:       "id-token",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ForCloudSpace(tt.cloudSpace, TokenUser(tt.token), tt.contextName, false)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if cfg.CurrentContext != tt.expectedName {
+				t.Errorf("expected current context %q, got %q", tt.expectedName, cfg.CurrentContext)
+			}
+			if len(cfg.Clusters) != 1 || cfg.Clusters[0].Cluster["server"] != tt.expectedServer {
+				t.Errorf("expected server %q, got %+v", tt.expectedServer, cfg.Clusters)
+			}
+			if len(cfg.Users) != 1 || cfg.Users[0].User["token"] != tt.token {
+				t.Errorf("expected user token %q, got %+v", tt.token, cfg.Users)
+			}
+			if !cfg.HasContext(tt.expectedName) {
+				t.Errorf("expected context %q to exist", tt.expectedName)
+			}
+		})
+	}
+}
+
+func TestForCloudSpaceExecUser(t *testing.T) {
+	cfg, err := ForCloudSpace(testCloudSpace("hcp-123.spot.rackspace.com"), ExecUser("spotctl", "auth", "print-token", "--exec-credential"), "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := cfg.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	for _, expected := range []string{"apiVersion: " + ExecAPIVersion, "command: spotctl", "- print-token", "interactiveMode: Never"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected kubeconfig to contain %q:\n%s", expected, data)
+		}
+	}
+	if strings.Contains(string(data), "token:") {
+		t.Errorf("expected no embedded token:\n%s", data)
+	}
+
+	// A user merged with an embedded token switches to exec without a conflict
+	existing := New()
+	embedded, _ := ForCloudSpace(testCloudSpace("hcp-123.spot.rackspace.com"), TokenUser("token"), "", false)
+	if err := existing.Merge(embedded, false); err != nil {
+		t.Fatalf("unexpected merge error: %v", err)
+	}
+	if err := existing.Merge(cfg, false); err != nil {
+		t.Fatalf("expected the exec user to replace the token, got %v", err)
+	}
+	if _, ok := existing.Users[0].User["exec"]; !ok || len(existing.Users) != 1 {
+		t.Errorf("expected the exec user, got %+v", existing.Users)
+	}
+}
+
+const existingKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: other
+  cluster:
+    server: https://other.example.com
+    certificate-authority-data: Zm9v
+contexts:
+- name: other
+  context:
+    cluster: other
+    user: other
+    namespace: kube-system
+users:
+- name: other
+  user:
+    client-certificate-data: YmFy
+current-context: other
+preferences: {}
+`
+
+func TestMergePreservesExistingEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(existingKubeconfig), 0600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	existing, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load kubeconfig: %v", err)
+	}
+
+	generated, err := ForCloudSpace(testCloudSpace("hcp-123.spot.rackspace.com"), TokenUser("token-1"), "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := existing.Merge(generated, false); err != nil {
+		t.Fatalf("unexpected merge error: %v", err)
+	}
+	if err := Write(path, existing); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	// Merging again with a new token updates the entry in place
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to reload kubeconfig: %v", err)
+	}
+	refreshed, _ := ForCloudSpace(testCloudSpace("hcp-123.spot.rackspace.com"), TokenUser("token-2"), "", false)
+	if err := reloaded.Merge(refreshed, false); err != nil {
+		t.Fatalf("unexpected merge error: %v", err)
+	}
+
+	if len(reloaded.Contexts) != 2 || len(reloaded.Clusters) != 2 || len(reloaded.Users) != 2 {
+		t.Fatalf("expected 2 of each entry, got %d contexts, %d clusters, %d users",
+			len(reloaded.Contexts), len(reloaded.Clusters), len(reloaded.Users))
+	}
+	if reloaded.CurrentContext != "other" {
+		t.Errorf("expected current context to be preserved, got %q", reloaded.CurrentContext)
+	}
+	if reloaded.Users[1].User["token"] != "token-2" {
+		t.Errorf("expected refreshed token, got %v", reloaded.Users[1].User["token"])
+	}
+
+	data, err := reloaded.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+	for _, expected := range []string{"certificate-authority-data: Zm9v", "namespace: kube-system", "client-certificate-data: YmFy"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected merged kubeconfig to contain %q:\n%s", expected, data)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat kubeconfig: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected permissions 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	existing := New()
+	existing.Clusters = append(existing.Clusters, NamedEntry{
+		Name:    "my-cloudspace",
+		Cluster: map[string]interface{}{"server": "https://somewhere-else.example.com"},
+	})
+
+	generated, _ := ForCloudSpace(testCloudSpace("hcp-123.spot.rackspace.com"), TokenUser("token"), "", false)
+
+	err := existing.Merge(generated, false)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected ConflictError, got %v", err)
+	}
+
+	if err := existing.Merge(generated, true); err != nil {
+		t.Fatalf("expected forced merge to succeed, got %v", err)
+	}
+	if existing.Clusters[0].Cluster["server"] != "https://hcp-123.spot.rackspace.com" {
+		t.Errorf("expected forced merge to overwrite server, got %v", existing.Clusters[0].Cluster["server"])
+	}
+}
+
+func TestMergeContextAndUserConflicts(t *testing.T) {
+	generated, _ := ForCloudSpace(testCloudSpace("hcp-123.spot.rackspace.com"), TokenUser("token"), "", false)
+
+	tests := []struct {
+		name     string
+		existing func(*Config)
+		kind     string
+	}{
+		{
+			name: "context with another cluster",
+			existing: func(c *Config) {
+				c.Contexts = append(c.Contexts, NamedEntry{
+					Name:    "my-cloudspace",
+					Context: map[string]interface{}{"cluster": "production", "user": "admin"},
+				})
+			},
+			kind: "context",
+		},
+		{
+			name: "user of another cluster",
+			existing: func(c *Config) {
+				c.Contexts = append(c.Contexts, NamedEntry{
+					Name:    "production",
+					Context: map[string]interface{}{"cluster": "production", "user": "my-cloudspace"},
+				})
+				c.Users = append(c.Users, NamedEntry{
+					Name: "my-cloudspace",
+					User: map[string]interface{}{"client-certificate-data": "cert"},
+				})
+			},
+			kind: "user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := New()
+			tt.existing(existing)
+
+			err := existing.Merge(generated, false)
+			var conflict *ConflictError
+			if !errors.As(err, &conflict) || conflict.Kind != tt.kind {
+				t.Fatalf("expected %s ConflictError, got %v", tt.kind, err)
+			}
+
+			if err := existing.Merge(generated, true); err != nil {
+				t.Fatalf("expected forced merge to succeed, got %v", err)
+			}
+			if existing.Users[len(existing.Users)-1].User["token"] != "token" {
+				t.Errorf("expected forced merge to overwrite user, got %v", existing.Users)
+			}
+		})
+	}
+
+	// Merging the same cloudspace again with a new token only refreshes it
+	existing := New()
+	if err := existing.Merge(generated, false); err != nil {
+		t.Fatalf("unexpected merge error: %v", err)
+	}
+	refreshed, _ := ForCloudSpace(testCloudSpace("hcp-123.spot.rackspace.com"), TokenUser("new-token"), "", false)
+	if err := existing.Merge(refreshed, false); err != nil {
+		t.Fatalf("expected token refresh to merge, got %v", err)
+	}
+}
+
+func TestRemoveContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(existingKubeconfig), 0600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load kubeconfig: %v", err)
+	}
+
+	generated, _ := ForCloudSpace(testCloudSpace("hcp-123.spot.rackspace.com"), TokenUser("token"), "spot", false)
+	if err := cfg.Merge(generated, false); err != nil {
+		t.Fatalf("unexpected merge error: %v", err)
+	}
+	cfg.CurrentContext = "spot"
+
+	if !cfg.RemoveContext("spot") {
+		t.Fatalf("expected context to be removed")
+	}
+	if cfg.RemoveContext("spot") {
+		t.Errorf("expected second removal to report missing context")
+	}
+
+	if len(cfg.Contexts) != 1 || cfg.Contexts[0].Name != "other" {
+		t.Errorf("expected only 'other' context to remain, got %+v", cfg.Contexts)
+	}
+	if len(cfg.Clusters) != 1 || len(cfg.Users) != 1 {
+		t.Errorf("expected cluster and user to be removed, got %d clusters, %d users", len(cfg.Clusters), len(cfg.Users))
+	}
+	if cfg.CurrentContext != "" {
+		t.Errorf("expected current context to be cleared, got %q", cfg.CurrentContext)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.APIVersion != "v1" || cfg.Kind != "Config" || len(cfg.Contexts) != 0 {
+		t.Errorf("expected empty kubeconfig, got %+v", cfg)
+	}
+}
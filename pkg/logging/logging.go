@@ -0,0 +1,54 @@
+// Package logging builds the structured logger spotctl writes diagnostics to.
+// Logs always go to stderr so they never mix with command output on stdout.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Log formats accepted by --log-format
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// DefaultLevel is the level used when no -v, --log-level or --debug is given
+const DefaultLevel = slog.LevelWarn
+
+// ParseLevel parses a --log-level value: debug, info, warn or error
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: must be one of debug, info, warn, error", value)
+	}
+	return level, nil
+}
+
+// VerbosityLevel returns the level selected by repeating -v: warnings by
+// default, info with -v and debug with -vv or more
+func VerbosityLevel(verbosity int) slog.Level {
+	switch {
+	case verbosity <= 0:
+		return DefaultLevel
+	case verbosity == 1:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// New returns a logger writing records at level or above to w in format
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch format {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be %s or %s", format, FormatText, FormatJSON)
+	}
+}
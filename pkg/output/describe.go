@@ -0,0 +1,244 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// DescribeWriter renders human-readable, multi-section resource descriptions
+// in the style of kubectl describe
+type DescribeWriter struct {
+	tw *tabwriter.Writer
+}
+
+// NewDescribeWriter creates a describe writer that writes to w.
+// Flush must be called once all sections have been written.
+func NewDescribeWriter(w io.Writer) *DescribeWriter {
+	return &DescribeWriter{
+		tw: tabwriter.NewWriter(w, 0, 8, 2, ' ', 0),
+	}
+}
+
+// Field writes a "Label: value" line at the given indentation level.
+// Empty values are rendered as <none>.
+func (d *DescribeWriter) Field(level int, label string, value interface{}) {
+	fmt.Fprintf(d.tw, "%s%s:\t%s\n", indent(level), label, formatDescribeValue(value))
+}
+
+// Section writes a section heading at the given indentation level.
+// Fields in a new section are aligned independently of the previous one.
+func (d *DescribeWriter) Section(level int, title string) {
+	d.tw.Flush()
+	fmt.Fprintf(d.tw, "%s%s:\n", indent(level), title)
+}
+
+// Table writes a table with a header row and a separator at the given indentation level.
+// If there are no rows, <none> is written next to the label instead.
+func (d *DescribeWriter) Table(level int, label string, headers []string, rows [][]string) {
+	if len(rows) == 0 {
+		fmt.Fprintf(d.tw, "%s%s:\t<none>\n", indent(level), label)
+		return
+	}
+
+	d.Section(level, label)
+	separators := make([]string, len(headers))
+	for i, header := range headers {
+		separators[i] = strings.Repeat("-", len(header))
+	}
+	prefix := indent(level + 1)
+	fmt.Fprintf(d.tw, "%s%s\n", prefix, strings.Join(headers, "\t"))
+	fmt.Fprintf(d.tw, "%s%s\n", prefix, strings.Join(separators, "\t"))
+	for _, row := range rows {
+		fmt.Fprintf(d.tw, "%s%s\n", prefix, strings.Join(row, "\t"))
+	}
+	// Keep the table's column widths from leaking into the lines that follow
+	d.tw.Flush()
+}
+
+// Map writes a string map as sorted "key=value" lines next to the label
+func (d *DescribeWriter) Map(level int, label string, values map[string]string) {
+	if len(values) == 0 {
+		d.Field(level, label, "")
+		return
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for i, key := range keys {
+		heading := ""
+		if i == 0 {
+			heading = label + ":"
+		}
+		fmt.Fprintf(d.tw, "%s%s\t%s=%s\n", indent(level), heading, key, values[key])
+	}
+}
+
+// List writes a string slice with one entry per line next to the label
+func (d *DescribeWriter) List(level int, label string, values []string) {
+	if len(values) == 0 {
+		d.Field(level, label, "")
+		return
+	}
+
+	for i, value := range values {
+		heading := ""
+		if i == 0 {
+			heading = label + ":"
+		}
+		fmt.Fprintf(d.tw, "%s%s\t%s\n", indent(level), heading, value)
+	}
+}
+
+// Object writes an arbitrary decoded JSON value (maps, slices and scalars) as an
+// indented tree under the label. Map keys are sorted so output is stable.
+func (d *DescribeWriter) Object(level int, label string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			d.Field(level, label, "")
+			return
+		}
+		d.Section(level, label)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			d.Object(level+1, key, v[key])
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			d.Field(level, label, "")
+			return
+		}
+		d.Section(level, label)
+		for i, item := range v {
+			d.Object(level+1, fmt.Sprintf("[%d]", i), item)
+		}
+	default:
+		d.Field(level, label, value)
+	}
+}
+
+// Event types, as kubectl describe shows them
+const (
+	EventNormal  = "Normal"
+	EventWarning = "Warning"
+)
+
+// Event is something that happened to a resource, pieced together from its
+// timestamps and status conditions as the API keeps no event log
+type Event struct {
+	Time    *time.Time
+	Type    string
+	Reason  string
+	Message string
+}
+
+// Events writes events oldest first as a table with their age. Events without a
+// time cannot be placed in the narrative and are left out.
+func (d *DescribeWriter) Events(level int, events []Event, now time.Time) {
+	dated := make([]Event, 0, len(events))
+	for _, event := range events {
+		if event.Time != nil && !event.Time.IsZero() {
+			dated = append(dated, event)
+		}
+	}
+	sort.SliceStable(dated, func(i, j int) bool { return dated[i].Time.Before(*dated[j].Time) })
+
+	rows := make([][]string, 0, len(dated))
+	for _, event := range dated {
+		rows = append(rows, []string{event.Type, event.Reason, Age(event.Time, now), formatDescribeValue(event.Message)})
+	}
+	d.Table(level, "Events", []string{"TYPE", "REASON", "AGE", "MESSAGE"}, rows)
+}
+
+// Flush writes any buffered output
+func (d *DescribeWriter) Flush() error {
+	return d.tw.Flush()
+}
+
+// indent returns the leading whitespace for an indentation level
+func indent(level int) string {
+	return strings.Repeat("  ", level)
+}
+
+// formatDescribeValue converts a field value into its display string
+func formatDescribeValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		if v == "" {
+			return "<none>"
+		}
+		return v
+	case *int:
+		if v == nil {
+			return "<none>"
+		}
+		return fmt.Sprintf("%d", *v)
+	case *time.Time:
+		if v == nil {
+			return "<none>"
+		}
+		return v.Format(time.RFC1123Z)
+	case float64:
+		// Decoded JSON numbers; print integers without a decimal point
+		if v == float64(int64(v)) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return fmt.Sprintf("%g", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Age returns the time elapsed since t in a short human-readable form, e.g. "5m" or "3d"
+func Age(t *time.Time, now time.Time) string {
+	if t == nil || t.IsZero() {
+		return "<unknown>"
+	}
+	return HumanDuration(now.Sub(*t))
+}
+
+// Timestamp renders t together with its age, e.g. for a Created field; nil gives ""
+func Timestamp(t *time.Time, now time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s ago)", t.Format(time.RFC1123Z), Age(t, now))
+}
+
+// HumanDuration formats a duration using the largest sensible unit, e.g. "45s", "12m", "5h" or "2d"
+func HumanDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		hours := int(d.Hours())
+		if minutes := int(d.Minutes()) % 60; hours < 10 && minutes > 0 {
+			return fmt.Sprintf("%dh%dm", hours, minutes)
+		}
+		return fmt.Sprintf("%dh", hours)
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	default:
+		return fmt.Sprintf("%dy%dd", int(d.Hours()/24/365), int(d.Hours()/24)%365)
+	}
+}
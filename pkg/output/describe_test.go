@@ -0,0 +1,155 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDescribeWriter(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewDescribeWriter(&buf)
+
+	desired := 3
+	d.Field(0, "Name", "my-cloudspace")
+	d.Field(0, "Webhook", "")
+	d.Field(0, "Desired", &desired)
+	d.Map(0, "Labels", map[string]string{"team": "platform", "env": "dev"})
+	d.Section(0, "Spec")
+	d.Field(1, "Region", "uk-lon-1")
+	d.List(1, "Taints", nil)
+	d.Table(0, "Conditions", []string{"TYPE", "STATUS"}, [][]string{{"Ready", "True"}})
+	d.Table(0, "Events", []string{"TYPE"}, nil)
+	d.Object(0, "Bids", map[string]interface{}{
+		"gp.vs1.large-lon": map[string]interface{}{"count": float64(2), "price": "0.05"},
+	})
+	if err := d.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	expected := []string{
+		"Name:",
+		"my-cloudspace",
+		"Webhook:",
+		"<none>",
+		"Desired:",
+		"Labels:",
+		"env=dev",
+		"team=platform",
+		"Spec:\n  Region:",
+		"Conditions:\n  TYPE",
+		"  ----",
+		"Ready",
+		"Events:",
+		"Bids:\n  gp.vs1.large-lon:\n    count:",
+		"price:",
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// Labels are sorted and only the first carries the heading
+	if strings.Index(out, "env=dev") > strings.Index(out, "team=platform") {
+		t.Errorf("expected labels to be sorted, got:\n%s", out)
+	}
+	if strings.Count(out, "Labels:") != 1 {
+		t.Errorf("expected a single Labels heading, got:\n%s", out)
+	}
+	if strings.Contains(out, "2e+00") || strings.Contains(out, "2.0") {
+		t.Errorf("expected integer JSON numbers without decimals, got:\n%s", out)
+	}
+}
+
+func TestDescribeEvents(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+
+	var buf bytes.Buffer
+	d := NewDescribeWriter(&buf)
+	d.Events(0, []Event{
+		{Time: at(time.Hour), Type: EventNormal, Reason: "Ready", Message: "became ready"},
+		{Time: nil, Type: EventNormal, Reason: "Undated"},
+		{Time: at(3 * time.Hour), Type: EventNormal, Reason: "Created"},
+		{Time: at(time.Minute), Type: EventWarning, Reason: "Deleting", Message: "deletion requested"},
+	}, now)
+	if err := d.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	created, ready, deleting := strings.Index(out, "Created"), strings.Index(out, "Ready"), strings.Index(out, "Deleting")
+	if created < 0 || created > ready || ready > deleting {
+		t.Errorf("expected events oldest first, got:\n%s", out)
+	}
+	if strings.Contains(out, "Undated") {
+		t.Errorf("expected events without a time to be left out, got:\n%s", out)
+	}
+	for _, want := range []string{"TYPE", "3h", "Warning", "deletion requested"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	d = NewDescribeWriter(&buf)
+	d.Events(0, []Event{{Reason: "Undated"}}, now)
+	d.Flush()
+	if !strings.Contains(buf.String(), "Events:") || !strings.Contains(buf.String(), "<none>") {
+		t.Errorf("expected no events to be shown as <none>, got:\n%s", buf.String())
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{-5 * time.Second, "0s"},
+		{45 * time.Second, "45s"},
+		{12 * time.Minute, "12m"},
+		{90 * time.Minute, "1h30m"},
+		{5 * time.Hour, "5h"},
+		{30 * time.Hour, "30h"},
+		{72 * time.Hour, "3d"},
+		{400 * 24 * time.Hour, "1y35d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := HumanDuration(tt.duration); got != tt.expected {
+				t.Errorf("HumanDuration(%v) = %q, want %q", tt.duration, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAge(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	created := now.Add(-26 * time.Hour)
+
+	if got := Age(&created, now); got != "26h" {
+		t.Errorf("Age() = %q, want %q", got, "26h")
+	}
+	if got := Age(nil, now); got != "<unknown>" {
+		t.Errorf("Age(nil) = %q, want %q", got, "<unknown>")
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	created := now.Add(-26 * time.Hour)
+
+	if got, want := Timestamp(&created, now), "Sun, 31 Dec 2023 22:00:00 +0000 (26h ago)"; got != want {
+		t.Errorf("Timestamp() = %q, want %q", got, want)
+	}
+	if got := Timestamp(nil, now); got != "" {
+		t.Errorf("Timestamp(nil) = %q, want empty", got)
+	}
+}
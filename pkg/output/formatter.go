@@ -1,6 +1,8 @@
 package output
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -22,12 +24,23 @@ const (
 	JSONFormat  OutputFormat = "json"
 	YAMLFormat  OutputFormat = "yaml"
 	WideFormat  OutputFormat = "wide"
+	CSVFormat   OutputFormat = "csv"
+	NameFormat  OutputFormat = "name"
+
+	// JSONPathPrefix starts a format such as jsonpath={.items[*].metadata.name}
+	JSONPathPrefix = "jsonpath="
 )
 
 // OutputOptions contains options for formatting output
 type OutputOptions struct {
 	Format      OutputFormat
 	ShowDetails bool
+	// Resource prefixes the names printed by the name format, e.g. spotnodepool;
+	// the lower case kind of each item is used if empty
+	Resource string
+	// Namespaced prefixes the names printed by the name format with the namespace
+	// of each item that has one, e.g. org-abc123/spotnodepool/my-pool
+	Namespaced bool
 }
 
 // TableColumn represents a column in table output
@@ -90,11 +103,73 @@ func (f *Formatter) OutputToWriter(w io.Writer, data interface{}, tableConfig *T
 			f.options.ShowDetails = true
 		}
 		return f.outputTableToWriter(w, data, tableConfig)
+	case CSVFormat:
+		if tableConfig == nil {
+			return fmt.Errorf("table configuration required for csv output")
+		}
+		return f.outputCSVToWriter(w, data, tableConfig)
+	case NameFormat:
+		return f.outputNamesToWriter(w, data)
 	default:
+		if template, ok := strings.CutPrefix(string(f.options.Format), JSONPathPrefix); ok {
+			return f.outputJSONPathToWriter(w, data, template)
+		}
 		return fmt.Errorf("unsupported output format: %s", f.options.Format)
 	}
 }
 
+// outputJSONPathToWriter outputs the values selected by a JSONPath template. Lists
+// are addressed as .items, as in kubectl, even where -o json prints them as an array.
+func (f *Formatter) outputJSONPathToWriter(w io.Writer, data interface{}, template string) error {
+	jsonPath, err := ParseJSONPath(template)
+	if err != nil {
+		return err
+	}
+	if v := reflect.Indirect(reflect.ValueOf(data)); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		data = map[string]interface{}{"items": data}
+	}
+
+	var buf bytes.Buffer
+	if err := jsonPath.Execute(&buf, data); err != nil {
+		return err
+	}
+	// End with a newline unless the template already does
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// outputNamesToWriter outputs one resource/name line per item, e.g. spotnodepool/my-pool,
+// which commands taking --filename accept back
+func (f *Formatter) outputNamesToWriter(w io.Writer, data interface{}) error {
+	items, err := f.extractItems(data)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		name := f.getFieldValue(item, "metadata.name")
+		if name == "" {
+			name = f.getFieldValue(item, "name")
+		}
+		resource := f.options.Resource
+		if resource == "" {
+			resource = strings.ToLower(f.getFieldValue(item, "kind"))
+		}
+		if resource != "" {
+			name = resource + "/" + name
+		}
+		if namespace := f.getFieldValue(item, "metadata.namespace"); f.options.Namespaced && namespace != "" {
+			name = namespace + "/" + name
+		}
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // outputJSON outputs data as formatted JSON
 func (f *Formatter) outputJSON(data interface{}) error {
 	return f.outputJSONToWriter(os.Stdout, data)
@@ -180,6 +255,39 @@ func (f *Formatter) outputTableToWriter(w io.Writer, data interface{}, config *T
 	return nil
 }
 
+// outputCSVToWriter outputs data as CSV to the specified writer.
+// All columns, including detail columns, are written and values are never truncated.
+func (f *Formatter) outputCSVToWriter(w io.Writer, data interface{}, config *TableConfig) error {
+	items, err := f.extractItems(data)
+	if err != nil {
+		return err
+	}
+
+	columns := append(append([]TableColumn{}, config.Columns...), config.DetailCols...)
+
+	cw := csv.NewWriter(w)
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.Header
+	}
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = f.getFieldValue(item, col.Field)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
 // extractItems extracts a slice of items from the data
 // Handles both single items and list structures (like RegionList)
 func (f *Formatter) extractItems(data interface{}) ([]interface{}, error) {
@@ -249,6 +357,14 @@ func (f *Formatter) getFieldValue(item interface{}, fieldPath string) string {
 			return ""
 		}
 
+		// Look through interfaces, e.g. rows holding an item of any kind
+		if field.Kind() == reflect.Interface {
+			if field.IsNil() {
+				return ""
+			}
+			field = field.Elem()
+		}
+
 		// Handle pointers in the field chain
 		if field.Kind() == reflect.Ptr {
 			if field.IsNil() {
@@ -265,6 +381,13 @@ func (f *Formatter) getFieldValue(item interface{}, fieldPath string) string {
 		return ""
 	}
 
+	// Types with their own representation (e.g. fixed-point amounts) take precedence over their kind
+	if v.CanInterface() {
+		if stringer, ok := v.Interface().(fmt.Stringer); ok {
+			return stringer.String()
+		}
+	}
+
 	switch v.Kind() {
 	case reflect.String:
 		return v.String()
@@ -311,5 +434,22 @@ func (f *Formatter) findField(v reflect.Value, fieldName string) reflect.Value {
 		}
 	}
 
+	// Finally look inside embedded structs, so rows can wrap an item with extra columns
+	for i := 0; i < v.NumField(); i++ {
+		if !t.Field(i).Anonymous {
+			continue
+		}
+		embedded := v.Field(i)
+		if embedded.Kind() == reflect.Ptr {
+			if embedded.IsNil() {
+				continue
+			}
+			embedded = embedded.Elem()
+		}
+		if field := f.findField(embedded, fieldName); field.IsValid() {
+			return field
+		}
+	}
+
 	return reflect.Value{}
 }
@@ -16,8 +16,9 @@ type TestRegion struct {
 }
 
 type TestMetadata struct {
-	Name string `json:"name,omitempty"`
-	UID  string `json:"uid,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	UID       string `json:"uid,omitempty"`
 }
 
 type TestSpec struct {
@@ -215,6 +216,84 @@ func TestFormatter_OutputTableDetails(t *testing.T) {
 	}
 }
 
+func TestFormatter_OutputCSV(t *testing.T) {
+	data := &TestRegionList{
+		Items: []TestRegion{
+			{
+				Metadata: TestMetadata{Name: "uk-lon-1"},
+				Spec:     TestSpec{Country: "United Kingdom", Description: "London, \"central\""},
+			},
+			{
+				Metadata: TestMetadata{Name: "empty-region"},
+			},
+		},
+	}
+
+	config := &TableConfig{
+		Columns: []TableColumn{
+			{Header: "NAME", Field: "metadata.name"},
+			{Header: "COUNTRY", Field: "spec.country", Default: "N/A", Width: 5},
+		},
+		DetailCols: []TableColumn{
+			{Header: "DESCRIPTION", Field: "spec.description"},
+		},
+	}
+
+	formatter := NewFormatter(OutputOptions{Format: CSVFormat})
+
+	var buf bytes.Buffer
+	if err := formatter.OutputToWriter(&buf, data, config); err != nil {
+		t.Fatalf("OutputToWriter failed: %v", err)
+	}
+
+	expected := "NAME,COUNTRY,DESCRIPTION\n" +
+		"uk-lon-1,United Kingdom,\"London, \"\"central\"\"\"\n" +
+		"empty-region,,\n"
+	if buf.String() != expected {
+		t.Errorf("CSV output = %q, want %q", buf.String(), expected)
+	}
+
+	if err := formatter.OutputToWriter(&buf, data, nil); err == nil {
+		t.Error("Expected error for CSV output without table configuration")
+	}
+}
+
+func TestFormatter_OutputNames(t *testing.T) {
+	data := &TestRegionList{
+		Items: []TestRegion{
+			{Kind: "Region", Metadata: TestMetadata{Name: "uk-lon-1"}},
+			{Kind: "Region", Metadata: TestMetadata{Name: "us-east-iad-1"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewFormatter(OutputOptions{Format: NameFormat, Resource: "region"}).OutputToWriter(&buf, data, nil); err != nil {
+		t.Fatalf("OutputToWriter failed: %v", err)
+	}
+	if expected := "region/uk-lon-1\nregion/us-east-iad-1\n"; buf.String() != expected {
+		t.Errorf("Name output = %q, want %q", buf.String(), expected)
+	}
+
+	// Without a resource the kind of each item is used
+	buf.Reset()
+	if err := NewFormatter(OutputOptions{Format: NameFormat}).OutputToWriter(&buf, &data.Items[0], nil); err != nil {
+		t.Fatalf("OutputToWriter failed: %v", err)
+	}
+	if expected := "region/uk-lon-1\n"; buf.String() != expected {
+		t.Errorf("Name output = %q, want %q", buf.String(), expected)
+	}
+
+	// Namespaced names are prefixed with the namespace of items that have one
+	data.Items[1].Metadata.Namespace = "org-abc123"
+	buf.Reset()
+	if err := NewFormatter(OutputOptions{Format: NameFormat, Resource: "region", Namespaced: true}).OutputToWriter(&buf, data, nil); err != nil {
+		t.Fatalf("OutputToWriter failed: %v", err)
+	}
+	if expected := "region/uk-lon-1\norg-abc123/region/us-east-iad-1\n"; buf.String() != expected {
+		t.Errorf("Name output = %q, want %q", buf.String(), expected)
+	}
+}
+
 func TestFormatter_GetFieldValue(t *testing.T) {
 	formatter := NewFormatter(OutputOptions{})
 
@@ -246,3 +325,19 @@ func TestFormatter_GetFieldValue(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatter_GetFieldValueEmbedded(t *testing.T) {
+	formatter := NewFormatter(OutputOptions{})
+
+	row := struct {
+		Org string `json:"org"`
+		TestRegion
+	}{Org: "acme", TestRegion: TestRegion{Metadata: TestMetadata{Name: "test-region"}}}
+
+	if got := formatter.getFieldValue(row, "org"); got != "acme" {
+		t.Errorf("getFieldValue(org) = %q, expected acme", got)
+	}
+	if got := formatter.getFieldValue(row, "metadata.name"); got != "test-region" {
+		t.Errorf("getFieldValue(metadata.name) = %q, expected test-region", got)
+	}
+}
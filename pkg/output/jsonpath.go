@@ -0,0 +1,560 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a parsed kubectl style JSONPath template such as
+// '{range .items[*]}{.metadata.name}{"\n"}{end}'.
+//
+// Supported expressions are fields (.name, ['name']), wildcards (.*, [*]),
+// indices and slices ([0], [-1], [1:3]), recursive descent (..name), filters
+// comparing a field with a literal ([?(@.status.phase=="Ready")] or testing
+// that it exists ([?(@.spec.bidPrice)]), string literals ({"\n"}) and
+// {range}...{end} blocks. Missing fields produce no output rather than an error.
+type JSONPath struct {
+	nodes []templateNode
+}
+
+// templateNode is a piece of a template: literal text, an expression or a range block
+type templateNode struct {
+	text  string
+	path  []pathStep
+	expr  bool // path is printed
+	block bool // body is executed for every value of path
+	body  []templateNode
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepWildcard
+	stepRecursive
+	stepIndex
+	stepSlice
+	stepFilter
+)
+
+// pathStep is one step of an expression, applied to every value the previous step produced
+type pathStep struct {
+	kind       stepKind
+	name       string
+	index      int
+	start, end *int
+	filter     *pathFilter
+}
+
+// pathFilter keeps the elements whose path compares with a literal, or where it exists
+type pathFilter struct {
+	path    []pathStep
+	op      string
+	literal any
+}
+
+// ParseJSONPath parses a JSONPath template
+func ParseJSONPath(template string) (*JSONPath, error) {
+	p := &jsonPathParser{template: template}
+	nodes, err := p.parseNodes(false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jsonpath %q: %w", template, err)
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+type jsonPathParser struct {
+	template string
+	pos      int
+}
+
+// parseNodes parses until the end of the template or, inside a range block, its {end}
+func (p *jsonPathParser) parseNodes(inRange bool) ([]templateNode, error) {
+	var nodes []templateNode
+	for p.pos < len(p.template) {
+		open := strings.IndexByte(p.template[p.pos:], '{')
+		if open < 0 {
+			nodes = append(nodes, templateNode{text: p.template[p.pos:]})
+			p.pos = len(p.template)
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, templateNode{text: p.template[p.pos : p.pos+open]})
+		}
+		p.pos += open
+
+		end, err := matchingClose(p.template, p.pos, '{', '}')
+		if err != nil {
+			return nil, err
+		}
+		expr := strings.TrimSpace(p.template[p.pos+1 : end])
+		p.pos = end + 1
+
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, fmt.Errorf("{end} without {range}")
+			}
+			return nodes, nil
+		case strings.HasPrefix(expr, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, err
+			}
+			body, err := p.parseNodes(true)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, templateNode{path: path, body: body, block: true})
+		case strings.HasPrefix(expr, `"`) || strings.HasPrefix(expr, "'"):
+			text, err := unquote(expr)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, templateNode{text: text})
+		default:
+			path, err := parsePath(expr)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, templateNode{path: path, expr: true})
+		}
+	}
+	if inRange {
+		return nil, fmt.Errorf("{range} without {end}")
+	}
+	return nodes, nil
+}
+
+// parsePath parses an expression such as .items[*].metadata.name
+func parsePath(expr string) ([]pathStep, error) {
+	expr = strings.TrimPrefix(expr, "$")
+	var steps []pathStep
+	for i := 0; i < len(expr); {
+		switch {
+		case strings.HasPrefix(expr[i:], ".."):
+			name, next := readName(expr, i+2)
+			if name == "" {
+				return nil, fmt.Errorf("expected a field name after '..' at %d", i)
+			}
+			steps = append(steps, pathStep{kind: stepRecursive, name: name})
+			i = next
+		case expr[i] == '.':
+			name, next := readName(expr, i+1)
+			switch name {
+			case "":
+				// A lone '.' is the current object
+			case "*":
+				steps = append(steps, pathStep{kind: stepWildcard})
+			default:
+				steps = append(steps, pathStep{kind: stepField, name: name})
+			}
+			i = next
+		case expr[i] == '[':
+			end, err := matchingClose(expr, i, '[', ']')
+			if err != nil {
+				return nil, err
+			}
+			step, err := parseSubscript(strings.TrimSpace(expr[i+1 : end]))
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+			i = end + 1
+		default:
+			// A leading field may omit its dot, e.g. {items[0]}
+			name, next := readName(expr, i)
+			if name == "" || i > 0 {
+				return nil, fmt.Errorf("unexpected %q at %d", expr[i], i)
+			}
+			steps = append(steps, pathStep{kind: stepField, name: name})
+			i = next
+		}
+	}
+	return steps, nil
+}
+
+// readName reads a field name or '*' starting at i
+func readName(expr string, i int) (string, int) {
+	start := i
+	for i < len(expr) && expr[i] != '.' && expr[i] != '[' {
+		i++
+	}
+	return expr[start:i], i
+}
+
+// parseSubscript parses the inside of [...]
+func parseSubscript(inner string) (pathStep, error) {
+	switch {
+	case inner == "*":
+		return pathStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(inner, `"`) || strings.HasPrefix(inner, "'"):
+		name, err := unquote(inner)
+		return pathStep{kind: stepField, name: name}, err
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		filter, err := parseFilter(strings.TrimSpace(inner[2 : len(inner)-1]))
+		return pathStep{kind: stepFilter, filter: filter}, err
+	case strings.Contains(inner, ":"):
+		step := pathStep{kind: stepSlice}
+		bounds := strings.SplitN(inner, ":", 2)
+		for i, bound := range bounds {
+			bound = strings.TrimSpace(bound)
+			if bound == "" {
+				continue
+			}
+			n, err := strconv.Atoi(bound)
+			if err != nil {
+				return step, fmt.Errorf("invalid slice [%s]", inner)
+			}
+			if i == 0 {
+				step.start = &n
+			} else {
+				step.end = &n
+			}
+		}
+		return step, nil
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return pathStep{}, fmt.Errorf("invalid subscript [%s]", inner)
+	}
+	return pathStep{kind: stepIndex, index: n}, nil
+}
+
+// filterOperators are tried longest first so <= is not read as <
+var filterOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// parseFilter parses '@.path', or '@.path OP literal'
+func parseFilter(expr string) (*pathFilter, error) {
+	left, op, right := expr, "", ""
+	for _, candidate := range filterOperators {
+		if i := indexOutsideQuotes(expr, candidate); i >= 0 {
+			left, op, right = strings.TrimSpace(expr[:i]), candidate, strings.TrimSpace(expr[i+len(candidate):])
+			break
+		}
+	}
+	if !strings.HasPrefix(left, "@") {
+		return nil, fmt.Errorf("filter %q must start with @", expr)
+	}
+	path, err := parsePath(left[1:])
+	if err != nil {
+		return nil, err
+	}
+	filter := &pathFilter{path: path, op: op}
+	if op == "" {
+		return filter, nil
+	}
+
+	switch {
+	case strings.HasPrefix(right, `"`) || strings.HasPrefix(right, "'"):
+		filter.literal, err = unquote(right)
+	case right == "true" || right == "false":
+		filter.literal = right == "true"
+	case right == "null":
+		filter.literal = nil
+	default:
+		var number float64
+		number, err = strconv.ParseFloat(right, 64)
+		filter.literal = number
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid literal %q in filter", right)
+	}
+	return filter, nil
+}
+
+// matchingClose returns the index of the bracket closing the one at open, skipping quoted text
+func matchingClose(s string, open int, openChar, closeChar byte) (int, error) {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == openChar:
+			depth++
+		case c == closeChar:
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed %q at %d", openChar, open)
+}
+
+// indexOutsideQuotes returns the index of the first sub outside quoted text, or -1
+func indexOutsideQuotes(s, sub string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(s[i:], sub):
+			return i
+		}
+	}
+	return -1
+}
+
+// unquote removes the quotes of a single or double quoted string, interpreting escapes
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") && len(s) >= 2 {
+		s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// Execute writes the template evaluated against data, which is converted to its
+// JSON form first so struct fields are addressed by their JSON names
+func (j *JSONPath) Execute(w io.Writer, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode data: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var root any
+	if err := decoder.Decode(&root); err != nil {
+		return fmt.Errorf("failed to decode data: %w", err)
+	}
+	return executeNodes(w, j.nodes, root)
+}
+
+func executeNodes(w io.Writer, nodes []templateNode, current any) error {
+	for _, node := range nodes {
+		switch {
+		case node.block:
+			for _, value := range evaluate(node.path, current) {
+				if err := executeNodes(w, node.body, value); err != nil {
+					return err
+				}
+			}
+		case node.expr:
+			values := evaluate(node.path, current)
+			texts := make([]string, 0, len(values))
+			for _, value := range values {
+				text, err := formatValue(value)
+				if err != nil {
+					return err
+				}
+				texts = append(texts, text)
+			}
+			if _, err := io.WriteString(w, strings.Join(texts, " ")); err != nil {
+				return err
+			}
+		default:
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// evaluate applies steps to current, returning every value reached
+func evaluate(steps []pathStep, current any) []any {
+	values := []any{current}
+	for _, step := range steps {
+		var next []any
+		for _, value := range values {
+			next = append(next, applyStep(step, value)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func applyStep(step pathStep, value any) []any {
+	switch step.kind {
+	case stepField:
+		if object, ok := value.(map[string]any); ok {
+			if field, ok := object[step.name]; ok {
+				return []any{field}
+			}
+		}
+	case stepWildcard:
+		return children(value)
+	case stepRecursive:
+		var found []any
+		walk(value, func(v any) {
+			if step.name == "*" {
+				found = append(found, children(v)...)
+			} else if object, ok := v.(map[string]any); ok {
+				if field, ok := object[step.name]; ok {
+					found = append(found, field)
+				}
+			}
+		})
+		return found
+	case stepIndex:
+		if list, ok := value.([]any); ok {
+			i := step.index
+			if i < 0 {
+				i += len(list)
+			}
+			if i >= 0 && i < len(list) {
+				return []any{list[i]}
+			}
+		}
+	case stepSlice:
+		if list, ok := value.([]any); ok {
+			start, end := sliceBound(step.start, 0, len(list)), sliceBound(step.end, len(list), len(list))
+			if start < end {
+				return list[start:end]
+			}
+		}
+	case stepFilter:
+		var kept []any
+		for _, element := range children(value) {
+			if step.filter.matches(element) {
+				kept = append(kept, element)
+			}
+		}
+		return kept
+	}
+	return nil
+}
+
+// sliceBound resolves an optional, possibly negative, slice bound within [0, length]
+func sliceBound(bound *int, fallback, length int) int {
+	if bound == nil {
+		return fallback
+	}
+	i := *bound
+	if i < 0 {
+		i += length
+	}
+	return max(0, min(i, length))
+}
+
+// children returns the elements of a list or the values of an object in key order
+func children(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]any, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, v[key])
+		}
+		return values
+	}
+	return nil
+}
+
+// walk calls visit on value and everything nested in it, depth first
+func walk(value any, visit func(any)) {
+	visit(value)
+	for _, child := range children(value) {
+		walk(child, visit)
+	}
+}
+
+func (f *pathFilter) matches(element any) bool {
+	values := evaluate(f.path, element)
+	if f.op == "" {
+		return len(values) > 0
+	}
+	if len(values) == 0 {
+		return false
+	}
+	return compare(values[0], f.op, f.literal)
+}
+
+// compare compares a JSON value with a filter literal; numbers compare numerically
+func compare(value any, op string, literal any) bool {
+	if number, ok := literal.(float64); ok {
+		actual, ok := value.(json.Number)
+		if !ok {
+			return op == "!="
+		}
+		n, err := actual.Float64()
+		if err != nil {
+			return false
+		}
+		switch op {
+		case "==":
+			return n == number
+		case "!=":
+			return n != number
+		case "<":
+			return n < number
+		case "<=":
+			return n <= number
+		case ">":
+			return n > number
+		case ">=":
+			return n >= number
+		}
+		return false
+	}
+
+	// Objects and lists equal no literal
+	switch value.(type) {
+	case map[string]any, []any:
+		return op == "!="
+	}
+	switch op {
+	case "==":
+		return value == literal
+	case "!=":
+		return value != literal
+	}
+	a, aok := value.(string)
+	b, bok := literal.(string)
+	if !aok || !bok {
+		return false
+	}
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+// formatValue prints strings and numbers as they are and objects and lists as JSON
+func formatValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case nil:
+		return "", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	raw, err := json.Marshal(value)
+	return string(raw), err
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+const jsonPathData = `{
+  "kind": "SpotNodePoolList",
+  "items": [
+    {"metadata": {"name": "pool-a", "labels": {"team": "web"}}, "spec": {"desired": 2, "bidPrice": "0.080"}, "status": {"phase": "Ready"}},
+    {"metadata": {"name": "pool-b"}, "spec": {"desired": 5}, "status": {"phase": "Pending"}},
+    {"metadata": {"name": "pool-c"}, "spec": {"desired": 3, "bidPrice": "0.120"}, "status": {"phase": "Ready"}}
+  ]
+}`
+
+func TestJSONPath(t *testing.T) {
+	var data any
+	if err := json.Unmarshal([]byte(jsonPathData), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: "{.kind}", want: "SpotNodePoolList"},
+		{template: "{$.kind}", want: "SpotNodePoolList"},
+		{template: "{.items[*].metadata.name}", want: "pool-a pool-b pool-c"},
+		{template: "{.items[0].spec.desired}", want: "2"},
+		{template: "{.items[-1].metadata.name}", want: "pool-c"},
+		{template: "{.items[1:].metadata.name}", want: "pool-b pool-c"},
+		{template: "{.items[0].metadata['name']}", want: "pool-a"},
+		{template: "{.items[0].metadata.labels}", want: `{"team":"web"}`},
+		{template: "{..bidPrice}", want: "0.080 0.120"},
+		{template: `{.items[?(@.status.phase=="Ready")].metadata.name}`, want: "pool-a pool-c"},
+		{template: `{.items[?(@.spec.desired>2)].metadata.name}`, want: "pool-b pool-c"},
+		{template: `{.items[?(@.spec.bidPrice)].metadata.name}`, want: "pool-a pool-c"},
+		{template: `{range .items[*]}{.metadata.name}={.status.phase}{"\n"}{end}`, want: "pool-a=Ready\npool-b=Pending\npool-c=Ready\n"},
+		{template: "name: {.items[0].metadata.name}", want: "name: pool-a"},
+		{template: "{.items[0].missing}", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			jsonPath, err := ParseJSONPath(tt.template)
+			if err != nil {
+				t.Fatalf("ParseJSONPath failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := jsonPath.Execute(&buf, data); err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	for _, template := range []string{"{.items[", "{range .items[*]}{.name}", "{end}", "{.items[x]}", `{.items[?(.a=="b")]}`} {
+		if _, err := ParseJSONPath(template); err == nil {
+			t.Errorf("Expected an error parsing %q", template)
+		}
+	}
+}
+
+func TestFormatter_OutputJSONPath(t *testing.T) {
+	data := &TestRegionList{Items: []TestRegion{{Metadata: TestMetadata{Name: "uk-lon-1"}}, {Metadata: TestMetadata{Name: "us-dfw-1"}}}}
+
+	var buf bytes.Buffer
+	formatter := NewFormatter(OutputOptions{Format: JSONPathPrefix + "{.items[*].metadata.name}"})
+	if err := formatter.OutputToWriter(&buf, data, nil); err != nil {
+		t.Fatalf("OutputToWriter failed: %v", err)
+	}
+	if buf.String() != "uk-lon-1 us-dfw-1\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+
+	// Lists printed as arrays are addressed as .items, and a template ending in a newline gets no second one
+	buf.Reset()
+	formatter = NewFormatter(OutputOptions{Format: JSONPathPrefix + `{range .items[*]}{.metadata.name}{"\n"}{end}`})
+	if err := formatter.OutputToWriter(&buf, data.Items, nil); err != nil {
+		t.Fatalf("OutputToWriter failed: %v", err)
+	}
+	if buf.String() != "uk-lon-1\nus-dfw-1\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
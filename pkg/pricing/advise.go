@@ -0,0 +1,310 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// Risk is how much risk of losing an auction (and the servers with it) is acceptable
+type Risk string
+
+// Supported risk levels
+const (
+	RiskLow    Risk = "low"
+	RiskMedium Risk = "medium"
+	RiskHigh   Risk = "high"
+)
+
+// ParseRisk parses a risk level
+func ParseRisk(value string) (Risk, error) {
+	switch Risk(value) {
+	case RiskLow, RiskMedium, RiskHigh:
+		return Risk(value), nil
+	default:
+		return "", fmt.Errorf("invalid risk %q (use low, medium or high)", value)
+	}
+}
+
+// targetWinProbability is the share of past auctions a bid should have won at each risk level
+func (r Risk) targetWinProbability() float64 {
+	switch r {
+	case RiskLow:
+		return 0.95
+	case RiskHigh:
+		return 0.5
+	default:
+		return 0.8
+	}
+}
+
+// marketMarkupPercent is the markup over the market price used when there is no price history
+func (r Risk) marketMarkupPercent() int {
+	switch r {
+	case RiskLow:
+		return 25
+	case RiskHigh:
+		return 0
+	default:
+		return 10
+	}
+}
+
+// bidIncrement is the granularity bids are rounded up to: a tenth of a cent
+const bidIncrement Amount = 1_000
+
+// scarceSupplyRatio is the share of capacity still available below which supply is considered tight
+const scarceSupplyRatio = 0.1
+
+// AdviceInput is everything the bid advisor takes into account
+type AdviceInput struct {
+	ServerClass client.ServerClass
+	Nodes       int
+	Risk        Risk
+	// History holds the hammer prices of past auctions
+	History []Amount
+	// Percentiles maps a percentile (0-100) to the hammer price at that percentile
+	Percentiles map[int]Amount
+	// Unavailable names the price data the API has none of for the server class,
+	// e.g. "price history", so the advice can say what it was made without
+	Unavailable []string
+}
+
+// Advice is a recommended bid price with the reasoning behind it
+type Advice struct {
+	ServerClass string `json:"serverClass" yaml:"serverClass"`
+	Nodes       int    `json:"nodes" yaml:"nodes"`
+	Risk        Risk   `json:"risk" yaml:"risk"`
+	BidPrice    Amount `json:"bidPrice" yaml:"bidPrice"`
+	// WinProbability is the estimated chance the bid wins an auction, or nil if it cannot be estimated
+	WinProbability *float64 `json:"winProbability,omitempty" yaml:"winProbability,omitempty"`
+	// MaxHourlyCost is what the pool costs per hour if every auction clears at the bid price
+	MaxHourlyCost Amount   `json:"maxHourlyCost" yaml:"maxHourlyCost"`
+	Reasons       []string `json:"reasons" yaml:"reasons"`
+	Warnings      []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// ParsePriceHistory converts an API price history into hammer prices, skipping unparsable points
+func ParsePriceHistory(history *client.PriceHistory) []Amount {
+	if history == nil {
+		return nil
+	}
+	prices := make([]Amount, 0, len(history.History))
+	for _, point := range history.History {
+		if price, err := Parse(point.Price); err == nil {
+			prices = append(prices, price)
+		}
+	}
+	return prices
+}
+
+// ParsePercentiles converts API percentile info into prices keyed by percentile, skipping unparsable entries
+func ParsePercentiles(info *client.PercentileInfo) map[int]Amount {
+	if info == nil {
+		return nil
+	}
+	percentiles := make(map[int]Amount, len(info.Percentiles))
+	for key, value := range info.Percentiles {
+		percentile, err := strconv.Atoi(key)
+		if err != nil || percentile < 0 || percentile > 100 {
+			continue
+		}
+		if price, err := Parse(value); err == nil {
+			percentiles[percentile] = price
+		}
+	}
+	return percentiles
+}
+
+// Advise recommends a bid price for a server class. The bid is chosen so that it
+// would have won the share of recent auctions matching the risk level, then raised
+// to at least the current market price and further when supply is scarce.
+func Advise(input AdviceInput) (*Advice, error) {
+	serverClass := input.ServerClass
+	name := serverClass.Metadata.Name
+	if input.Nodes < 1 {
+		return nil, fmt.Errorf("nodes must be at least 1")
+	}
+	if input.Risk == "" {
+		input.Risk = RiskMedium
+	}
+
+	advice := &Advice{ServerClass: name, Nodes: input.Nodes, Risk: input.Risk, Reasons: []string{}}
+	target := input.Risk.targetWinProbability()
+
+	marketPrice, marketErr := SpotHourly(serverClass)
+	var hammerPrice *Amount
+	if price, err := Parse(serverClass.Status.SpotPricing.HammerPricePerHour); err == nil {
+		hammerPrice = &price
+	}
+
+	// Start from the auction history, falling back to percentiles and then the market price
+	var bid Amount
+	switch {
+	case len(input.History) > 0:
+		bid = quantile(input.History, target)
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+			"%.0f%% of the last %d auctions cleared at or below $%s/hour", target*100, len(input.History), bid))
+	case len(input.Percentiles) > 0:
+		percentile, price := percentileAtLeast(input.Percentiles, target*100)
+		bid = price
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+			"the %s percentile of recent hammer prices is $%s/hour", ordinal(percentile), price))
+	case marketErr == nil:
+		markup := input.Risk.marketMarkupPercent()
+		bid = marketPrice.Mul(100 + markup).Div(100)
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+			"no price history available, bidding %d%% above the market price", markup))
+	case hammerPrice != nil:
+		bid = *hammerPrice
+		advice.Reasons = append(advice.Reasons, "no price history or market price available, bidding the last hammer price")
+	default:
+		return nil, fmt.Errorf("server class %q has no price information to base a bid on", name)
+	}
+
+	for _, data := range input.Unavailable {
+		advice.Warnings = append(advice.Warnings, fmt.Sprintf("the API has no %s for this server class, so the bid does not take it into account", data))
+	}
+
+	if marketErr == nil && bid < marketPrice {
+		bid = marketPrice
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+			"raised to the current market price of $%s/hour, a lower bid would not win today", marketPrice))
+	}
+	if hammerPrice != nil {
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf("the last auction cleared at $%s/hour", *hammerPrice))
+	}
+	if lastAuction := serverClass.Status.LastAuction; lastAuction != nil {
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf("%d servers were sold in the last auction", *lastAuction))
+	}
+
+	// Adjust for supply
+	available, capacity := serverClass.Status.Available, serverClass.Status.Capacity
+	if available != nil {
+		if *available < input.Nodes {
+			advice.Warnings = append(advice.Warnings, fmt.Sprintf(
+				"only %d servers are available but %d were requested; the pool may not be fully filled at any price", *available, input.Nodes))
+		}
+		if capacity != nil && *capacity > 0 && float64(*available)/float64(*capacity) < scarceSupplyRatio {
+			bid = bid.Mul(110).Div(100)
+			advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+				"raised by 10%% because only %d of %d servers are available", *available, *capacity))
+		}
+	}
+
+	bid = roundUp(bid, bidIncrement)
+	advice.BidPrice = bid
+	advice.MaxHourlyCost = bid.Mul(input.Nodes)
+
+	switch {
+	case len(input.History) > 0:
+		probability := winShare(input.History, bid)
+		advice.WinProbability = &probability
+	case len(input.Percentiles) > 0:
+		probability := interpolatePercentile(input.Percentiles, bid)
+		advice.WinProbability = &probability
+	default:
+		advice.Warnings = append(advice.Warnings, "win probability cannot be estimated without price history")
+	}
+
+	if onDemand, err := OnDemandHourly(serverClass); err == nil && bid >= onDemand {
+		advice.Warnings = append(advice.Warnings, fmt.Sprintf(
+			"the bid is at or above the on-demand price of $%s/hour; consider an on-demand node pool", onDemand))
+	}
+
+	return advice, nil
+}
+
+// quantile returns the smallest price at or above the given share of prices
+func quantile(prices []Amount, share float64) Amount {
+	sorted := append([]Amount(nil), prices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(math.Ceil(share*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+// winShare returns the share of prices at or below the bid
+func winShare(prices []Amount, bid Amount) float64 {
+	won := 0
+	for _, price := range prices {
+		if price <= bid {
+			won++
+		}
+	}
+	return float64(won) / float64(len(prices))
+}
+
+// percentileAtLeast returns the lowest known percentile at or above the target,
+// or the highest known percentile if none reaches it
+func percentileAtLeast(percentiles map[int]Amount, target float64) (int, Amount) {
+	keys := sortedPercentiles(percentiles)
+	for _, key := range keys {
+		if float64(key) >= target {
+			return key, percentiles[key]
+		}
+	}
+	last := keys[len(keys)-1]
+	return last, percentiles[last]
+}
+
+// interpolatePercentile estimates the share of auctions a bid wins by linear
+// interpolation between the known percentiles
+func interpolatePercentile(percentiles map[int]Amount, bid Amount) float64 {
+	lowerKey, lowerPrice := 0, Amount(0)
+	for _, key := range sortedPercentiles(percentiles) {
+		price := percentiles[key]
+		if bid < price {
+			if price == lowerPrice {
+				return float64(lowerKey) / 100
+			}
+			fraction := float64(bid-lowerPrice) / float64(price-lowerPrice)
+			return (float64(lowerKey) + fraction*float64(key-lowerKey)) / 100
+		}
+		lowerKey, lowerPrice = key, price
+	}
+	// At or above every known percentile: the highest one is a conservative estimate
+	return float64(lowerKey) / 100
+}
+
+func sortedPercentiles(percentiles map[int]Amount) []int {
+	keys := make([]int, 0, len(percentiles))
+	for key := range percentiles {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// ordinal returns n with its English ordinal suffix, e.g. 1st, 12th or 23rd
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		suffix = "th"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// roundUp rounds a positive amount up to a multiple of increment
+func roundUp(a, increment Amount) Amount {
+	if remainder := a % increment; remainder != 0 {
+		return a + increment - remainder
+	}
+	return a
+}
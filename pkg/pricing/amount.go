@@ -0,0 +1,189 @@
+package pricing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// amountScale is the number of Amount units per dollar (six decimal places)
+const amountScale = 1_000_000
+
+// amountDecimals is the number of decimal places an Amount can represent
+const amountDecimals = 6
+
+// HoursPerMonth is the average number of hours in a month used for monthly projections
+const HoursPerMonth = 730
+
+// Amount is a monetary amount stored as an integer number of millionths of a dollar.
+// The API returns prices as decimal strings; keeping them as fixed-point integers
+// means sums and multiplications are exact and never accumulate float rounding errors.
+type Amount int64
+
+// Parse parses a decimal price string such as "0.0125" or "$1.20".
+// Digits beyond six decimal places are rounded half up.
+func Parse(value string) (Amount, error) {
+	s := strings.TrimSpace(value)
+	s = strings.TrimPrefix(s, "$")
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty price")
+	}
+
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid price %q: no digits", value)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || (frac != "" && !isDigits(frac)) {
+		return 0, fmt.Errorf("invalid price %q", value)
+	}
+
+	wholeUnits, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || wholeUnits > (1<<62)/amountScale {
+		return 0, fmt.Errorf("price %q is out of range", value)
+	}
+
+	roundUp := false
+	if len(frac) > amountDecimals {
+		roundUp = frac[amountDecimals] >= '5'
+		frac = frac[:amountDecimals]
+	}
+	frac += strings.Repeat("0", amountDecimals-len(frac))
+	fracUnits, _ := strconv.ParseInt(frac, 10, 64)
+
+	amount := wholeUnits*amountScale + fracUnits
+	if roundUp {
+		amount++
+	}
+	if negative {
+		amount = -amount
+	}
+	return Amount(amount), nil
+}
+
+// MustParse is like Parse but panics on error. Intended for constants and tests.
+func MustParse(value string) Amount {
+	amount, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// Mul returns the amount multiplied by n
+func (a Amount) Mul(n int) Amount {
+	return a * Amount(n)
+}
+
+// Div returns the amount divided by n, rounded half away from zero
+func (a Amount) Div(n int64) Amount {
+	if n == 0 {
+		return 0
+	}
+	q := int64(a) / n
+	r := int64(a) % n
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= abs(n) {
+		if (a < 0) != (n < 0) {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Amount(q)
+}
+
+// Monthly projects an hourly amount to a monthly amount
+func (a Amount) Monthly() Amount {
+	return a.Mul(HoursPerMonth)
+}
+
+// Format renders the amount as a plain decimal string with the given number of
+// decimal places, rounding half away from zero, e.g. Format(4) => "0.0125"
+func (a Amount) Format(decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	if decimals > amountDecimals {
+		decimals = amountDecimals
+	}
+
+	divisor := int64(1)
+	for i := amountDecimals; i > decimals; i-- {
+		divisor *= 10
+	}
+	rounded := int64(a.Div(divisor))
+
+	sign := ""
+	if rounded < 0 {
+		sign = "-"
+		rounded = -rounded
+	}
+
+	pow := int64(1)
+	for i := 0; i < decimals; i++ {
+		pow *= 10
+	}
+	whole := rounded / pow
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, whole)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, whole, decimals, rounded%pow)
+}
+
+// String renders the amount with four decimal places, which is the precision
+// the Spot API uses for hourly prices
+func (a Amount) String() string {
+	return a.Format(4)
+}
+
+// Dollars renders the amount as a dollar value, e.g. "$12.50"
+func (a Amount) Dollars() string {
+	if a < 0 {
+		return "-$" + (-a).Format(2)
+	}
+	return "$" + a.Format(2)
+}
+
+// MarshalText encodes the amount as a decimal string so JSON and YAML output
+// carries the exact value rather than a float
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.Format(amountDecimals)), nil
+}
+
+// UnmarshalText decodes a decimal string produced by MarshalText
+func (a *Amount) UnmarshalText(text []byte) error {
+	amount, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
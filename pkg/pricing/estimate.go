@@ -0,0 +1,175 @@
+package pricing
+
+import (
+	"sort"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// Node pool types reported in estimates
+const (
+	SpotNodePoolType     = "spot"
+	OnDemandNodePoolType = "ondemand"
+)
+
+// NodePoolCost is the estimated cost of a single node pool
+type NodePoolCost struct {
+	Namespace   string `json:"namespace" yaml:"namespace"`
+	CloudSpace  string `json:"cloudSpace" yaml:"cloudSpace"`
+	Name        string `json:"name" yaml:"name"`
+	Type        string `json:"type" yaml:"type"`
+	ServerClass string `json:"serverClass" yaml:"serverClass"`
+	Servers     int    `json:"servers" yaml:"servers"`
+	UnitHourly  Amount `json:"unitHourly" yaml:"unitHourly"`
+	Hourly      Amount `json:"hourly" yaml:"hourly"`
+	Monthly     Amount `json:"monthly" yaml:"monthly"`
+	// Error explains why the pool could not be priced; its cost is then zero
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// CloudSpaceCost is the estimated cost of all node pools in a cloudspace
+type CloudSpaceCost struct {
+	Namespace  string `json:"namespace" yaml:"namespace"`
+	Name       string `json:"name" yaml:"name"`
+	NodePools  int    `json:"nodePools" yaml:"nodePools"`
+	Servers    int    `json:"servers" yaml:"servers"`
+	Hourly     Amount `json:"hourly" yaml:"hourly"`
+	Monthly    Amount `json:"monthly" yaml:"monthly"`
+	Incomplete bool   `json:"incomplete,omitempty" yaml:"incomplete,omitempty"`
+}
+
+// NamespaceCost is the estimated cost of all node pools in a namespace
+type NamespaceCost struct {
+	Namespace   string `json:"namespace" yaml:"namespace"`
+	CloudSpaces int    `json:"cloudSpaces" yaml:"cloudSpaces"`
+	NodePools   int    `json:"nodePools" yaml:"nodePools"`
+	Servers     int    `json:"servers" yaml:"servers"`
+	Hourly      Amount `json:"hourly" yaml:"hourly"`
+	Monthly     Amount `json:"monthly" yaml:"monthly"`
+	Incomplete  bool   `json:"incomplete,omitempty" yaml:"incomplete,omitempty"`
+}
+
+// Estimate is a cost breakdown of a namespace per node pool and per cloudspace.
+// Node pools that could not be priced are included with an error and mark the
+// totals they contribute to as incomplete.
+type Estimate struct {
+	Summary NamespaceCost `json:"summary" yaml:"summary"`
+	// Namespaces breaks the summary down when estimates are combined; the summary
+	// of a combined estimate has no namespace
+	Namespaces  []NamespaceCost  `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	CloudSpaces []CloudSpaceCost `json:"cloudSpaces" yaml:"cloudSpaces"`
+	NodePools   []NodePoolCost   `json:"nodePools" yaml:"nodePools"`
+}
+
+// Estimate prices the given node pools of a namespace and aggregates them per cloudspace
+func (c *Catalog) Estimate(namespace string, spotPools []client.SpotNodePool, onDemandPools []client.OnDemandNodePool) *Estimate {
+	estimate := &Estimate{
+		Summary:     NamespaceCost{Namespace: namespace},
+		CloudSpaces: []CloudSpaceCost{},
+		NodePools:   []NodePoolCost{},
+	}
+
+	for _, pool := range spotPools {
+		cost := NodePoolCost{
+			Namespace:   namespace,
+			CloudSpace:  pool.Spec.CloudSpace,
+			Name:        pool.Metadata.Name,
+			Type:        SpotNodePoolType,
+			ServerClass: pool.Spec.ServerClass,
+			Servers:     SpotNodePoolCount(pool),
+		}
+		unitPrice, err := c.SpotHourly(pool.Spec.ServerClass)
+		cost.setPrice(unitPrice, err)
+		estimate.NodePools = append(estimate.NodePools, cost)
+	}
+
+	for _, pool := range onDemandPools {
+		cost := NodePoolCost{
+			Namespace:   namespace,
+			CloudSpace:  pool.Spec.CloudSpace,
+			Name:        pool.Metadata.Name,
+			Type:        OnDemandNodePoolType,
+			ServerClass: pool.Spec.ServerClass,
+			Servers:     OnDemandNodePoolCount(pool),
+		}
+		unitPrice, err := c.OnDemandHourly(pool.Spec.ServerClass)
+		cost.setPrice(unitPrice, err)
+		estimate.NodePools = append(estimate.NodePools, cost)
+	}
+
+	sort.Slice(estimate.NodePools, func(i, j int) bool {
+		a, b := estimate.NodePools[i], estimate.NodePools[j]
+		if a.CloudSpace != b.CloudSpace {
+			return a.CloudSpace < b.CloudSpace
+		}
+		if a.Type != b.Type {
+			return a.Type > b.Type // spot before ondemand
+		}
+		return a.Name < b.Name
+	})
+
+	index := make(map[string]int)
+	for _, pool := range estimate.NodePools {
+		i, ok := index[pool.CloudSpace]
+		if !ok {
+			i = len(estimate.CloudSpaces)
+			index[pool.CloudSpace] = i
+			estimate.CloudSpaces = append(estimate.CloudSpaces, CloudSpaceCost{Namespace: namespace, Name: pool.CloudSpace})
+		}
+		cs := &estimate.CloudSpaces[i]
+		cs.NodePools++
+		cs.Servers += pool.Servers
+		cs.Hourly += pool.Hourly
+		cs.Incomplete = cs.Incomplete || pool.Error != ""
+	}
+
+	summary := &estimate.Summary
+	for i := range estimate.CloudSpaces {
+		cs := &estimate.CloudSpaces[i]
+		cs.Monthly = cs.Hourly.Monthly()
+		summary.CloudSpaces++
+		summary.NodePools += cs.NodePools
+		summary.Servers += cs.Servers
+		summary.Hourly += cs.Hourly
+		summary.Incomplete = summary.Incomplete || cs.Incomplete
+	}
+	summary.Monthly = summary.Hourly.Monthly()
+
+	return estimate
+}
+
+// Combine adds up the estimates of several namespaces, keeping each one's summary
+// in Namespaces and its cloudspaces and node pools in the order given
+func Combine(estimates []*Estimate) *Estimate {
+	combined := &Estimate{
+		Namespaces:  []NamespaceCost{},
+		CloudSpaces: []CloudSpaceCost{},
+		NodePools:   []NodePoolCost{},
+	}
+
+	summary := &combined.Summary
+	for _, estimate := range estimates {
+		combined.Namespaces = append(combined.Namespaces, estimate.Summary)
+		combined.CloudSpaces = append(combined.CloudSpaces, estimate.CloudSpaces...)
+		combined.NodePools = append(combined.NodePools, estimate.NodePools...)
+		summary.CloudSpaces += estimate.Summary.CloudSpaces
+		summary.NodePools += estimate.Summary.NodePools
+		summary.Servers += estimate.Summary.Servers
+		summary.Hourly += estimate.Summary.Hourly
+		summary.Incomplete = summary.Incomplete || estimate.Summary.Incomplete
+	}
+	summary.Monthly = summary.Hourly.Monthly()
+
+	return combined
+}
+
+// setPrice records the unit price and totals of a node pool, or why it could not be priced
+func (p *NodePoolCost) setPrice(unitPrice Amount, err error) {
+	if err != nil {
+		p.Error = err.Error()
+		return
+	}
+	p.UnitHourly = unitPrice
+	p.Hourly = unitPrice.Mul(p.Servers)
+	p.Monthly = p.Hourly.Monthly()
+}
@@ -0,0 +1,111 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// Catalog indexes server class prices by server class name
+type Catalog struct {
+	classes map[string]client.ServerClass
+}
+
+// NewCatalog builds a price catalog from a server class list
+func NewCatalog(list *client.ServerClassList) *Catalog {
+	catalog := &Catalog{classes: make(map[string]client.ServerClass)}
+	if list == nil {
+		return catalog
+	}
+	for _, serverClass := range list.Items {
+		catalog.classes[serverClass.Metadata.Name] = serverClass
+	}
+	return catalog
+}
+
+// ServerClass returns the named server class, if known
+func (c *Catalog) ServerClass(name string) (client.ServerClass, bool) {
+	serverClass, ok := c.classes[name]
+	return serverClass, ok
+}
+
+// SpotHourly returns the current spot market price per hour for one server of the given class
+func (c *Catalog) SpotHourly(serverClass string) (Amount, error) {
+	class, ok := c.classes[serverClass]
+	if !ok {
+		return 0, fmt.Errorf("unknown server class %q", serverClass)
+	}
+	return SpotHourly(class)
+}
+
+// OnDemandHourly returns the on-demand price per hour for one server of the given class
+func (c *Catalog) OnDemandHourly(serverClass string) (Amount, error) {
+	class, ok := c.classes[serverClass]
+	if !ok {
+		return 0, fmt.Errorf("unknown server class %q", serverClass)
+	}
+	return OnDemandHourly(class)
+}
+
+// SpotHourly returns the spot market price per hour of a server class
+func SpotHourly(serverClass client.ServerClass) (Amount, error) {
+	price := serverClass.Status.SpotPricing.MarketPricePerHour
+	if price == "" {
+		return 0, fmt.Errorf("server class %q has no spot market price", serverClass.Metadata.Name)
+	}
+	amount, err := Parse(price)
+	if err != nil {
+		return 0, fmt.Errorf("server class %q: %w", serverClass.Metadata.Name, err)
+	}
+	return amount, nil
+}
+
+// OnDemandHourly returns the on-demand price per hour of a server class,
+// converting from the pricing interval reported by the API
+func OnDemandHourly(serverClass client.ServerClass) (Amount, error) {
+	pricing := serverClass.Spec.OnDemandPricing
+	if pricing.Cost == "" {
+		return 0, fmt.Errorf("server class %q has no on-demand price", serverClass.Metadata.Name)
+	}
+	amount, err := Parse(pricing.Cost)
+	if err != nil {
+		return 0, fmt.Errorf("server class %q: %w", serverClass.Metadata.Name, err)
+	}
+
+	interval := strings.ToLower(strings.TrimSpace(pricing.Interval))
+	switch {
+	case interval == "" || strings.HasPrefix(interval, "h"):
+		return amount, nil
+	case strings.HasPrefix(interval, "month"), interval == "mo":
+		return amount.Div(HoursPerMonth), nil
+	case strings.HasPrefix(interval, "day"):
+		return amount.Div(24), nil
+	default:
+		return 0, fmt.Errorf("server class %q has unsupported pricing interval %q", serverClass.Metadata.Name, pricing.Interval)
+	}
+}
+
+// SpotNodePoolCount returns the number of billable servers in a spot node pool:
+// the number won, or its desired size until it reports a won count
+func SpotNodePoolCount(pool client.SpotNodePool) int {
+	if pool.Status.WonCount != nil {
+		return *pool.Status.WonCount
+	}
+	if pool.Spec.Desired != nil {
+		return *pool.Spec.Desired
+	}
+	return 0
+}
+
+// OnDemandNodePoolCount returns the number of billable servers in an on-demand
+// node pool: the number reserved, or its desired size until it reports a reserved count
+func OnDemandNodePoolCount(pool client.OnDemandNodePool) int {
+	if pool.Status.ReservedCount != nil {
+		return *pool.Status.ReservedCount
+	}
+	if pool.Spec.Desired != nil {
+		return *pool.Spec.Desired
+	}
+	return 0
+}
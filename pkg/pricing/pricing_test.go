@@ -0,0 +1,373 @@
+package pricing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    Amount
+		expectError bool
+	}{
+		{input: "0.05", expected: 50_000},
+		{input: "$1.20", expected: 1_200_000},
+		{input: " 0.0125 ", expected: 12_500},
+		{input: "12", expected: 12_000_000},
+		{input: ".5", expected: 500_000},
+		{input: "0.0000005", expected: 1},
+		{input: "0.0000004", expected: 0},
+		{input: "-0.25", expected: -250_000},
+		{input: "", expectError: true},
+		{input: "abc", expectError: true},
+		{input: "1.2.3", expectError: true},
+		{input: "1e-3", expectError: true},
+		{input: "-", expectError: true},
+		{input: ".", expectError: true},
+		{input: "-.", expectError: true},
+		{input: "$", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Parse(%q) expected error, got %v", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Parse(%q) = %d, want %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAmountArithmeticIsExact(t *testing.T) {
+	// 0.1 added ten times drifts with float64 but must be exact here
+	var total Amount
+	for i := 0; i < 10; i++ {
+		total += MustParse("0.1")
+	}
+	if total != MustParse("1") {
+		t.Errorf("expected exact sum of 1.0, got %s", total)
+	}
+
+	if got := MustParse("0.0123").Mul(3).String(); got != "0.0369" {
+		t.Errorf("Mul() = %s, want 0.0369", got)
+	}
+	if got := MustParse("0.05").Monthly().Dollars(); got != "$36.50" {
+		t.Errorf("Monthly() = %s, want $36.50", got)
+	}
+	if got := MustParse("73").Div(HoursPerMonth).String(); got != "0.1000" {
+		t.Errorf("Div() = %s, want 0.1000", got)
+	}
+}
+
+func TestAmountFormat(t *testing.T) {
+	tests := []struct {
+		amount   Amount
+		decimals int
+		expected string
+	}{
+		{MustParse("0.014490"), 2, "0.01"},
+		{MustParse("0.014490"), 4, "0.0145"},
+		{MustParse("0.005"), 2, "0.01"},
+		{MustParse("-0.005"), 2, "-0.01"},
+		{MustParse("12.5"), 0, "13"},
+		{MustParse("3"), 4, "3.0000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := tt.amount.Format(tt.decimals); got != tt.expected {
+				t.Errorf("Format(%d) = %q, want %q", tt.decimals, got, tt.expected)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func testCatalog() *Catalog {
+	return NewCatalog(&client.ServerClassList{Items: []client.ServerClass{
+		{
+			Metadata: client.ObjectMeta{Name: "gp.vs1.large-lon"},
+			Spec:     client.ServerClassSpec{OnDemandPricing: client.ServerClassPricing{Cost: "0.20", Interval: "hour"}},
+			Status:   client.ServerClassStatus{SpotPricing: client.ServerClassSpotPricing{MarketPricePerHour: "0.035"}},
+		},
+		{
+			Metadata: client.ObjectMeta{Name: "mh.vs1.xlarge-lon"},
+			Spec:     client.ServerClassSpec{OnDemandPricing: client.ServerClassPricing{Cost: "146", Interval: "month"}},
+		},
+	}})
+}
+
+func TestCatalogNodePoolCosts(t *testing.T) {
+	catalog := testCatalog()
+
+	spotPool := client.SpotNodePool{
+		Spec:   client.SpotNodePoolSpec{ServerClass: "gp.vs1.large-lon", Desired: intPtr(5)},
+		Status: client.SpotNodePoolStatus{WonCount: intPtr(3)},
+	}
+	cost := catalog.Estimate("org-abc123", []client.SpotNodePool{spotPool}, nil).NodePools[0]
+	if cost.Error != "" {
+		t.Fatalf("unexpected error: %v", cost.Error)
+	}
+	if cost.Hourly != MustParse("0.105") {
+		t.Errorf("spot pool cost = %s, want 0.1050", cost.Hourly)
+	}
+
+	// Without a won count the desired size is used
+	spotPool.Status.WonCount = nil
+	if cost := catalog.Estimate("org-abc123", []client.SpotNodePool{spotPool}, nil).NodePools[0]; cost.Hourly != MustParse("0.175") {
+		t.Errorf("spot pool cost without won count = %s, want 0.1750", cost.Hourly)
+	}
+
+	onDemandPool := client.OnDemandNodePool{
+		Spec:   client.OnDemandNodePoolSpec{ServerClass: "mh.vs1.xlarge-lon", Desired: intPtr(2)},
+		Status: client.OnDemandNodePoolStatus{ReservedCount: intPtr(2)},
+	}
+	cost = catalog.Estimate("org-abc123", nil, []client.OnDemandNodePool{onDemandPool}).NodePools[0]
+	if cost.Error != "" {
+		t.Fatalf("unexpected error: %v", cost.Error)
+	}
+	if cost.Hourly != MustParse("0.4") {
+		t.Errorf("on-demand pool cost = %s, want 0.4000", cost.Hourly)
+	}
+
+	if _, err := catalog.SpotHourly("mh.vs1.xlarge-lon"); err == nil {
+		t.Errorf("expected error for server class without spot price")
+	}
+	if _, err := catalog.OnDemandHourly("unknown"); err == nil {
+		t.Errorf("expected error for unknown server class")
+	}
+}
+
+func TestCatalogEstimate(t *testing.T) {
+	catalog := testCatalog()
+
+	spotPools := []client.SpotNodePool{
+		{
+			Metadata: client.ObjectMeta{Name: "spot-b"},
+			Spec:     client.SpotNodePoolSpec{CloudSpace: "alpha", ServerClass: "gp.vs1.large-lon", Desired: intPtr(5)},
+			Status:   client.SpotNodePoolStatus{WonCount: intPtr(3)},
+		},
+		{
+			Metadata: client.ObjectMeta{Name: "spot-a"},
+			Spec:     client.SpotNodePoolSpec{CloudSpace: "beta", ServerClass: "unknown", Desired: intPtr(1)},
+		},
+	}
+	onDemandPools := []client.OnDemandNodePool{
+		{
+			Metadata: client.ObjectMeta{Name: "od-a"},
+			Spec:     client.OnDemandNodePoolSpec{CloudSpace: "alpha", ServerClass: "mh.vs1.xlarge-lon", Desired: intPtr(2)},
+		},
+	}
+
+	estimate := catalog.Estimate("org-abc123", spotPools, onDemandPools)
+
+	if len(estimate.NodePools) != 3 {
+		t.Fatalf("expected 3 node pool costs, got %d", len(estimate.NodePools))
+	}
+	if estimate.NodePools[0].Name != "spot-b" || estimate.NodePools[1].Name != "od-a" {
+		t.Errorf("expected pools ordered by cloudspace then spot first, got %s, %s", estimate.NodePools[0].Name, estimate.NodePools[1].Name)
+	}
+	if estimate.NodePools[2].Error == "" {
+		t.Errorf("expected pricing error for unknown server class")
+	}
+
+	if len(estimate.CloudSpaces) != 2 {
+		t.Fatalf("expected 2 cloudspace costs, got %d", len(estimate.CloudSpaces))
+	}
+	alpha := estimate.CloudSpaces[0]
+	if alpha.Name != "alpha" || alpha.Servers != 5 || alpha.Hourly != MustParse("0.505") || alpha.Incomplete {
+		t.Errorf("unexpected alpha cost: %+v", alpha)
+	}
+	if alpha.Monthly != MustParse("368.65") {
+		t.Errorf("alpha monthly = %s, want 368.6500", alpha.Monthly)
+	}
+	if !estimate.CloudSpaces[1].Incomplete {
+		t.Errorf("expected beta cost to be incomplete")
+	}
+
+	summary := estimate.Summary
+	if summary.CloudSpaces != 2 || summary.NodePools != 3 || summary.Servers != 6 || !summary.Incomplete {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if summary.Hourly != MustParse("0.505") {
+		t.Errorf("summary hourly = %s, want 0.5050", summary.Hourly)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	catalog := testCatalog()
+	pool := func(name string, servers int) client.SpotNodePool {
+		return client.SpotNodePool{
+			Metadata: client.ObjectMeta{Name: name},
+			Spec:     client.SpotNodePoolSpec{CloudSpace: "alpha", ServerClass: "gp.vs1.large-lon", Desired: intPtr(servers)},
+		}
+	}
+
+	combined := Combine([]*Estimate{
+		catalog.Estimate("org-a", []client.SpotNodePool{pool("spot-a", 2)}, nil),
+		catalog.Estimate("org-b", []client.SpotNodePool{pool("spot-b", 4)}, nil),
+	})
+
+	if len(combined.Namespaces) != 2 || combined.Namespaces[1].Namespace != "org-b" || combined.Namespaces[1].Hourly != MustParse("0.14") {
+		t.Errorf("unexpected namespace costs: %+v", combined.Namespaces)
+	}
+	// The same cloudspace name in two namespaces stays two cloudspaces
+	if len(combined.CloudSpaces) != 2 || combined.CloudSpaces[0].Namespace != "org-a" {
+		t.Errorf("unexpected cloudspace costs: %+v", combined.CloudSpaces)
+	}
+	summary := combined.Summary
+	if summary.Namespace != "" || summary.CloudSpaces != 2 || summary.NodePools != 2 || summary.Servers != 6 || summary.Hourly != MustParse("0.21") {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func advisedServerClass(market, hammer string, available, capacity int) client.ServerClass {
+	return client.ServerClass{
+		Metadata: client.ObjectMeta{Name: "gp.vs1.large-lon"},
+		Spec:     client.ServerClassSpec{OnDemandPricing: client.ServerClassPricing{Cost: "0.20", Interval: "hour"}},
+		Status: client.ServerClassStatus{
+			Available:   intPtr(available),
+			Capacity:    intPtr(capacity),
+			LastAuction: intPtr(4),
+			SpotPricing: client.ServerClassSpotPricing{MarketPricePerHour: market, HammerPricePerHour: hammer},
+		},
+	}
+}
+
+func TestAdviseFromHistory(t *testing.T) {
+	var history []Amount
+	for i := 1; i <= 10; i++ {
+		history = append(history, MustParse("0.01").Mul(i)) // 0.01 ... 0.10
+	}
+
+	tests := []struct {
+		risk        Risk
+		bid         string
+		probability float64
+	}{
+		{risk: RiskLow, bid: "0.10", probability: 1},
+		{risk: RiskMedium, bid: "0.08", probability: 0.8},
+		{risk: RiskHigh, bid: "0.05", probability: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.risk), func(t *testing.T) {
+			advice, err := Advise(AdviceInput{
+				ServerClass: advisedServerClass("0.02", "0.03", 50, 100),
+				Nodes:       3,
+				Risk:        tt.risk,
+				History:     history,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if advice.BidPrice != MustParse(tt.bid) {
+				t.Errorf("bid = %s, want %s", advice.BidPrice, tt.bid)
+			}
+			if advice.WinProbability == nil || *advice.WinProbability != tt.probability {
+				t.Errorf("win probability = %v, want %v", advice.WinProbability, tt.probability)
+			}
+			if advice.MaxHourlyCost != MustParse(tt.bid).Mul(3) {
+				t.Errorf("max hourly cost = %s, want 3 x %s", advice.MaxHourlyCost, tt.bid)
+			}
+			if len(advice.Reasons) == 0 {
+				t.Errorf("expected reasoning to be explained")
+			}
+		})
+	}
+}
+
+func TestAdviseAdjustments(t *testing.T) {
+	// Market price above every historical price: the bid is raised to the market price
+	advice, err := Advise(AdviceInput{
+		ServerClass: advisedServerClass("0.05", "", 50, 100),
+		Nodes:       1,
+		Risk:        RiskMedium,
+		History:     []Amount{MustParse("0.01"), MustParse("0.02")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if advice.BidPrice != MustParse("0.05") {
+		t.Errorf("bid = %s, want market price 0.0500", advice.BidPrice)
+	}
+
+	// Scarce supply raises the bid by 10% (rounded up) and warns when too few servers are available
+	advice, err = Advise(AdviceInput{
+		ServerClass: advisedServerClass("0.0101", "", 2, 100),
+		Nodes:       5,
+		Risk:        RiskHigh,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if advice.BidPrice != MustParse("0.012") {
+		t.Errorf("bid = %s, want 0.0120", advice.BidPrice)
+	}
+	if advice.WinProbability != nil {
+		t.Errorf("expected unknown win probability without history, got %v", *advice.WinProbability)
+	}
+	if len(advice.Warnings) != 2 {
+		t.Errorf("expected capacity and probability warnings, got %v", advice.Warnings)
+	}
+
+	// Percentiles are used when there is no history
+	advice, err = Advise(AdviceInput{
+		ServerClass: advisedServerClass("0.01", "", 50, 100),
+		Nodes:       1,
+		Risk:        RiskMedium,
+		Percentiles: map[int]Amount{50: MustParse("0.02"), 90: MustParse("0.04")},
+		Unavailable: []string{"price history"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if advice.BidPrice != MustParse("0.04") || advice.WinProbability == nil || *advice.WinProbability != 0.9 {
+		t.Errorf("unexpected percentile advice: bid %s probability %v", advice.BidPrice, advice.WinProbability)
+	}
+	if !strings.Contains(advice.Reasons[0], "the 90th percentile") {
+		t.Errorf("expected the percentile in the reasons, got %v", advice.Reasons)
+	}
+	if len(advice.Warnings) != 1 || !strings.Contains(advice.Warnings[0], "no price history") {
+		t.Errorf("expected a warning about the missing history, got %v", advice.Warnings)
+	}
+
+	if _, err := Advise(AdviceInput{ServerClass: client.ServerClass{}, Nodes: 1}); err == nil {
+		t.Errorf("expected error without any price information")
+	}
+	if _, err := Advise(AdviceInput{ServerClass: advisedServerClass("0.01", "", 1, 1), Nodes: 0}); err == nil {
+		t.Errorf("expected error for zero nodes")
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 50: "50th", 92: "92nd", 100: "100th", 101: "101st", 111: "111th"} {
+		if got := ordinal(n); got != want {
+			t.Errorf("ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestParsePercentiles(t *testing.T) {
+	percentiles := ParsePercentiles(&client.PercentileInfo{Percentiles: map[string]string{
+		"50": "0.012", "90": "0.02", "p99": "0.05", "200": "1",
+	}})
+	if len(percentiles) != 2 || percentiles[90] != MustParse("0.02") {
+		t.Errorf("unexpected percentiles: %v", percentiles)
+	}
+}
@@ -0,0 +1,129 @@
+package quantity
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Byte sizes of the binary memory units
+const (
+	KiB int64 = 1 << 10
+	MiB int64 = 1 << 20
+	GiB int64 = 1 << 30
+	TiB int64 = 1 << 40
+)
+
+// memoryUnits maps a memory suffix to its size in bytes. As in Kubernetes, the
+// "i" suffixes (Ki, Mi, Gi, Ti) are binary and the plain ones (k, M, G, T) are
+// decimal. The API reports server class memory as e.g. "3.75GB"; a trailing "B"
+// does not change the unit, so that is read as 3.75G, i.e. 3,750,000,000 bytes
+// (about 3.5Gi).
+var memoryUnits = map[string]int64{
+	"":   1,
+	"B":  1,
+	"Ki": KiB,
+	"Mi": MiB,
+	"Gi": GiB,
+	"Ti": TiB,
+	"k":  1_000,
+	"M":  1_000_000,
+	"G":  1_000_000_000,
+	"T":  1_000_000_000_000,
+}
+
+// ParseCPU parses a CPU quantity such as "8", "2.5" or "500m" into millicores
+func ParseCPU(value string) (int64, error) {
+	s := strings.TrimSpace(value)
+	scale := 1000.0
+	if strings.HasSuffix(s, "m") {
+		s = strings.TrimSuffix(s, "m")
+		scale = 1
+	}
+
+	number, err := parseNumber(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cpu quantity %q", value)
+	}
+	return int64(math.Round(number * scale)), nil
+}
+
+// ParseMemory parses a memory quantity such as "32Gi", "16GB" or "512Mi" into bytes
+func ParseMemory(value string) (int64, error) {
+	s := strings.TrimSpace(value)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	numberPart, unitPart := s, ""
+	if i >= 0 {
+		numberPart, unitPart = s[:i], strings.TrimSpace(s[i:])
+	}
+
+	multiplier, ok := memoryUnits[normalizeUnit(unitPart)]
+	if !ok {
+		return 0, fmt.Errorf("invalid memory quantity %q: unknown unit %q", value, unitPart)
+	}
+
+	number, err := parseNumber(numberPart)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory quantity %q", value)
+	}
+	return int64(math.Round(number * float64(multiplier))), nil
+}
+
+// FormatCPU renders millicores as cores, e.g. 8000 => "8" and 2500 => "2.5"
+func FormatCPU(millicores int64) string {
+	return strconv.FormatFloat(float64(millicores)/1000, 'f', -1, 64)
+}
+
+// FormatMemory renders bytes in the largest binary unit that keeps one decimal place, e.g. "32Gi" or "3.8Gi"
+func FormatMemory(bytes int64) string {
+	units := []struct {
+		suffix string
+		size   int64
+	}{{"Ti", TiB}, {"Gi", GiB}, {"Mi", MiB}, {"Ki", KiB}}
+
+	for _, unit := range units {
+		if bytes >= unit.size {
+			value := math.Round(float64(bytes)/float64(unit.size)*10) / 10
+			return strconv.FormatFloat(value, 'f', -1, 64) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+// parseNumber parses a non-negative decimal number
+func parseNumber(s string) (float64, error) {
+	if s == "" || strings.ContainsAny(s, "eE+-") {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	number, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return number, nil
+}
+
+// normalizeUnit maps the other spellings of a unit to its memoryUnits key: a
+// trailing "B" is dropped, e.g. "GB" or "GiB", and case is ignored where that is
+// unambiguous, e.g. "gb", "gib" or "K". A lone "m" is left alone, as Kubernetes
+// reads it as milli.
+func normalizeUnit(unit string) string {
+	if _, ok := memoryUnits[unit]; ok {
+		return unit
+	}
+	lower := strings.ToLower(unit)
+	switch {
+	case len(lower) == 3 && strings.HasSuffix(lower, "ib"):
+		return strings.ToUpper(lower[:1]) + "i"
+	case len(lower) == 2 && strings.HasSuffix(lower, "b"):
+		lower = lower[:1]
+	case len(lower) != 1 || lower == "m":
+		return unit
+	}
+	if lower == "k" {
+		return lower
+	}
+	return strings.ToUpper(lower)
+}
@@ -0,0 +1,98 @@
+package quantity
+
+import "testing"
+
+func TestParseCPU(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    int64
+		expectError bool
+	}{
+		{input: "8", expected: 8000},
+		{input: "2.5", expected: 2500},
+		{input: "500m", expected: 500},
+		{input: " 4 ", expected: 4000},
+		{input: "", expectError: true},
+		{input: "four", expectError: true},
+		{input: "-1", expectError: true},
+		{input: "1e3", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCPU(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseCPU(%q) expected error, got %d", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCPU(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseCPU(%q) = %d, want %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    int64
+		expectError bool
+	}{
+		{input: "32Gi", expected: 32 * GiB},
+		{input: "16GB", expected: 16_000_000_000},
+		{input: "3.75GB", expected: 3_750_000_000},
+		{input: "512Mi", expected: 512 * MiB},
+		{input: "1G", expected: 1_000_000_000},
+		{input: "500k", expected: 500_000},
+		{input: "500K", expected: 500_000},
+		{input: "64 gib", expected: 64 * GiB},
+		{input: "8gb", expected: 8_000_000_000},
+		{input: "1024", expected: 1024},
+		{input: "1024B", expected: 1024},
+		{input: "512m", expectError: true},
+		{input: "32Qi", expectError: true},
+		{input: "Gi", expectError: true},
+		{input: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMemory(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseMemory(%q) expected error, got %d", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMemory(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseMemory(%q) = %d, want %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got := FormatCPU(2500); got != "2.5" {
+		t.Errorf("FormatCPU(2500) = %q, want 2.5", got)
+	}
+	if got := FormatCPU(8000); got != "8" {
+		t.Errorf("FormatCPU(8000) = %q, want 8", got)
+	}
+	if got := FormatMemory(32 * GiB); got != "32Gi" {
+		t.Errorf("FormatMemory(32Gi) = %q, want 32Gi", got)
+	}
+	if got := FormatMemory(3*GiB + 768*MiB); got != "3.8Gi" {
+		t.Errorf("FormatMemory(3.75Gi) = %q, want 3.8Gi", got)
+	}
+	if got := FormatMemory(512 * MiB); got != "512Mi" {
+		t.Errorf("FormatMemory(512Mi) = %q, want 512Mi", got)
+	}
+}
@@ -0,0 +1,80 @@
+package registry_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/registry"
+)
+
+// Widget is a kind declared outside pkg/client, as a plugin or fork would
+type Widget struct {
+	Metadata client.ObjectMeta `json:"metadata"`
+}
+
+// WidgetList pages by continue token through its ListMeta
+type WidgetList struct {
+	Items    []Widget        `json:"items"`
+	Metadata client.ListMeta `json:"metadata,omitempty"`
+}
+
+func (l *WidgetList) ListItems() []Widget { return l.Items }
+
+func (l *WidgetList) NextPage(opts client.ListOptions) (client.ListOptions, bool) {
+	return l.Metadata.NextPage(opts)
+}
+
+func (l *WidgetList) AppendPage(next any) {
+	page := next.(*WidgetList)
+	l.Items = append(l.Items, page.Items...)
+	l.Metadata = page.Metadata
+}
+
+func TestRegisterExternalKind(t *testing.T) {
+	// Two pages of widgets, linked by a continue token
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/widgets") {
+			http.NotFound(w, r)
+			return
+		}
+		page := WidgetList{Items: []Widget{{Metadata: client.ObjectMeta{Name: "widget-a"}}}, Metadata: client.ListMeta{Continue: "page-2"}}
+		if r.URL.Query().Get("continue") == "page-2" {
+			page = WidgetList{Items: []Widget{{Metadata: client.ObjectMeta{Name: "widget-b"}}}}
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	widgets := registry.NewKind[Widget, WidgetList](client.Resource{
+		Kind:        "Widget",
+		Plural:      "widgets",
+		Singular:    "widget",
+		DisplayName: "widget",
+		APIVersion:  client.APIVersionDefault,
+		Verbs:       []string{client.VerbGet, client.VerbList},
+	}, func() *output.TableConfig {
+		return &output.TableConfig{Columns: []output.TableColumn{{Header: "NAME", Field: "metadata.name"}}}
+	})
+	registry.Register(widgets)
+
+	kind, err := registry.Lookup("widget")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	apiClient := client.NewClient(&config.Config{BaseURL: server.URL, Timeout: 30}, client.WithTokenSource(client.StaticTokenSource("token")))
+	list, err := kind.List(context.Background(), apiClient, "", client.ListOptions{}, 0)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	items, ok := list.([]Widget)
+	if !ok || len(items) != 2 || items[1].Metadata.Name != "widget-b" {
+		t.Errorf("Expected both pages of widgets, got %#v", list)
+	}
+}
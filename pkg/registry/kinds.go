@@ -0,0 +1,128 @@
+package registry
+
+import (
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/output"
+)
+
+// The kinds spotctl knows about. Commands use these directly; the generic verbs
+// find them by name through Lookup.
+var (
+	Regions           = NewKind[client.Region, client.RegionList](client.RegionResource, regionsTable)
+	ServerClasses     = NewKind[client.ServerClass, client.ServerClassList](client.ServerClassResource, serverClassesTable)
+	Organizations     = NewKind[client.Organization, client.OrganizationList](client.OrganizationResource, organizationsTable)
+	CloudSpaces       = NewKind[client.CloudSpace, client.CloudSpaceList](client.CloudSpaceResource, cloudSpacesTable)
+	SpotNodePools     = NewKind[client.SpotNodePool, client.SpotNodePoolList](client.SpotNodePoolResource, spotNodePoolsTable)
+	OnDemandNodePools = NewKind[client.OnDemandNodePool, client.OnDemandNodePoolList](client.OnDemandNodePoolResource, onDemandNodePoolsTable)
+)
+
+// builtinKinds are registered before any other kind
+func builtinKinds() []Kind {
+	return []Kind{Regions, ServerClasses, Organizations, CloudSpaces, SpotNodePools, OnDemandNodePools}
+}
+
+// regionsTable returns the table configuration for regions
+func regionsTable() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "NAME", Field: "metadata.name", Default: "N/A"},
+			{Header: "COUNTRY", Field: "spec.country", Default: "N/A"},
+			{Header: "PROVIDER", Field: "spec.provider.providerType", Default: "N/A"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "PROVIDER REGION", Field: "spec.provider.providerRegionName", Default: "N/A"},
+			{Header: "DESCRIPTION", Field: "spec.description", Default: "N/A", Width: 50},
+		},
+	}
+}
+
+// serverClassesTable returns the table configuration for server classes
+func serverClassesTable() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "NAME", Field: "metadata.name"},
+			{Header: "DISPLAY NAME", Field: "spec.displayName"},
+			{Header: "REGION", Field: "spec.region"},
+			{Header: "CPU", Field: "spec.resources.cpu"},
+			{Header: "MEMORY", Field: "spec.resources.memory"},
+			{Header: "AVAILABILITY", Field: "spec.availability"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "CATEGORY", Field: "spec.category"},
+			{Header: "FLAVOR TYPE", Field: "spec.flavorType"},
+			{Header: "PROVIDER TYPE", Field: "spec.provider.providerType"},
+			{Header: "ON-DEMAND COST", Field: "spec.onDemandPricing.cost"},
+			{Header: "SPOT PRICE", Field: "status.spotPricing.marketPricePerHour", Default: "N/A"},
+			{Header: "HAMMER PRICE", Field: "status.spotPricing.hammerPricePerHour", Default: "N/A"},
+		},
+	}
+}
+
+// organizationsTable returns the table configuration for organizations
+func organizationsTable() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "ID", Field: "id"},
+			{Header: "NAME", Field: "name"},
+			{Header: "DISPLAY NAME", Field: "display_name"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "NAMESPACE", Field: "metadata.namespace"},
+		},
+	}
+}
+
+// cloudSpacesTable returns the table configuration for cloudspaces
+func cloudSpacesTable() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "NAME", Field: "metadata.name"},
+			{Header: "NAMESPACE", Field: "metadata.namespace"},
+			{Header: "REGION", Field: "spec.region"},
+			{Header: "PHASE", Field: "status.phase", Default: "<none>"},
+			{Header: "HEALTH", Field: "status.health", Default: "<none>"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "K8S VERSION", Field: "status.currentKubernetesVersion", Default: "<none>"},
+			{Header: "CNI", Field: "spec.cni", Default: "<none>"},
+			{Header: "DEPLOYMENT TYPE", Field: "spec.deploymentType", Default: "<none>"},
+			{Header: "HA CONTROL PLANE", Field: "spec.HAControlPlane", Default: "<none>"},
+		},
+	}
+}
+
+// spotNodePoolsTable returns the table configuration for spot node pools
+func spotNodePoolsTable() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "NAME", Field: "metadata.name"},
+			{Header: "NAMESPACE", Field: "metadata.namespace"},
+			{Header: "SERVER CLASS", Field: "spec.serverClass", Default: "<none>"},
+			{Header: "DESIRED", Field: "spec.desired", Default: "<none>"},
+			{Header: "BID STATUS", Field: "status.bidStatus", Default: "<none>"},
+			{Header: "WON COUNT", Field: "status.wonCount", Default: "<none>"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "CLOUD SPACE", Field: "spec.cloudSpace", Default: "<none>"},
+			{Header: "BID PRICE", Field: "spec.bidPrice", Default: "<none>"},
+			{Header: "AUTOSCALING", Field: "spec.autoscaling.enabled", Default: "<none>"},
+			{Header: "MIN NODES", Field: "spec.autoscaling.minNodes", Default: "<none>"},
+			{Header: "MAX NODES", Field: "spec.autoscaling.maxNodes", Default: "<none>"},
+		},
+	}
+}
+
+// onDemandNodePoolsTable returns the table configuration for on-demand node pools
+func onDemandNodePoolsTable() *output.TableConfig {
+	return &output.TableConfig{
+		Columns: []output.TableColumn{
+			{Header: "NAME", Field: "metadata.name"},
+			{Header: "NAMESPACE", Field: "metadata.namespace"},
+			{Header: "SERVER CLASS", Field: "spec.serverClass", Default: "<none>"},
+			{Header: "DESIRED", Field: "spec.desired", Default: "<none>"},
+		},
+		DetailCols: []output.TableColumn{
+			{Header: "CLOUD SPACE", Field: "spec.cloudSpace", Default: "<none>"},
+		},
+	}
+}
@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/output"
+	"github.com/georgetaylor/spotctl/pkg/pager"
+	"github.com/spf13/viper"
+)
+
+// NewFormatter returns a formatter for objects of a kind, honouring --no-pager
+func NewFormatter(kind Kind, options output.OutputOptions) *output.Formatter {
+	p := pager.NewPager()
+	p.Disable = viper.GetBool("no-pager")
+	options.Resource = kind.Resource().Singular
+	return output.NewFormatterWithPager(options, p)
+}
+
+// Output writes objects of a kind in the given format, honouring --no-pager
+func Output(kind Kind, data any, format string) error {
+	return NewFormatter(kind, output.OutputOptions{Format: output.OutputFormat(format)}).Output(data, kind.TableConfig())
+}
+
+// OutputList writes a list of objects of a kind, either a slice or a list type
+// with Items. When the list is empty tables say so, naming the namespace listed
+// unless it is empty; JSON and YAML print an empty list and -o name prints
+// nothing, so scripts reading them still work.
+func OutputList(kind Kind, list any, format, namespace string) error {
+	if !isEmptyList(list) {
+		return Output(kind, list, format)
+	}
+
+	switch output.OutputFormat(format) {
+	case output.TableFormat, output.WideFormat:
+		if namespace == "" {
+			fmt.Printf("No %s found\n", DisplayPlural(kind))
+		} else {
+			fmt.Printf("No %s found in namespace %s\n", DisplayPlural(kind), namespace)
+		}
+		return nil
+	case output.NameFormat:
+		return nil
+	}
+	if v := reflect.ValueOf(list); v.Kind() == reflect.Slice {
+		// A nil slice would print as null
+		list = []any{}
+	}
+	return Output(kind, list, format)
+}
+
+// DisplayPlural returns the human readable plural of a kind, e.g. "spot node pools"
+func DisplayPlural(kind Kind) string {
+	name := kind.Resource().DisplayName
+	if strings.HasSuffix(name, "s") {
+		return name + "es"
+	}
+	return name + "s"
+}
+
+// isEmptyList reports whether list is a slice, or a pointer to a struct whose
+// Items are a slice, with no elements
+func isEmptyList(list any) bool {
+	v := reflect.Indirect(reflect.ValueOf(list))
+	if v.Kind() == reflect.Struct {
+		v = v.FieldByName("Items")
+	}
+	return v.Kind() == reflect.Slice && v.Len() == 0
+}
@@ -0,0 +1,144 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/output"
+)
+
+// Kind is a resource kind that the generic get, edit and delete verbs can operate on
+type Kind interface {
+	// Resource describes the kind
+	Resource() client.Resource
+	// TableConfig returns how objects of the kind are rendered as a table
+	TableConfig() *output.TableConfig
+	// Get retrieves one object
+	Get(ctx context.Context, c client.Interface, namespace, name string) (any, error)
+	// List retrieves up to limit objects (all if limit is zero) as a slice
+	List(ctx context.Context, c client.Interface, namespace string, opts client.ListOptions, limit int) (any, error)
+	// Edit applies JSON patch operations to one object, returning the result
+	Edit(ctx context.Context, c client.Interface, namespace, name string, patchOps []client.PatchOperation) (any, error)
+	// Delete deletes one object
+	Delete(ctx context.Context, c client.Interface, namespace, name string) (*client.DeleteResponse, error)
+}
+
+// typedKind implements Kind for object type T and list type L
+type typedKind[T any, L any, PL client.ItemList[T, L]] struct {
+	resource client.Resource
+	table    func() *output.TableConfig
+}
+
+// NewKind describes a kind by its resource, Go types and table configuration.
+// *L must implement ListItems, and client.PagedList if the API pages the list;
+// PL is inferred, e.g. NewKind[Widget, WidgetList](...).
+func NewKind[T any, L any, PL client.ItemList[T, L]](resource client.Resource, table func() *output.TableConfig) Kind {
+	return &typedKind[T, L, PL]{resource: resource, table: table}
+}
+
+func (k *typedKind[T, L, PL]) Resource() client.Resource {
+	return k.resource
+}
+
+func (k *typedKind[T, L, PL]) TableConfig() *output.TableConfig {
+	return k.table()
+}
+
+func (k *typedKind[T, L, PL]) Get(ctx context.Context, c client.Interface, namespace, name string) (any, error) {
+	return client.NewResourceClient[T, L, PL](c, k.resource).Get(ctx, namespace, name)
+}
+
+func (k *typedKind[T, L, PL]) List(ctx context.Context, c client.Interface, namespace string, opts client.ListOptions, limit int) (any, error) {
+	return client.Collect(client.NewResourceClient[T, L, PL](c, k.resource).All(ctx, namespace, opts), limit)
+}
+
+func (k *typedKind[T, L, PL]) Edit(ctx context.Context, c client.Interface, namespace, name string, patchOps []client.PatchOperation) (any, error) {
+	return client.NewResourceClient[T, L, PL](c, k.resource).Edit(ctx, namespace, name, patchOps)
+}
+
+func (k *typedKind[T, L, PL]) Delete(ctx context.Context, c client.Interface, namespace, name string) (*client.DeleteResponse, error) {
+	return client.NewResourceClient[T, L, PL](c, k.resource).Delete(ctx, namespace, name)
+}
+
+var (
+	mu    sync.RWMutex
+	kinds = indexKinds(builtinKinds())
+)
+
+// Register makes a kind available under its plural, singular and short names.
+// It panics if any of the names is already taken, as that is a programming error.
+func Register(kind Kind) {
+	mu.Lock()
+	defer mu.Unlock()
+	addKind(kinds, kind)
+}
+
+func indexKinds(list []Kind) map[string]Kind {
+	index := map[string]Kind{}
+	for _, kind := range list {
+		addKind(index, kind)
+	}
+	return index
+}
+
+func addKind(index map[string]Kind, kind Kind) {
+	for _, name := range kind.Resource().Names() {
+		name = strings.ToLower(name)
+		if existing, ok := index[name]; ok {
+			panic(fmt.Sprintf("registry: %q is registered for both %s and %s", name, existing.Resource().Kind, kind.Resource().Kind))
+		}
+		index[name] = kind
+	}
+}
+
+// Lookup returns the kind registered under name, ignoring case
+func Lookup(name string) (Kind, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if kind, ok := kinds[strings.ToLower(name)]; ok {
+		return kind, nil
+	}
+	return nil, fmt.Errorf("unknown resource type %q: valid types are %s", name, strings.Join(pluralsLocked(), ", "))
+}
+
+// Kinds returns every registered kind ordered by plural name
+func Kinds() []Kind {
+	mu.RLock()
+	defer mu.RUnlock()
+	return kindsLocked()
+}
+
+// Plurals returns the plural names of every registered kind, sorted
+func Plurals() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return pluralsLocked()
+}
+
+func kindsLocked() []Kind {
+	byPlural := map[string]Kind{}
+	for _, kind := range kinds {
+		byPlural[kind.Resource().Plural] = kind
+	}
+	result := make([]Kind, 0, len(byPlural))
+	for _, kind := range byPlural {
+		result = append(result, kind)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Resource().Plural < result[j].Resource().Plural
+	})
+	return result
+}
+
+func pluralsLocked() []string {
+	var plurals []string
+	for _, kind := range kindsLocked() {
+		plurals = append(plurals, kind.Resource().Plural)
+	}
+	return plurals
+}
@@ -0,0 +1,60 @@
+package registry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+	"github.com/georgetaylor/spotctl/pkg/output"
+)
+
+// withKinds runs the test against an empty registry, restoring it afterwards
+func withKinds(t *testing.T) {
+	t.Helper()
+	original := kinds
+	kinds = map[string]Kind{}
+	t.Cleanup(func() { kinds = original })
+}
+
+func tableConfig() *output.TableConfig {
+	return &output.TableConfig{}
+}
+
+func TestLookup(t *testing.T) {
+	withKinds(t)
+	Register(NewKind[client.CloudSpace, client.CloudSpaceList](client.CloudSpaceResource, tableConfig))
+	Register(NewKind[client.ServerClass, client.ServerClassList](client.ServerClassResource, tableConfig))
+
+	for _, name := range []string{"cloudspaces", "cloudspace", "cs", "CloudSpaces"} {
+		kind, err := Lookup(name)
+		if err != nil {
+			t.Fatalf("Lookup(%q) failed: %v", name, err)
+		}
+		if kind.Resource().Kind != "CloudSpace" {
+			t.Errorf("Lookup(%q) returned %s, expected CloudSpace", name, kind.Resource().Kind)
+		}
+	}
+
+	_, err := Lookup("widgets")
+	if err == nil || !strings.Contains(err.Error(), "valid types are cloudspaces, serverclasses") {
+		t.Errorf("Expected unknown type error listing valid types, got %v", err)
+	}
+
+	if plurals := strings.Join(Plurals(), ","); plurals != "cloudspaces,serverclasses" {
+		t.Errorf("Expected sorted plurals, got %s", plurals)
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	withKinds(t)
+	Register(NewKind[client.CloudSpace, client.CloudSpaceList](client.CloudSpaceResource, tableConfig))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a duplicate name to panic")
+		}
+	}()
+	duplicate := client.RegionResource
+	duplicate.ShortNames = []string{"cs"}
+	Register(NewKind[client.Region, client.RegionList](duplicate, tableConfig))
+}
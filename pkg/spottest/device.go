@@ -0,0 +1,129 @@
+package spottest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// deviceLogin is a pending device authorization
+type deviceLogin struct {
+	userCode string
+	expires  time.Time
+	lastPoll time.Time
+	approved bool
+	denied   bool
+}
+
+// WithDeviceInterval sets the polling interval the device authorization endpoint
+// asks for; polling faster is answered with slow_down
+func WithDeviceInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		s.deviceInterval = interval
+	}
+}
+
+// ApproveDevice approves the pending device login with userCode, as if the user
+// had entered it in a browser. It reports whether such a login was pending.
+func (s *Server) ApproveDevice(userCode string) bool {
+	return s.decideDevice(userCode, true)
+}
+
+// DenyDevice rejects the pending device login with userCode
+func (s *Server) DenyDevice(userCode string) bool {
+	return s.decideDevice(userCode, false)
+}
+
+func (s *Server) decideDevice(userCode string, approve bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, login := range s.devices {
+		if login.userCode == userCode && !login.approved && !login.denied {
+			login.approved, login.denied = approve, !approve
+			return true
+		}
+	}
+	return false
+}
+
+// serveDeviceCode implements the device authorization endpoint (RFC 8628 section 3.1)
+func (s *Server) serveDeviceCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "device authorization requests must use POST")
+		return
+	}
+
+	s.mu.Lock()
+	s.deviceCodes++
+	deviceCode := fmt.Sprintf("spottest-device-%d", s.deviceCodes)
+	userCode := fmt.Sprintf("SPOT-%04d", s.deviceCodes)
+	s.devices[deviceCode] = &deviceLogin{userCode: userCode, expires: s.now().Add(deviceCodeLifetime)}
+	s.mu.Unlock()
+
+	verificationURI := fmt.Sprintf("http://%s/activate", r.Host)
+	writeJSON(w, http.StatusOK, client.DeviceAuthorization{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + userCode,
+		ExpiresIn:               int(deviceCodeLifetime.Seconds()),
+		Interval:                int(s.deviceInterval.Seconds()),
+	})
+}
+
+// deviceCodeLifetime is how long a device code can be approved and redeemed
+const deviceCodeLifetime = 10 * time.Minute
+
+// serveActivate approves a device login from a browser: GET /activate?user_code=...
+func (s *Server) serveActivate(w http.ResponseWriter, r *http.Request) {
+	userCode := r.URL.Query().Get("user_code")
+	if userCode == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<form>Enter the code shown by spotctl: <input name="user_code"> <button>Approve</button></form>`)
+		return
+	}
+	if !s.ApproveDevice(userCode) {
+		http.Error(w, fmt.Sprintf("no pending login with code %s", userCode), http.StatusNotFound)
+		return
+	}
+	fmt.Fprintf(w, "Login %s approved; you can return to spotctl.\n", userCode)
+}
+
+// redeemDeviceCode answers a device code token request (RFC 8628 section 3.5).
+// The caller holds s.mu.
+func (s *Server) redeemDeviceCode(w http.ResponseWriter, deviceCode string) {
+	login, ok := s.devices[deviceCode]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "unknown device code"})
+		return
+	}
+
+	now := s.now()
+	tooSoon := !login.lastPoll.IsZero() && now.Sub(login.lastPoll) < s.deviceInterval
+	login.lastPoll = now
+	switch {
+	case now.After(login.expires):
+		delete(s.devices, deviceCode)
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "expired_token"})
+	case login.denied:
+		delete(s.devices, deviceCode)
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "access_denied"})
+	case tooSoon:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "slow_down"})
+	case !login.approved:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+	default:
+		delete(s.devices, deviceCode)
+		token := s.issueToken()
+		writeJSON(w, http.StatusOK, client.TokenResponse{
+			AccessToken:  token,
+			IDToken:      token,
+			RefreshToken: s.refreshToken,
+			Scope:        client.DeviceScope,
+			ExpiresIn:    int(s.tokenLifetime.Seconds()),
+			TokenType:    "Bearer",
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package spottest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// WithoutDiscovery makes the server answer discovery requests with 404, like a
+// server without discovery support
+func WithoutDiscovery() Option {
+	return func(s *Server) {
+		s.noDiscovery = true
+	}
+}
+
+// serveDiscovery answers GET / with the API groups and GET /<group>/<version>
+// with the resources of that version, reporting whether path was a discovery request
+func (s *Server) serveDiscovery(w http.ResponseWriter, r *http.Request, path string) bool {
+	if s.noDiscovery || r.Method != http.MethodGet {
+		return false
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) == 1 && segments[0] == "":
+		writeJSON(w, http.StatusOK, discoveryGroups())
+		return true
+	case len(segments) == 2:
+		resources := discoveryResources(client.APIVersion(segments[0] + "/" + segments[1]))
+		if resources == nil {
+			return false
+		}
+		writeJSON(w, http.StatusOK, resources)
+		return true
+	}
+	return false
+}
+
+// discoveryGroups lists the group of every kind the server serves, each with a single version
+func discoveryGroups() *client.APIGroupList {
+	versions := map[string]client.GroupVersionForDiscovery{}
+	for _, k := range kinds {
+		version := k.resource.APIVersion
+		versions[version.Group()] = client.GroupVersionForDiscovery{
+			GroupVersion: version.String(),
+			Version:      strings.TrimPrefix(version.String(), version.Group()+"/"),
+		}
+	}
+
+	list := &client.APIGroupList{Kind: "APIGroupList", APIVersion: "v1"}
+	for group, version := range versions {
+		list.Groups = append(list.Groups, client.APIGroup{
+			Name:             group,
+			Versions:         []client.GroupVersionForDiscovery{version},
+			PreferredVersion: version,
+		})
+	}
+	sort.Slice(list.Groups, func(i, j int) bool { return list.Groups[i].Name < list.Groups[j].Name })
+	return list
+}
+
+// discoveryResources lists the kinds served by a version, or nil if it serves none
+func discoveryResources(version client.APIVersion) *client.APIResourceList {
+	var resources []client.APIResource
+	for _, k := range kinds {
+		if k.resource.APIVersion != version {
+			continue
+		}
+		resources = append(resources, client.APIResource{
+			Name:         k.resource.Plural,
+			SingularName: k.resource.Singular,
+			Namespaced:   k.resource.Namespaced,
+			Kind:         k.resource.Kind,
+			Verbs:        k.resource.Verbs,
+			ShortNames:   k.resource.ShortNames,
+		})
+	}
+	if resources == nil {
+		return nil
+	}
+	return &client.APIResourceList{Kind: "APIResourceList", APIVersion: "v1", GroupVersion: version.String(), Resources: resources}
+}
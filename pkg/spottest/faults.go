@@ -0,0 +1,91 @@
+package spottest
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault makes matching requests slow or fail, to exercise retries and timeouts
+type Fault struct {
+	// Method limits the fault to one HTTP method; empty matches every method
+	Method string
+	// Path limits the fault to request paths starting with it, ignoring any /apis
+	// prefix, e.g. /ngpc.rxt.io/v1/regions; empty matches every path
+	Path string
+	// Latency delays the response
+	Latency time.Duration
+	// Status, if set, is returned instead of the real response, e.g. 429 or 503
+	Status int
+	// RetryAfter is sent as the Retry-After header of a failed response
+	RetryAfter time.Duration
+	// Times limits how many requests the fault applies to; zero means no limit
+	Times int
+	// Rate is the fraction of matching requests the fault applies to; zero means all
+	Rate float64
+}
+
+// InjectFault adds a fault; faults are tried in the order they were injected and
+// the first matching one applies
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes every injected fault
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the fault to apply to r, if any, counting it against its Times
+func (s *Server) matchFault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/apis")
+	for i, fault := range s.faults {
+		if fault.Method != "" && fault.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(path, fault.Path) {
+			continue
+		}
+		if fault.Rate > 0 && rand.Float64() >= fault.Rate {
+			continue
+		}
+		matched := *fault
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+// apply delays and fails the response as configured, returning whether the
+// request should still be served
+func (f *Fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f.Latency > 0 {
+		select {
+		case <-time.After(f.Latency):
+		case <-r.Context().Done():
+			return false
+		}
+	}
+	if f.Status == 0 {
+		return true
+	}
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Seconds())))
+	}
+	writeError(w, f.Status, http.StatusText(f.Status), "injected fault")
+	return false
+}
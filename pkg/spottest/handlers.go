@@ -0,0 +1,323 @@
+package spottest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// route is a parsed API request path
+type route struct {
+	apiVersion client.APIVersion
+	kind       *kind
+	namespace  string
+	name       string
+	// price is set instead of kind for the price-history and percentile-info endpoints
+	price string
+}
+
+// parseRoute parses /<group>/<version>/[namespaces/<namespace>/]<plural>[/<name>]
+func parseRoute(path string) (route, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 3 {
+		return route{}, fmt.Errorf("the server could not find the requested resource %s", path)
+	}
+	r := route{apiVersion: client.APIVersion(segments[0] + "/" + segments[1])}
+	rest := segments[2:]
+
+	if len(rest) == 1 && (rest[0] == "price-history" || rest[0] == "percentile-info") && r.apiVersion == client.APIVersionDefault {
+		r.price = rest[0]
+		return r, nil
+	}
+
+	if rest[0] == "namespaces" && len(rest) >= 3 {
+		r.namespace, rest = rest[1], rest[2:]
+	}
+	if len(rest) > 2 {
+		return route{}, fmt.Errorf("the server could not find the requested resource %s", path)
+	}
+	r.kind = kindByPlural(rest[0])
+	if r.kind == nil || r.kind.resource.APIVersion != r.apiVersion || r.kind.resource.Namespaced != (r.namespace != "") {
+		return route{}, fmt.Errorf("the server could not find the requested resource %s", path)
+	}
+	if len(rest) == 2 {
+		r.name = rest[1]
+	}
+	return r, nil
+}
+
+// serveResource dispatches an authorized API request
+func (s *Server) serveResource(w http.ResponseWriter, r *http.Request, rt route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advance()
+
+	if rt.price != "" {
+		s.servePrice(w, r, rt.price)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dryRun") == "All"
+	writable := rt.kind.resource.Namespaced
+	switch {
+	case r.Method == http.MethodGet && rt.name == "":
+		s.list(w, r, rt)
+	case r.Method == http.MethodGet:
+		s.get(w, rt)
+	case r.Method == http.MethodPost && rt.name == "" && writable:
+		s.create(w, r, rt, dryRun)
+	case r.Method == http.MethodPatch && rt.name != "" && writable:
+		s.patch(w, r, rt, dryRun)
+	case r.Method == http.MethodDelete && rt.name != "" && writable:
+		s.delete(w, rt, dryRun)
+	case r.Method == http.MethodDelete && writable:
+		s.deleteCollection(w, rt, dryRun)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("%s is not supported on %s", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, rt route) {
+	requirements, err := parseSelector(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	entries := s.stores[rt.kind.resource.Plural].list(rt.namespace)
+	items := make([]map[string]any, 0, len(entries))
+	for _, e := range entries {
+		if matchesSelector(e.object, requirements) {
+			items = append(items, e.object)
+		}
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if rt.kind.resource.Kind == client.OrganizationResource.Kind {
+		s.listOrganizations(w, r, items, limit)
+		return
+	}
+
+	start := 0
+	if token := r.URL.Query().Get("continue"); token != "" {
+		var err error
+		if start, err = strconv.Atoi(token); err != nil || start < 0 || start > len(items) {
+			writeError(w, http.StatusGone, "Expired", "the provided continue parameter is too old or malformed")
+			return
+		}
+	}
+	end := len(items)
+	meta := map[string]any{}
+	if limit > 0 && start+limit < end {
+		end = start + limit
+		meta["continue"] = strconv.Itoa(end)
+		meta["remainingItemCount"] = len(items) - end
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"apiVersion": rt.kind.resource.APIVersion,
+		"kind":       rt.kind.resource.Kind + "List",
+		"metadata":   meta,
+		"items":      items[start:end],
+	})
+}
+
+// listOrganizations pages organizations by offset like the auth API does
+func (s *Server) listOrganizations(w http.ResponseWriter, r *http.Request, items []map[string]any, limit int) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+	start = min(max(start, 0), len(items))
+	end := len(items)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+
+	response := map[string]any{
+		"start":         start,
+		"limit":         limit,
+		"length":        end - start,
+		"total":         len(items),
+		"organizations": items[start:end],
+	}
+	if end < len(items) {
+		response["next"] = fmt.Sprintf("%s?start=%d&limit=%d", r.URL.Path, end, limit)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) get(w http.ResponseWriter, rt route) {
+	e, ok := s.stores[rt.kind.resource.Plural].get(rt.namespace, rt.name)
+	if !ok {
+		writeNotFound(w, rt)
+		return
+	}
+	writeJSON(w, http.StatusOK, e.object)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, rt route, dryRun bool) {
+	obj, ok := readObject(w, r, rt.kind)
+	if !ok {
+		return
+	}
+	meta := child(obj, "metadata")
+	name, _ := meta["name"].(string)
+	if name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid", fmt.Sprintf("%s: metadata.name is required", rt.kind.resource.Kind))
+		return
+	}
+	st := s.stores[rt.kind.resource.Plural]
+	if _, exists := st.get(rt.namespace, name); exists {
+		writeError(w, http.StatusConflict, "AlreadyExists", fmt.Sprintf("%s %q already exists", rt.kind.resource.Plural, name))
+		return
+	}
+
+	now := s.now()
+	s.uids++
+	meta["namespace"] = rt.namespace
+	meta["uid"] = fmt.Sprintf("spottest-uid-%d", s.uids)
+	meta["resourceVersion"] = "1"
+	meta["creationTimestamp"] = now.UTC().Format(time.RFC3339)
+	obj["metadata"] = meta
+	obj["apiVersion"] = rt.kind.resource.APIVersion
+	obj["kind"] = rt.kind.resource.Kind
+
+	e := &entry{object: obj, created: now}
+	if rt.kind.provisioning != nil {
+		rt.kind.provisioning(obj)
+	}
+	if s.provisionDelay == 0 && rt.kind.ready != nil {
+		e.ready, e.readyAt = true, now
+		rt.kind.ready(obj, now)
+	}
+	if !dryRun {
+		st.put(rt.namespace, name, e)
+	}
+	writeJSON(w, http.StatusCreated, obj)
+}
+
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, rt route, dryRun bool) {
+	e, ok := s.stores[rt.kind.resource.Plural].get(rt.namespace, rt.name)
+	if !ok {
+		writeNotFound(w, rt)
+		return
+	}
+
+	var patchOps []client.PatchOperation
+	if err := json.NewDecoder(r.Body).Decode(&patchOps); err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("invalid JSON patch: %v", err))
+		return
+	}
+	current, err := json.Marshal(e.object)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+		return
+	}
+	patched, err := client.ApplyPatch(current, patchOps)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid", err.Error())
+		return
+	}
+	if err := rt.kind.validate(patched); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid", fmt.Sprintf("patched %s is invalid: %v", rt.kind.resource.Kind, err))
+		return
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(patched, &obj); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid", err.Error())
+		return
+	}
+
+	meta, oldMeta := child(obj, "metadata"), child(e.object, "metadata")
+	if meta["name"] != oldMeta["name"] || meta["namespace"] != oldMeta["namespace"] {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid", "metadata.name and metadata.namespace cannot be changed")
+		return
+	}
+	version, _ := strconv.Atoi(fmt.Sprint(oldMeta["resourceVersion"]))
+	meta["resourceVersion"] = strconv.Itoa(version + 1)
+	obj["metadata"] = meta
+
+	// Ready objects reflect their new spec straight away, e.g. a changed node count
+	if e.ready && rt.kind.ready != nil {
+		rt.kind.ready(obj, e.readyAt)
+	}
+	if !dryRun {
+		e.object = obj
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) delete(w http.ResponseWriter, rt route, dryRun bool) {
+	st := s.stores[rt.kind.resource.Plural]
+	e, ok := st.get(rt.namespace, rt.name)
+	if !ok {
+		writeNotFound(w, rt)
+		return
+	}
+	if !dryRun {
+		s.markDeleted(st, rt.namespace, rt.name, e)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Status",
+		"status":     "Success",
+		"details":    map[string]any{"name": rt.name, "kind": rt.kind.resource.Plural, "uid": child(e.object, "metadata")["uid"]},
+	})
+}
+
+func (s *Server) deleteCollection(w http.ResponseWriter, rt route, dryRun bool) {
+	if !rt.kind.resource.Supports(client.VerbDeleteCollection) {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("%s does not support deletecollection", rt.kind.resource.Plural))
+		return
+	}
+	st := s.stores[rt.kind.resource.Plural]
+	if !dryRun {
+		for _, e := range st.list(rt.namespace) {
+			s.markDeleted(st, rt.namespace, child(e.object, "metadata")["name"].(string), e)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"apiVersion": "v1", "kind": "Status", "status": "Success"})
+}
+
+// markDeleted removes an object, or marks it as being deleted when deletion takes time
+func (s *Server) markDeleted(st *store, namespace, name string, e *entry) {
+	if s.deletionDelay == 0 {
+		st.remove(namespace, name)
+		return
+	}
+	if e.deleting {
+		return
+	}
+	e.deleting, e.deleted = true, s.now()
+	child(e.object, "metadata")["deletionTimestamp"] = e.deleted.UTC().Format(time.RFC3339)
+	if status, ok := e.object["status"].(map[string]any); ok && e.object["kind"] == client.CloudSpaceResource.Kind {
+		status["phase"] = "Deleting"
+	}
+}
+
+// readObject decodes a request body into a generic object after validating it against the kind's type
+func readObject(w http.ResponseWriter, r *http.Request, k *kind) (map[string]any, bool) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return nil, false
+	}
+	if err := k.validate(data); err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("invalid %s: %v", k.resource.Kind, err))
+		return nil, false
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil || obj == nil {
+		writeError(w, http.StatusBadRequest, "BadRequest", fmt.Sprintf("invalid %s: expected a JSON object", k.resource.Kind))
+		return nil, false
+	}
+	return obj, true
+}
+
+func writeNotFound(w http.ResponseWriter, rt route) {
+	writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("%s %q not found", rt.kind.resource.Plural, rt.name))
+}
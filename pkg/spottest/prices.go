@@ -0,0 +1,65 @@
+package spottest
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// priceHistoryPoints is the number of hourly auctions in a price history
+const priceHistoryPoints = 24
+
+// servePrice serves the price-history and percentile-info endpoints, deriving
+// prices from the server class's current hammer price. The caller holds s.mu.
+func (s *Server) servePrice(w http.ResponseWriter, r *http.Request, endpoint string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("%s is not supported on %s", r.Method, r.URL.Path))
+		return
+	}
+	name := r.URL.Query().Get("serverClass")
+	e, ok := s.stores["serverclasses"].get("", name)
+	if !ok {
+		writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("serverclasses %q not found", name))
+		return
+	}
+	base := basePrice(e.object)
+
+	if endpoint == "percentile-info" {
+		percentiles := map[string]string{}
+		for _, p := range []int{10, 25, 50, 75, 90} {
+			percentiles[strconv.Itoa(p)] = formatPrice(base * (0.8 + 0.4*float64(p)/100))
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"serverClass": name, "percentiles": percentiles})
+		return
+	}
+
+	// A deterministic wave around the base price, one auction per hour
+	now := s.now().UTC().Truncate(time.Hour)
+	history := make([]map[string]any, 0, priceHistoryPoints)
+	for i := priceHistoryPoints - 1; i >= 0; i-- {
+		timestamp := now.Add(-time.Duration(i) * time.Hour)
+		price := base * (1 + 0.15*math.Sin(float64(i)/3))
+		history = append(history, map[string]any{"timestamp": timestamp.Format(time.RFC3339), "price": formatPrice(price)})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"serverClass": name, "history": history})
+}
+
+// basePrice returns the hammer or market price of a server class, or a default
+func basePrice(serverClass map[string]any) float64 {
+	pricing := child(child(serverClass, "status"), "spotPricing")
+	for _, key := range []string{"hammerPricePerHour", "marketPricePerHour"} {
+		if value, ok := pricing[key].(string); ok {
+			if price, err := strconv.ParseFloat(strings.TrimPrefix(value, "$"), 64); err == nil && price > 0 {
+				return price
+			}
+		}
+	}
+	return 0.01
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(math.Round(price*10000)/10000, 'f', -1, 64)
+}
@@ -0,0 +1,168 @@
+package spottest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// kind describes how the fake stores and evolves one resource kind
+type kind struct {
+	resource client.Resource
+	// validate checks that a request body decodes into the kind's Go type
+	validate func(data []byte) error
+	// provisioning sets the status of a newly created object
+	provisioning func(obj map[string]any)
+	// ready sets the status of an object once it has been provisioned
+	ready func(obj map[string]any, readyAt time.Time)
+}
+
+// kinds served by the fake, in the order they are listed
+var kinds = []*kind{
+	{resource: client.RegionResource, validate: validateAs[client.Region]},
+	{resource: client.ServerClassResource, validate: validateAs[client.ServerClass]},
+	{resource: client.OrganizationResource, validate: validateAs[client.Organization]},
+	{
+		resource: client.CloudSpaceResource,
+		validate: validateAs[client.CloudSpace],
+		provisioning: func(obj map[string]any) {
+			obj["status"] = map[string]any{"phase": "Provisioning", "health": "Unknown"}
+		},
+		ready: func(obj map[string]any, readyAt time.Time) {
+			meta := child(obj, "metadata")
+			obj["status"] = map[string]any{
+				"phase":                    "Ready",
+				"health":                   "Healthy",
+				"APIServerEndpoint":        fmt.Sprintf("https://%s.%s.spottest.invalid", meta["name"], meta["namespace"]),
+				"currentKubernetesVersion": child(obj, "spec")["kubernetesVersion"],
+				"firstReadyTimestamp":      readyAt.UTC().Format(time.RFC3339),
+			}
+		},
+	},
+	{
+		resource: client.SpotNodePoolResource,
+		validate: validateAs[client.SpotNodePool],
+		provisioning: func(obj map[string]any) {
+			obj["status"] = map[string]any{"bidStatus": "Pending"}
+		},
+		ready: func(obj map[string]any, readyAt time.Time) {
+			obj["status"] = map[string]any{"bidStatus": "Won", "wonCount": desired(obj)}
+		},
+	},
+	{
+		resource: client.OnDemandNodePoolResource,
+		validate: validateAs[client.OnDemandNodePool],
+		provisioning: func(obj map[string]any) {
+			obj["status"] = map[string]any{"reservedStatus": "Pending"}
+		},
+		ready: func(obj map[string]any, readyAt time.Time) {
+			obj["status"] = map[string]any{"reservedStatus": "Reserved", "reservedCount": desired(obj)}
+		},
+	},
+}
+
+// kindByPlural returns the kind served under plural, if any
+func kindByPlural(plural string) *kind {
+	for _, k := range kinds {
+		if k.resource.Plural == plural {
+			return k
+		}
+	}
+	return nil
+}
+
+// validateAs checks that data decodes into T
+func validateAs[T any](data []byte) error {
+	var obj T
+	return json.Unmarshal(data, &obj)
+}
+
+// child returns the object stored under key, or an empty one
+func child(obj map[string]any, key string) map[string]any {
+	if value, ok := obj[key].(map[string]any); ok {
+		return value
+	}
+	return map[string]any{}
+}
+
+// desired returns the desired node count of a node pool
+func desired(obj map[string]any) any {
+	if value, ok := child(obj, "spec")["desired"]; ok {
+		return value
+	}
+	return 0
+}
+
+// entry is a stored object together with its lifecycle state
+type entry struct {
+	object   map[string]any
+	created  time.Time
+	ready    bool
+	readyAt  time.Time
+	deleting bool
+	deleted  time.Time
+}
+
+// store holds the objects of one kind keyed by namespace and name
+type store struct {
+	entries map[string]map[string]*entry
+}
+
+func newStore() *store {
+	return &store{entries: map[string]map[string]*entry{}}
+}
+
+func (st *store) get(namespace, name string) (*entry, bool) {
+	e, ok := st.entries[namespace][name]
+	return e, ok
+}
+
+func (st *store) put(namespace, name string, e *entry) {
+	if st.entries[namespace] == nil {
+		st.entries[namespace] = map[string]*entry{}
+	}
+	st.entries[namespace][name] = e
+}
+
+func (st *store) remove(namespace, name string) {
+	delete(st.entries[namespace], name)
+}
+
+// list returns the entries in a namespace ordered by name
+func (st *store) list(namespace string) []*entry {
+	names := make([]string, 0, len(st.entries[namespace]))
+	for name := range st.entries[namespace] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]*entry, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, st.entries[namespace][name])
+	}
+	return entries
+}
+
+// advance applies the status transitions due by now. The caller holds s.mu.
+func (s *Server) advance() {
+	now := s.now()
+	for _, k := range kinds {
+		st := s.stores[k.resource.Plural]
+		for namespace, entries := range st.entries {
+			for name, e := range entries {
+				if e.deleting && !now.Before(e.deleted.Add(s.deletionDelay)) {
+					st.remove(namespace, name)
+					continue
+				}
+				if !e.ready && k.ready != nil && !now.Before(e.created.Add(s.provisionDelay)) {
+					e.ready = true
+					e.readyAt = e.created.Add(s.provisionDelay)
+					k.ready(e.object, e.readyAt)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package spottest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// DefaultNamespace is the namespace of the organization added by SeedDefaults
+const DefaultNamespace = "org-spottest"
+
+// Seed stores objects as given, status included, replacing any with the same
+// name. Each object must be a Region, ServerClass, Organization, CloudSpace,
+// SpotNodePool or OnDemandNodePool, by value or pointer; namespaced objects are
+// stored in their Metadata.Namespace.
+func (s *Server) Seed(objs ...any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, obj := range objs {
+		k, namespace, name, err := identify(obj)
+		if err != nil {
+			return err
+		}
+		if k.resource.Namespaced && namespace == "" {
+			return fmt.Errorf("spottest: %s %q has no namespace", k.resource.Kind, name)
+		}
+		if name == "" {
+			return fmt.Errorf("spottest: %s has no name", k.resource.Kind)
+		}
+
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("spottest: failed to encode %s %q: %w", k.resource.Kind, name, err)
+		}
+		var stored map[string]any
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("spottest: failed to encode %s %q: %w", k.resource.Kind, name, err)
+		}
+		if k.resource.Kind != client.OrganizationResource.Kind {
+			stored["apiVersion"] = k.resource.APIVersion
+			stored["kind"] = k.resource.Kind
+		}
+		now := s.now()
+		s.stores[k.resource.Plural].put(namespace, name, &entry{object: stored, created: now, ready: true, readyAt: now})
+	}
+	return nil
+}
+
+// identify returns the kind, namespace and name of a seeded object
+func identify(obj any) (*kind, string, string, error) {
+	switch o := obj.(type) {
+	case *client.Region:
+		return kindByPlural("regions"), "", o.Metadata.Name, nil
+	case client.Region:
+		return identify(&o)
+	case *client.ServerClass:
+		return kindByPlural("serverclasses"), "", o.Metadata.Name, nil
+	case client.ServerClass:
+		return identify(&o)
+	case *client.Organization:
+		return kindByPlural("organizations"), "", o.Name, nil
+	case client.Organization:
+		return identify(&o)
+	case *client.CloudSpace:
+		return kindByPlural("cloudspaces"), o.Metadata.Namespace, o.Metadata.Name, nil
+	case client.CloudSpace:
+		return identify(&o)
+	case *client.SpotNodePool:
+		return kindByPlural("spotnodepools"), o.Metadata.Namespace, o.Metadata.Name, nil
+	case client.SpotNodePool:
+		return identify(&o)
+	case *client.OnDemandNodePool:
+		return kindByPlural("ondemandnodepools"), o.Metadata.Namespace, o.Metadata.Name, nil
+	case client.OnDemandNodePool:
+		return identify(&o)
+	default:
+		return nil, "", "", fmt.Errorf("spottest: unsupported object type %T", obj)
+	}
+}
+
+// SeedDefaults adds a small catalogue of regions and server classes and one
+// organization owning DefaultNamespace, which is enough to create cloudspaces
+// and node pools against
+func (s *Server) SeedDefaults() {
+	regions := []struct{ name, country, description string }{
+		{"us-central-dfw-1", "USA", "Dallas, Texas"},
+		{"us-east-iad-1", "USA", "Ashburn, Virginia"},
+		{"uk-lon-1", "UK", "London"},
+	}
+	serverClasses := []struct{ name, region, category, cpu, memory, onDemand, hammer string }{
+		{"gp.vs1.medium-dfw", "us-central-dfw-1", "General Purpose", "2", "3.75GB", "0.0632", "0.0015"},
+		{"gp.vs1.large-dfw", "us-central-dfw-1", "General Purpose", "4", "7.5GB", "0.1264", "0.003"},
+		{"ch.vs1.xlarge-iad", "us-east-iad-1", "Compute Heavy", "8", "15GB", "0.2528", "0.01"},
+		{"mh.vs1.large-lon", "uk-lon-1", "Memory Heavy", "4", "30GB", "0.1875", "0.008"},
+	}
+
+	var objs []any
+	for _, r := range regions {
+		objs = append(objs, client.Region{
+			Metadata: client.ObjectMeta{Name: r.name},
+			Spec: client.RegionSpec{
+				Country:     r.country,
+				Description: r.description,
+				Provider:    client.RegionProvider{ProviderRegionName: r.name, ProviderType: "ospc"},
+			},
+		})
+	}
+	for _, sc := range serverClasses {
+		available, capacity := 40, 50
+		objs = append(objs, client.ServerClass{
+			Metadata: client.ObjectMeta{Name: sc.name},
+			Spec: client.ServerClassSpec{
+				Availability:    "available",
+				Category:        sc.category,
+				DisplayName:     sc.name,
+				FlavorType:      "vm",
+				OnDemandPricing: client.ServerClassPricing{Cost: sc.onDemand, Interval: "hour"},
+				Provider:        client.ServerClassProvider{ProviderFlavorID: sc.name, ProviderType: "ospc"},
+				Region:          sc.region,
+				Resources:       client.ServerClassResources{CPU: sc.cpu, Memory: sc.memory},
+			},
+			Status: client.ServerClassStatus{
+				Available:   &available,
+				Capacity:    &capacity,
+				SpotPricing: client.ServerClassSpotPricing{HammerPricePerHour: sc.hammer, MarketPricePerHour: sc.hammer},
+			},
+		})
+	}
+	objs = append(objs, client.Organization{
+		ID:          "org_spottest",
+		Name:        "spottest",
+		DisplayName: "Spot Test",
+		Metadata:    client.OrganizationMetadata{Namespace: DefaultNamespace},
+	})
+
+	if err := s.Seed(objs...); err != nil {
+		panic(err)
+	}
+}
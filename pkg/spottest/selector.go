@@ -0,0 +1,61 @@
+package spottest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// requirement is one comma separated term of a label selector
+type requirement struct {
+	key    string
+	value  string
+	negate bool
+	// exists is true for the bare key and !key forms, which ignore value
+	exists bool
+}
+
+// parseSelector parses an equality based label selector such as env=dev,tier!=web,!legacy
+func parseSelector(selector string) ([]requirement, error) {
+	var requirements []requirement
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		var req requirement
+		switch {
+		case term == "":
+			continue
+		case strings.Contains(term, "!="):
+			req.key, req.value, _ = strings.Cut(term, "!=")
+			req.negate = true
+		case strings.Contains(term, "=="):
+			req.key, req.value, _ = strings.Cut(term, "==")
+		case strings.Contains(term, "="):
+			req.key, req.value, _ = strings.Cut(term, "=")
+		case strings.HasPrefix(term, "!"):
+			req.key, req.negate, req.exists = term[1:], true, true
+		default:
+			req.key, req.exists = term, true
+		}
+		req.key, req.value = strings.TrimSpace(req.key), strings.TrimSpace(req.value)
+		if req.key == "" {
+			return nil, fmt.Errorf("invalid label selector %q", selector)
+		}
+		requirements = append(requirements, req)
+	}
+	return requirements, nil
+}
+
+// matchesSelector reports whether an object's labels satisfy every requirement
+func matchesSelector(obj map[string]any, requirements []requirement) bool {
+	labels := child(child(obj, "metadata"), "labels")
+	for _, req := range requirements {
+		value, ok := labels[req.key].(string)
+		matched := ok
+		if !req.exists {
+			matched = ok && value == req.value
+		}
+		if matched == req.negate {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,300 @@
+// Package spottest provides a stateful in-memory fake of the Rackspace Spot API,
+// including the OAuth token endpoint, for tests and offline development.
+//
+// In tests:
+//
+//	server := spottest.NewTestServer(t)
+//	c := client.NewClient(server.Config())
+//
+// Interactively, run 'spotctl dev-server' and point spotctl at the printed URLs.
+package spottest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// DefaultRefreshToken is the refresh token accepted by a server created without WithRefreshToken
+const DefaultRefreshToken = "spottest-refresh-token"
+
+// DefaultSubject and DefaultEmail identify the user in issued ID tokens
+const (
+	DefaultSubject = "spottest|developer"
+	DefaultEmail   = "developer@spottest.invalid"
+)
+
+// Server is a fake Spot API. It is an http.Handler serving the API, including
+// Kubernetes style discovery, under both / and /apis, the OAuth token endpoint
+// at /oauth/token and the device authorization endpoint at /oauth/device/code,
+// with a page at /activate to approve device logins. It is safe for concurrent use.
+type Server struct {
+	mu sync.Mutex
+
+	refreshToken   string
+	tokenLifetime  time.Duration
+	tokens         map[string]bool
+	issued         int
+	devices        map[string]*deviceLogin
+	deviceCodes    int
+	deviceInterval time.Duration
+	provisionDelay time.Duration
+	deletionDelay  time.Duration
+	now            func() time.Time
+
+	// rotateRefreshTokens replaces the accepted refresh token on every exchange
+	rotateRefreshTokens bool
+	rotations           int
+
+	stores map[string]*store
+	faults []*Fault
+	uids   int
+
+	// noDiscovery disables the discovery endpoints
+	noDiscovery bool
+
+	// requests counts API requests, those to the OAuth endpoints excepted
+	requests atomic.Int64
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithRefreshToken sets the only refresh token the token endpoint accepts
+func WithRefreshToken(refreshToken string) Option {
+	return func(s *Server) {
+		s.refreshToken = refreshToken
+	}
+}
+
+// WithAccessToken makes the API accept token as a bearer token without it being
+// issued by the token endpoint, e.g. for clients using client.StaticTokenSource
+func WithAccessToken(token string) Option {
+	return func(s *Server) {
+		s.tokens[token] = true
+	}
+}
+
+// WithRefreshTokenRotation makes every refresh token exchange return a new
+// refresh token and stop accepting the old one, like authorization servers with
+// refresh token rotation enabled
+func WithRefreshTokenRotation() Option {
+	return func(s *Server) {
+		s.rotateRefreshTokens = true
+	}
+}
+
+// Requests returns how many API requests the server has received, not counting
+// those to the OAuth and device login endpoints, e.g. to check that a cache works
+func (s *Server) Requests() int {
+	return int(s.requests.Load())
+}
+
+// RefreshToken returns the refresh token the token endpoint currently accepts
+func (s *Server) RefreshToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.refreshToken
+}
+
+// WithTokenLifetime sets the expires_in of issued tokens
+func WithTokenLifetime(lifetime time.Duration) Option {
+	return func(s *Server) {
+		s.tokenLifetime = lifetime
+	}
+}
+
+// WithProvisionDelay sets how long created cloudspaces and node pools take to
+// become ready. With no delay they are ready immediately.
+func WithProvisionDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.provisionDelay = delay
+	}
+}
+
+// WithDeletionDelay sets how long deleted objects remain visible, with a deletion
+// timestamp, before they disappear. With no delay they are removed immediately.
+func WithDeletionDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.deletionDelay = delay
+	}
+}
+
+// WithClock sets the source of the current time used for status transitions
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// NewServer returns an empty fake API; use Seed or SeedDefaults to add objects
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		refreshToken:   DefaultRefreshToken,
+		tokenLifetime:  time.Hour,
+		tokens:         map[string]bool{},
+		devices:        map[string]*deviceLogin{},
+		deviceInterval: 5 * time.Second,
+		now:            time.Now,
+		stores:         map[string]*store{},
+	}
+	for _, k := range kinds {
+		s.stores[k.resource.Plural] = newStore()
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if fault := s.matchFault(r); fault != nil {
+		if !fault.apply(w, r) {
+			return
+		}
+	}
+
+	switch r.URL.Path {
+	case "/oauth/token":
+		s.serveToken(w, r)
+		return
+	case "/oauth/device/code":
+		s.serveDeviceCode(w, r)
+		return
+	case "/activate":
+		s.serveActivate(w, r)
+		return
+	}
+	s.requests.Add(1)
+
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "Unauthorized", "a valid bearer token is required")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/apis")
+	if s.serveDiscovery(w, r, path) {
+		return
+	}
+	route, err := parseRoute(path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NotFound", err.Error())
+		return
+	}
+	s.serveResource(w, r, route)
+}
+
+// serveToken implements the OAuth refresh token grant
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "token requests must use POST")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+	switch r.PostForm.Get("grant_type") {
+	case client.GrantType:
+	case client.DeviceCodeGrantType:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.redeemDeviceCode(w, r.PostForm.Get("device_code"))
+		return
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	// The refresh token is checked under the lock, as rotation replaces it
+	s.mu.Lock()
+	if r.PostForm.Get("refresh_token") != s.refreshToken {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "invalid_grant", "error_description": "unknown or invalid refresh token"})
+		return
+	}
+	token := s.issueToken()
+	rotated := ""
+	if s.rotateRefreshTokens {
+		s.rotations++
+		s.refreshToken = fmt.Sprintf("spottest-refresh-token-%d", s.rotations)
+		rotated = s.refreshToken
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, client.TokenResponse{
+		AccessToken:  token,
+		IDToken:      token,
+		RefreshToken: rotated,
+		ExpiresIn:    int(s.tokenLifetime.Seconds()),
+		TokenType:    "Bearer",
+	})
+}
+
+// issueToken issues and accepts a new ID token. The caller holds s.mu.
+func (s *Server) issueToken() string {
+	s.issued++
+	token := s.idToken(s.issued)
+	s.tokens[token] = true
+	return token
+}
+
+// idToken returns an unsigned JWT for the fake user; n makes every issued token unique.
+// The caller holds s.mu.
+func (s *Server) idToken(n int) string {
+	now := s.now()
+	claims, _ := json.Marshal(map[string]any{
+		"iss":   "spottest",
+		"sub":   DefaultSubject,
+		"email": DefaultEmail,
+		"iat":   now.Unix(),
+		"exp":   now.Add(s.tokenLifetime).Unix(),
+		"jti":   fmt.Sprintf("spottest-token-%d", n),
+	})
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + encode(claims) + "."
+}
+
+// authorized reports whether the request carries a token the server accepts
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[token]
+}
+
+// RevokeTokens invalidates every issued access token, as if they had expired
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]bool{}
+}
+
+// writeJSON writes v as a JSON response with status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a Kubernetes style status error, which the client reads as an API error
+func writeError(w http.ResponseWriter, status int, reason, message string) {
+	writeJSON(w, status, map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Status",
+		"status":     "Failure",
+		"code":       status,
+		"reason":     reason,
+		"message":    message,
+	})
+}
@@ -0,0 +1,431 @@
+package spottest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georgetaylor/spotctl/pkg/client"
+)
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func isStatus(err error, code int) bool {
+	var apiErr *client.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+func TestServer_CloudSpaceLifecycle(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	server := NewTestServer(t, WithProvisionDelay(5*time.Minute), WithDeletionDelay(time.Minute), WithClock(clock.Now))
+	c := client.NewClient(server.Config())
+	ctx := context.Background()
+
+	cloudSpace := &client.CloudSpace{
+		Metadata: client.ObjectMeta{Name: "dev"},
+		Spec:     client.CloudSpaceSpec{Region: "us-central-dfw-1", KubernetesVersion: "1.31.1"},
+	}
+	created, err := c.CreateCloudSpace(ctx, DefaultNamespace, cloudSpace)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.Status.Phase != "Provisioning" || created.Metadata.UID == "" {
+		t.Errorf("Expected a provisioning cloudspace with a UID, got %+v", created)
+	}
+	if _, err := c.CreateCloudSpace(ctx, DefaultNamespace, cloudSpace); !isStatus(err, http.StatusConflict) {
+		t.Errorf("Expected a conflict creating a duplicate, got %v", err)
+	}
+
+	clock.Advance(5 * time.Minute)
+	ready, err := c.GetCloudSpace(ctx, DefaultNamespace, "dev")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if ready.Status.Phase != "Ready" || ready.Status.CurrentKubernetesVersion != "1.31.1" {
+		t.Errorf("Expected the cloudspace to be ready after the provision delay, got %+v", ready.Status)
+	}
+
+	edited, err := c.EditCloudSpace(ctx, DefaultNamespace, "dev", []client.PatchOperation{{Op: "add", Path: "/spec/cni", Value: "cilium"}})
+	if err != nil {
+		t.Fatalf("Edit failed: %v", err)
+	}
+	if edited.Spec.CNI != "cilium" || edited.Metadata.ResourceVersion != "2" {
+		t.Errorf("Expected the patch to apply and bump the resource version, got %+v", edited)
+	}
+	if _, err := c.EditCloudSpace(ctx, DefaultNamespace, "dev", []client.PatchOperation{{Op: "replace", Path: "/metadata/name", Value: "other"}}); !isStatus(err, http.StatusUnprocessableEntity) {
+		t.Errorf("Expected renaming to be rejected, got %v", err)
+	}
+
+	if _, err := c.DeleteCloudSpace(ctx, DefaultNamespace, "dev"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	deleting, err := c.GetCloudSpace(ctx, DefaultNamespace, "dev")
+	if err != nil {
+		t.Fatalf("Expected the cloudspace to remain during the deletion delay: %v", err)
+	}
+	if deleting.Metadata.DeletionTimestamp == nil || deleting.Status.Phase != "Deleting" {
+		t.Errorf("Expected a deleting cloudspace, got %+v", deleting)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := c.GetCloudSpace(ctx, DefaultNamespace, "dev"); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("Expected not found after the deletion delay, got %v", err)
+	}
+}
+
+func TestServer_NodePoolsAndDryRun(t *testing.T) {
+	server := NewTestServer(t)
+	c := client.NewClient(server.Config())
+	ctx := context.Background()
+
+	desired := 2
+	pool := &client.SpotNodePool{
+		Metadata: client.ObjectMeta{Name: "workers"},
+		Spec:     client.SpotNodePoolSpec{CloudSpace: "dev", ServerClass: "gp.vs1.medium-dfw", BidPrice: "0.01", Desired: &desired},
+	}
+	if _, err := c.WithDryRun().CreateSpotNodePool(ctx, DefaultNamespace, pool); err != nil {
+		t.Fatalf("Dry run create failed: %v", err)
+	}
+	if list, _ := c.ListSpotNodePools(ctx, DefaultNamespace); len(list.Items) != 0 {
+		t.Fatalf("Expected a dry run to store nothing, got %d pools", len(list.Items))
+	}
+
+	created, err := c.CreateSpotNodePool(ctx, DefaultNamespace, pool)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.Status.BidStatus != "Won" || created.Status.WonCount == nil || *created.Status.WonCount != 2 {
+		t.Errorf("Expected a won bid for 2 nodes without a provision delay, got %+v", created.Status)
+	}
+
+	edited, err := c.EditSpotNodePool(ctx, DefaultNamespace, "workers", []client.PatchOperation{{Op: "replace", Path: "/spec/desired", Value: 3}})
+	if err != nil {
+		t.Fatalf("Edit failed: %v", err)
+	}
+	if *edited.Status.WonCount != 3 {
+		t.Errorf("Expected the won count to follow the desired count, got %d", *edited.Status.WonCount)
+	}
+
+	if _, err := c.DeleteAllSpotNodePools(ctx, DefaultNamespace); err != nil {
+		t.Fatalf("Delete all failed: %v", err)
+	}
+	if list, _ := c.ListSpotNodePools(ctx, DefaultNamespace); len(list.Items) != 0 {
+		t.Errorf("Expected no pools after deleting all, got %d", len(list.Items))
+	}
+}
+
+func TestServer_ReadOnlyKindsAndPagination(t *testing.T) {
+	server := NewTestServer(t)
+	c := client.NewClient(server.Config())
+	ctx := context.Background()
+
+	var names []string
+	for region, err := range c.AllRegions(ctx, client.ListOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("AllRegions failed: %v", err)
+		}
+		names = append(names, region.Metadata.Name)
+	}
+	if len(names) != 3 || names[0] != "uk-lon-1" {
+		t.Errorf("Expected 3 regions across pages, got %v", names)
+	}
+
+	orgs, err := client.Collect(c.AllOrganizations(ctx, client.ListOptions{Limit: 1}), 0)
+	if err != nil || len(orgs) != 1 || orgs[0].Metadata.Namespace != DefaultNamespace {
+		t.Errorf("Expected the default organization, got %v (%v)", orgs, err)
+	}
+
+	history, err := c.GetPriceHistory(ctx, "gp.vs1.medium-dfw")
+	if err != nil || len(history.History) != priceHistoryPoints {
+		t.Errorf("Expected %d price points, got %v (%v)", priceHistoryPoints, history, err)
+	}
+	if _, err := c.GetPercentileInfo(ctx, "missing"); !isStatus(err, http.StatusNotFound) {
+		t.Errorf("Expected not found for an unknown server class, got %v", err)
+	}
+
+	resp, err := c.Post(ctx, "/regions", map[string]any{"metadata": map[string]string{"name": "new"}})
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !isStatus(err, http.StatusMethodNotAllowed) {
+		t.Errorf("Expected regions to be read only, got %v", err)
+	}
+}
+
+func TestServer_LabelSelector(t *testing.T) {
+	server := NewTestServer(t)
+	c := client.NewClient(server.Config())
+	ctx := context.Background()
+
+	pool := func(name string, labels map[string]string) client.SpotNodePool {
+		return client.SpotNodePool{Metadata: client.ObjectMeta{Name: name, Namespace: DefaultNamespace, Labels: labels}}
+	}
+	if err := server.Seed(
+		pool("dev-web", map[string]string{"env": "dev", "tier": "web"}),
+		pool("dev-db", map[string]string{"env": "dev", "tier": "db"}),
+		pool("prod-web", map[string]string{"env": "prod", "tier": "web"}),
+		pool("unlabelled", nil),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{selector: "env=dev", want: []string{"dev-db", "dev-web"}},
+		{selector: "env==dev,tier!=web", want: []string{"dev-db"}},
+		{selector: "tier!=db", want: []string{"dev-web", "prod-web", "unlabelled"}},
+		{selector: "env", want: []string{"dev-db", "dev-web", "prod-web"}},
+		{selector: "!env", want: []string{"unlabelled"}},
+	}
+	for _, tt := range tests {
+		pools, err := client.Collect(c.AllSpotNodePools(ctx, DefaultNamespace, client.ListOptions{LabelSelector: tt.selector}), 0)
+		if err != nil {
+			t.Fatalf("List with %q failed: %v", tt.selector, err)
+		}
+		var names []string
+		for _, p := range pools {
+			names = append(names, p.Metadata.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(tt.want) {
+			t.Errorf("Selector %q matched %v, want %v", tt.selector, names, tt.want)
+		}
+	}
+
+	if _, err := c.ListSpotNodePools(ctx, DefaultNamespace); err != nil {
+		t.Fatalf("List without a selector failed: %v", err)
+	}
+	if _, err := client.Collect(c.AllSpotNodePools(ctx, DefaultNamespace, client.ListOptions{LabelSelector: "=dev"}), 0); !isStatus(err, http.StatusBadRequest) {
+		t.Errorf("Expected an invalid selector to be rejected, got %v", err)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	server := NewTestServer(t)
+	cfg := server.Config()
+	cfg.RefreshToken = "wrong"
+
+	if _, err := client.NewClient(cfg).ListRegions(context.Background()); err == nil {
+		t.Error("Expected an unknown refresh token to be rejected")
+	}
+
+	token, err := client.NewClient(server.Config()).AccessToken(context.Background())
+	if err != nil {
+		t.Fatalf("Token exchange failed: %v", err)
+	}
+	claims, err := client.ParseIDToken(token)
+	if err != nil {
+		t.Fatalf("Issued token is not a JWT: %v", err)
+	}
+	if claims.Subject != DefaultSubject || claims.Email != DefaultEmail || claims.ExpiresAt().IsZero() {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	c := client.NewClient(server.Config(), client.WithTokenSource(client.StaticTokenSource("not-issued")))
+	if _, err := c.ListRegions(context.Background()); !isStatus(err, http.StatusUnauthorized) {
+		t.Errorf("Expected a token the server did not issue to be rejected, got %v", err)
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	server := NewTestServer(t)
+	server.InjectFault(Fault{Method: http.MethodGet, Path: "/ngpc.rxt.io/v1/regions", Status: http.StatusServiceUnavailable, Times: 2})
+	ctx := context.Background()
+
+	if _, err := client.NewClient(server.Config()).ListRegions(ctx); !isStatus(err, http.StatusServiceUnavailable) {
+		t.Fatalf("Expected the injected 503, got %v", err)
+	}
+
+	// The second injected failure is retried past
+	c := client.NewClient(server.Config(), client.WithRetry(2, time.Millisecond))
+	if _, err := c.ListRegions(ctx); err != nil {
+		t.Fatalf("Expected a retry to succeed, got %v", err)
+	}
+
+	server.InjectFault(Fault{Latency: 50 * time.Millisecond})
+	start := time.Now()
+	if _, err := c.ListServerClasses(ctx); err != nil {
+		t.Fatalf("ListServerClasses failed: %v", err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Error("Expected the injected latency")
+	}
+}
+
+func TestServer_CancelSlowRequests(t *testing.T) {
+	server := NewTestServer(t)
+	server.InjectFault(Fault{Path: "/ngpc.rxt.io/v1/regions", Latency: 10 * time.Second})
+	c := client.NewClient(server.Config(), client.WithRetry(3, time.Millisecond))
+
+	// Canceling the context, as Ctrl-C does, aborts the in-flight request without retrying
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := c.ListRegions(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the request to be canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the request to stop when canceled, took %v", elapsed)
+	}
+
+	// request-timeout bounds each request
+	cfg := server.Config()
+	cfg.RequestTimeout = 50 * time.Millisecond
+	start = time.Now()
+	if _, err := client.NewClient(cfg).ListRegions(context.Background()); err == nil {
+		t.Error("Expected the request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the request timeout to apply, took %v", elapsed)
+	}
+}
+
+func TestServer_DeviceLogin(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	server := NewTestServer(t, WithClock(clock.Now), WithDeviceInterval(5*time.Second))
+
+	resp, err := http.PostForm(server.URL+"/oauth/device/code", url.Values{"client_id": {client.ClientID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var auth client.DeviceAuthorization
+	json.NewDecoder(resp.Body).Decode(&auth)
+	resp.Body.Close()
+	if auth.DeviceCode == "" || auth.UserCode == "" || auth.Interval != 5 {
+		t.Fatalf("Unexpected device authorization %+v", auth)
+	}
+
+	poll := func() (int, map[string]any) {
+		resp, err := http.PostForm(server.URL+"/oauth/token", url.Values{
+			"grant_type":  {client.DeviceCodeGrantType},
+			"device_code": {auth.DeviceCode},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body map[string]any
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	if _, body := poll(); body["error"] != "authorization_pending" {
+		t.Errorf("Expected authorization_pending, got %v", body)
+	}
+	if _, body := poll(); body["error"] != "slow_down" {
+		t.Errorf("Expected slow_down when polling too fast, got %v", body)
+	}
+
+	if server.ApproveDevice("WRONG-CODE") {
+		t.Error("Expected an unknown user code not to be approved")
+	}
+	if !server.ApproveDevice(auth.UserCode) {
+		t.Fatal("Expected the pending login to be approved")
+	}
+	clock.Advance(5 * time.Second)
+	status, body := poll()
+	if status != http.StatusOK || body["refresh_token"] != DefaultRefreshToken {
+		t.Fatalf("Expected the refresh token after approval, got %d %v", status, body)
+	}
+	if _, err := client.ParseIDToken(body["id_token"].(string)); err != nil {
+		t.Errorf("Expected an ID token: %v", err)
+	}
+
+	clock.Advance(5 * time.Second)
+	if _, body := poll(); body["error"] != "invalid_grant" {
+		t.Errorf("Expected a redeemed device code to be rejected, got %v", body)
+	}
+}
+
+func TestServer_RefreshTokenRotationAndRevocation(t *testing.T) {
+	server := NewTestServer(t, WithRefreshTokenRotation())
+
+	var saved []string
+	cfg := server.Config()
+	cfg.StoreRefreshToken = func(refreshToken string) error {
+		saved = append(saved, refreshToken)
+		return nil
+	}
+	c := client.NewClient(cfg)
+
+	if _, err := c.ListRegions(context.Background()); err != nil {
+		t.Fatalf("ListRegions failed: %v", err)
+	}
+	if len(saved) != 1 || saved[0] != server.RefreshToken() {
+		t.Fatalf("Expected the rotated refresh token %q to be saved, got %v", server.RefreshToken(), saved)
+	}
+
+	// A revoked access token is replaced with one from the rotated refresh token
+	server.RevokeTokens()
+	if _, err := c.ListRegions(context.Background()); err != nil {
+		t.Fatalf("Expected the client to recover from a revoked token, got %v", err)
+	}
+	if len(saved) != 2 || saved[1] != server.RefreshToken() {
+		t.Errorf("Expected a second rotation to be saved, got %v", saved)
+	}
+
+	// The original refresh token no longer works
+	cfg = server.Config()
+	cfg.RefreshToken = DefaultRefreshToken
+	if _, err := client.NewClient(cfg).ListRegions(context.Background()); err == nil {
+		t.Error("Expected the rotated-out refresh token to be rejected")
+	}
+}
+
+func TestServer_ConcurrentTokenExchanges(t *testing.T) {
+	server := NewTestServer(t, WithRefreshTokenRotation())
+
+	// Only one exchange can use the refresh token before it is rotated; run with -race
+	var wg sync.WaitGroup
+	statuses := make([]int, 8)
+	for i := range statuses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.PostForm(server.URL+"/oauth/token", url.Values{
+				"grant_type":    {client.GrantType},
+				"refresh_token": {DefaultRefreshToken},
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			statuses[i] = resp.StatusCode
+		}()
+	}
+	wg.Wait()
+
+	ok := 0
+	for _, status := range statuses {
+		if status == http.StatusOK {
+			ok++
+		}
+	}
+	if ok != 1 {
+		t.Errorf("Expected exactly one exchange to succeed, got statuses %v", statuses)
+	}
+}
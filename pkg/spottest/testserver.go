@@ -0,0 +1,54 @@
+package spottest
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/georgetaylor/spotctl/pkg/config"
+	"github.com/spf13/viper"
+)
+
+// TestServer is a Server listening on a local port for the duration of a test
+type TestServer struct {
+	*Server
+	// URL is the root URL of the server, e.g. http://127.0.0.1:41234
+	URL string
+}
+
+// NewTestServer starts a Server seeded with the default catalogue and stops it
+// when the test ends
+func NewTestServer(t testing.TB, opts ...Option) *TestServer {
+	t.Helper()
+	s := NewServer(opts...)
+	s.SeedDefaults()
+
+	httpServer := httptest.NewServer(s)
+	t.Cleanup(httpServer.Close)
+	return &TestServer{Server: s, URL: httpServer.URL}
+}
+
+// Config returns a client configuration pointing at the server, with the
+// refresh token it currently accepts
+func (ts *TestServer) Config() *config.Config {
+	return &config.Config{
+		RefreshToken: ts.RefreshToken(),
+		BaseURL:      ts.URL + "/apis",
+		OAuthURL:     ts.URL + "/oauth/token",
+		Namespace:    DefaultNamespace,
+		Timeout:      30,
+	}
+}
+
+// Configure points the global config at the server for the rest of the test, as
+// if its URLs and refresh token were in the config file, and gives the test a
+// cache directory of its own
+func (ts *TestServer) Configure(t testing.TB) {
+	t.Helper()
+	cfg := ts.Config()
+	viper.Reset()
+	viper.Set("refresh-token", cfg.RefreshToken)
+	viper.Set("base-url", cfg.BaseURL)
+	viper.Set("oauth-url", cfg.OAuthURL)
+	t.Cleanup(viper.Reset)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+}